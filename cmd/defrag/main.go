@@ -0,0 +1,142 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// defrag consolidates small preemptible or checkpointable GPU pods to recreate pristine
+// NVSwitch islands. By default it prints the plan without evicting anything; --dry-run=false
+// evicts the planned pods in rate-limited waves. With --interval it runs as a controller,
+// planning again after each interval.
+//
+//	defrag
+//	defrag --dry-run=false --max-islands=2 --wave-size=4 --wave-interval=2m
+//	defrag --dry-run=false --interval=30m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/defrag"
+)
+
+var (
+	kubeconfig   string
+	dryRun       bool
+	interval     time.Duration
+	maxPodGPUs   int
+	maxIslands   int
+	waveSize     int
+	waveInterval time.Duration
+)
+
+func init() {
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig (default: KUBECONFIG, ~/.kube/config or in-cluster)")
+	flag.BoolVar(&dryRun, "dry-run", true, "Print the plan without evicting pods")
+	flag.DurationVar(&interval, "interval", 0, "Plan again after this interval; 0 plans once and exits")
+	flag.IntVar(&maxPodGPUs, "max-pod-gpus", defrag.DefaultMaxPodGPUs, "Largest GPU request of a pod that may be moved")
+	flag.IntVar(&maxIslands, "max-islands", 1, "Islands freed per plan, 0 for no limit")
+	flag.IntVar(&waveSize, "wave-size", defrag.DefaultWaveSize, "Pods evicted per wave")
+	flag.DurationVar(&waveInterval, "wave-interval", defrag.DefaultWaveInterval, "Pause between eviction waves")
+	klog.InitFlags(nil)
+}
+
+func main() {
+	flag.Parse()
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx); err != nil {
+		klog.ErrorS(err, "Defragmentation failed")
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	evictor := &defrag.Evictor{Client: client, WaveSize: waveSize, WaveInterval: waveInterval}
+	if !dryRun {
+		// A pass killed mid-island leaves its node tainted
+		if err := evictor.ClearTaints(ctx); err != nil {
+			return err
+		}
+	}
+	for {
+		if err := defragment(ctx, client, evictor); err != nil {
+			if interval == 0 {
+				return err
+			}
+			klog.ErrorS(err, "Defragmentation pass failed")
+		}
+		if interval == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// defragment plans one pass over the cluster, prints the plan and, unless dry-running,
+// evicts the planned pods
+func defragment(ctx context.Context, client kubernetes.Interface, evictor *defrag.Evictor) error {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	plan := defrag.BuildPlan(ptrs(nodes.Items), ptrs(pods.Items), defrag.Options{MaxPodGPUs: maxPodGPUs, MaxIslands: maxIslands})
+	fmt.Print(plan)
+	if dryRun || len(plan.Islands) == 0 {
+		return nil
+	}
+
+	evicted, err := evictor.Execute(ctx, plan)
+	fmt.Printf("evicted %d of %d pod(s)\n", evicted, len(plan.Migrations()))
+	return err
+}
+
+func ptrs[T any](items []T) []*T {
+	out := make([]*T, len(items))
+	for i := range items {
+		out[i] = &items[i]
+	}
+	return out
+}
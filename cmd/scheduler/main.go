@@ -1,8 +1,13 @@
 package main
 
 import (
+	"errors"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
 	klog "k8s.io/klog/v2"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 
@@ -21,6 +26,27 @@ import (
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/workloadaware"
 )
 
+// defaultDebugAddress is the scheduler Service's metrics port
+const defaultDebugAddress = ":10251"
+
+// serveDebug serves the KubeNexus metrics and /debug/fragmentation. kube-scheduler's
+// own metrics stay on its secure port.
+func serveDebug(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle(resourcefragmentation.DebugFragmentationPath, resourcefragmentation.DebugHandler())
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	klog.InfoS("Serving KubeNexus debug endpoints", "address", address)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.ErrorS(err, "KubeNexus debug server failed", "address", address)
+	}
+}
+
 func main() {
 	klog.InfoS("KubeNexus Scheduler starting", "version", "v0.1.0")
 
@@ -46,6 +72,18 @@ func main() {
 		app.WithPlugin(preemption.Name, preemption.New),
 	)
 
+	// Serve the KubeNexus debug endpoints once flags are parsed
+	var debugAddress string
+	command.Flags().StringVar(&debugAddress, "kubenexus-debug-address", defaultDebugAddress,
+		"Listen address of /metrics and /debug/fragmentation; empty disables them")
+	runE := command.RunE
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		if debugAddress != "" {
+			go serveDebug(debugAddress)
+		}
+		return runE(cmd, args)
+	}
+
 	klog.InfoS("Executing scheduler command")
 	if err := command.Execute(); err != nil {
 		klog.ErrorS(err, "Scheduler command failed")
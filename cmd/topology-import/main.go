@@ -0,0 +1,260 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// topology-import converts a Slurm topology.conf or an ibnetdiscover dump into the node
+// labels or the NetworkTopology object read by NetworkFabricScore. By default it prints
+// a diff against the cluster; --apply writes the changes.
+//
+//	ibnetdiscover > fabric.txt
+//	topology-import --format=ibnetdiscover --input=fabric.txt --output=labels
+//	topology-import --format=slurm --input=topology.conf --output=crd --name=ib-fabric --apply
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/topologyimport"
+)
+
+var (
+	format     string
+	input      string
+	output     string
+	name       string
+	fabricType string
+	az         string
+	kubeconfig string
+	offline    bool
+	apply      bool
+)
+
+func init() {
+	flag.StringVar(&format, "format", "", "Input format: slurm (topology.conf) or ibnetdiscover")
+	flag.StringVar(&input, "input", "-", "Input file, - for stdin")
+	flag.StringVar(&output, "output", "labels", "What to produce: labels (node labels) or crd (NetworkTopology object)")
+	flag.StringVar(&name, "name", "fabric", "NetworkTopology object name for --output=crd")
+	flag.StringVar(&fabricType, "fabric-type", "", "network.kubenexus.io/fabric-type label value (default infiniband for ibnetdiscover)")
+	flag.StringVar(&az, "az", "", "network.kubenexus.io/az label value for every node")
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig (default: KUBECONFIG, ~/.kube/config or in-cluster)")
+	flag.BoolVar(&offline, "offline", false, "Print the labels or object without contacting the cluster")
+	flag.BoolVar(&apply, "apply", false, "Write the changes to the cluster instead of only printing the diff")
+	klog.InitFlags(nil)
+}
+
+func main() {
+	flag.Parse()
+	if err := run(context.Background()); err != nil {
+		klog.ErrorS(err, "Topology import failed")
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	topology, err := readTopology()
+	if err != nil {
+		return err
+	}
+	if fabricType == "" && format == "ibnetdiscover" {
+		fabricType = "infiniband"
+	}
+
+	switch output {
+	case "labels":
+		desired := topology.NodeLabels(topologyimport.LabelOptions{FabricType: fabricType, AZ: az})
+		if offline {
+			return printLabels(desired)
+		}
+		config, err := restConfig()
+		if err != nil {
+			return err
+		}
+		client, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes client: %w", err)
+		}
+		return syncLabels(ctx, client, desired)
+	case "crd":
+		desired := topology.NetworkTopology(name)
+		if offline {
+			out, err := yaml.Marshal(desired)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(out)
+			return err
+		}
+		config, err := restConfig()
+		if err != nil {
+			return err
+		}
+		client, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+		return syncTopology(ctx, client, desired)
+	default:
+		return fmt.Errorf("unknown --output %q, want labels or crd", output)
+	}
+}
+
+func readTopology() (*topologyimport.Topology, error) {
+	var r io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	switch format {
+	case "slurm":
+		return topologyimport.ParseSlurmTopology(r)
+	case "ibnetdiscover":
+		return topologyimport.ParseIBNetDiscover(r)
+	default:
+		return nil, fmt.Errorf("unknown --format %q, want slurm or ibnetdiscover", format)
+	}
+}
+
+func restConfig() (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	config.Timeout = 30 * time.Second
+	return config, nil
+}
+
+// printLabels prints one "node key=value" line per label
+func printLabels(desired map[string]map[string]string) error {
+	hosts := make([]string, 0, len(desired))
+	for host := range desired {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		keys := make([]string, 0, len(desired[host]))
+		for key := range desired[host] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s %s=%s\n", host, key, desired[host][key])
+		}
+	}
+	return nil
+}
+
+// syncLabels prints the label diff against the cluster's nodes and applies it with --apply
+func syncLabels(ctx context.Context, client kubernetes.Interface, desired map[string]map[string]string) error {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	changes, missing := topologyimport.DiffNodeLabels(nodes.Items, desired)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	for _, host := range missing {
+		fmt.Printf("%s: not a node in the cluster, skipped\n", host)
+	}
+	fmt.Printf("%d label changes on %d topology hosts, %d hosts not found\n", len(changes), len(desired), len(missing))
+	if !apply || len(changes) == 0 {
+		return nil
+	}
+
+	patches, err := topologyimport.LabelPatches(changes)
+	if err != nil {
+		return err
+	}
+	for node, patch := range patches {
+		if _, err := client.CoreV1().Nodes().Patch(ctx, node, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to label node %s: %w", node, err)
+		}
+	}
+	fmt.Printf("labeled %d nodes\n", len(patches))
+	return nil
+}
+
+// syncTopology prints the NetworkTopology diff against the cluster and creates or
+// updates the object with --apply
+func syncTopology(ctx context.Context, client dynamic.Interface, desired *v1alpha1.NetworkTopology) error {
+	resource := client.Resource(v1alpha1.SchemeGroupVersion.WithResource("networktopologies"))
+
+	var current *v1alpha1.NetworkTopology
+	existing, err := resource.Get(ctx, desired.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get NetworkTopology %s: %w", desired.Name, err)
+	default:
+		current = &v1alpha1.NetworkTopology{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, current); err != nil {
+			return fmt.Errorf("failed to read NetworkTopology %s: %w", desired.Name, err)
+		}
+	}
+
+	lines := topologyimport.DiffTopology(current, desired)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	fmt.Printf("%d domain changes to NetworkTopology %s\n", len(lines), desired.Name)
+	if !apply || len(lines) == 0 {
+		return nil
+	}
+
+	if current != nil {
+		desired.ResourceVersion = current.ResourceVersion
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	if current == nil {
+		_, err = resource.Create(ctx, u, metav1.CreateOptions{})
+	} else {
+		_, err = resource.Update(ctx, u, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write NetworkTopology %s: %w", desired.Name, err)
+	}
+	fmt.Printf("wrote NetworkTopology %s\n", desired.Name)
+	return nil
+}
@@ -28,8 +28,10 @@ import (
 	"syscall"
 	"time"
 
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/webhook"
@@ -71,7 +73,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	podMutator := webhook.NewPodMutator(clientset)
+	// Namespace tiers are served from an informer cache instead of per-request API calls
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	namespaceInformer := informerFactory.Core().V1().Namespaces()
+	namespaceLister := namespaceInformer.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, namespaceInformer.Informer().HasSynced) {
+		klog.ErrorS(nil, "Failed to sync namespace informer cache")
+		os.Exit(1)
+	}
+
+	podMutator := webhook.NewPodMutator(namespaceLister)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/mutate-pod", podMutator.Handle)
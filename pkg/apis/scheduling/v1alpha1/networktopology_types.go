@@ -0,0 +1,73 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkTopology describes the cluster network as a tree of switch domains, e.g.
+// superpod -> scalable unit -> leaf switch -> node
+type NetworkTopology struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NetworkTopologySpec `json:"spec"`
+}
+
+// NetworkTopologySpec defines the switch domains of the topology tree
+// +k8s:deepcopy-gen=true
+type NetworkTopologySpec struct {
+	// Domains lists the switch domains. Each names its parent; roots name none.
+	Domains []TopologyDomain `json:"domains"`
+}
+
+// TopologyDomain is one switch domain in the topology tree
+// +k8s:deepcopy-gen=true
+type TopologyDomain struct {
+	// Name identifies the domain within the topology
+	Name string `json:"name"`
+
+	// Level names the tier of the domain, e.g. superpod, scalable-unit or leaf
+	Level string `json:"level,omitempty"`
+
+	// Parent is the name of the enclosing domain, empty for a root
+	Parent string `json:"parent,omitempty"`
+
+	// Nodes are the Kubernetes nodes attached directly to this domain's switches
+	Nodes []string `json:"nodes,omitempty"`
+
+	// LinkBandwidthGbps is the bandwidth of each link into this domain's switches
+	LinkBandwidthGbps int64 `json:"linkBandwidthGbps,omitempty"`
+
+	// Oversubscription is the downlink to uplink ratio of this domain's switches toward
+	// the parent, e.g. "2:1" or "2". Empty means non-blocking.
+	Oversubscription string `json:"oversubscription,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkTopologyList contains a list of NetworkTopology
+type NetworkTopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkTopology `json:"items"`
+}
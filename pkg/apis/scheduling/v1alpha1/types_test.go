@@ -77,3 +77,30 @@ func TestConstants(t *testing.T) {
 		t.Errorf("Unexpected PodGroupLabel: %s", PodGroupLabel)
 	}
 }
+
+// TestNetworkTopologyDeepCopy verifies domains are copied deeply
+func TestNetworkTopologyDeepCopy(t *testing.T) {
+	original := &NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "ib-fabric"},
+		Spec: NetworkTopologySpec{
+			Domains: []TopologyDomain{
+				{Name: "su-1", Level: "scalable-unit", LinkBandwidthGbps: 400, Oversubscription: "2:1"},
+				{Name: "leaf-1", Level: "leaf", Parent: "su-1", Nodes: []string{"gpu-001", "gpu-002"}},
+			},
+		},
+	}
+
+	copied := original.DeepCopy()
+	original.Spec.Domains[1].Nodes[0] = "gpu-999"
+	original.Spec.Domains[0].Oversubscription = "1:1"
+
+	if copied.Spec.Domains[1].Nodes[0] != "gpu-001" {
+		t.Error("DeepCopy is not deep - node list shared with original")
+	}
+	if copied.Spec.Domains[0].Oversubscription != "2:1" {
+		t.Error("DeepCopy is not deep - domains shared with original")
+	}
+	if _, ok := copied.DeepCopyObject().(*NetworkTopology); !ok {
+		t.Error("DeepCopyObject did not return a NetworkTopology")
+	}
+}
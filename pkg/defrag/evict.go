@@ -0,0 +1,187 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defrag
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// DefaultWaveSize is the number of pods evicted per wave
+	DefaultWaveSize = 4
+	// DefaultWaveInterval is the pause between waves, leaving time to reschedule
+	DefaultWaveInterval = 2 * time.Minute
+
+	// TaintDefrag keeps new pods off an island while it is being freed
+	TaintDefrag = "scheduling.kubenexus.io/defrag"
+)
+
+// Evictor carries out plans in rate-limited waves of evictions
+type Evictor struct {
+	Client       kubernetes.Interface
+	WaveSize     int
+	WaveInterval time.Duration
+}
+
+// Execute frees the plan's islands one at a time. Each island's node is tainted
+// NoSchedule, so the evicted pods are not recreated on it, and its pods are evicted
+// WaveSize at a time with WaveInterval after each wave. An eviction refused by a
+// PodDisruptionBudget, or of a pod already gone, means the island cannot be freed as
+// planned, so the rest of its pods are left in place. The taint is removed once the
+// island is freed or abandoned, or the pass is aborted. It returns the number of pods
+// evicted.
+func (e *Evictor) Execute(ctx context.Context, plan *Plan) (int, error) {
+	evicted := 0
+	for _, island := range plan.Islands {
+		n, err := e.freeIsland(ctx, island)
+		evicted += n
+		if err != nil {
+			return evicted, err
+		}
+	}
+	return evicted, nil
+}
+
+// freeIsland taints the island's node and evicts its pods in waves, stopping at the
+// first pod that cannot be evicted. It returns the number of pods evicted.
+func (e *Evictor) freeIsland(ctx context.Context, island IslandPlan) (evicted int, err error) {
+	waveSize := e.WaveSize
+	if waveSize <= 0 {
+		waveSize = DefaultWaveSize
+	}
+
+	if err := e.setTaint(ctx, island.Node, true); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.InfoS("Defrag: abandoned island", "node", island.Node, "reason", err)
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer func() {
+		// Untaint even when the pass is aborted
+		if untaintErr := e.setTaint(context.WithoutCancel(ctx), island.Node, false); untaintErr != nil && err == nil {
+			err = untaintErr
+		}
+	}()
+
+	for start := 0; start < len(island.Migrations); start += waveSize {
+		wave := island.Migrations[start:min(start+waveSize, len(island.Migrations))]
+		for _, m := range wave {
+			eviction := &policyv1.Eviction{
+				ObjectMeta: metav1.ObjectMeta{Name: m.Pod.Name, Namespace: m.Pod.Namespace},
+			}
+			err := e.Client.PolicyV1().Evictions(m.Pod.Namespace).Evict(ctx, eviction)
+			switch {
+			case err == nil:
+				evicted++
+				klog.InfoS("Defrag: evicted pod", "pod", klog.KObj(m.Pod), "gpus", m.GPUs, "from", m.From, "expectedNode", m.To)
+			case apierrors.IsTooManyRequests(err), apierrors.IsNotFound(err):
+				klog.InfoS("Defrag: abandoned island", "node", island.Node, "pod", klog.KObj(m.Pod),
+					"reason", err, "podsLeft", len(island.Migrations)-evicted)
+				return evicted, nil
+			default:
+				return evicted, fmt.Errorf("failed to evict pod %s/%s: %w", m.Pod.Namespace, m.Pod.Name, err)
+			}
+		}
+
+		if err := e.pause(ctx); err != nil {
+			return evicted, err
+		}
+	}
+	return evicted, nil
+}
+
+// ClearTaints removes TaintDefrag left on nodes by a pass that was killed while freeing
+// an island
+func (e *Evictor) ClearTaints(ctx context.Context) error {
+	nodes, err := e.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !hasTaint(node) {
+			continue
+		}
+		klog.InfoS("Defrag: removing leftover taint", "node", node.Name, "taint", TaintDefrag)
+		if err := e.setTaint(ctx, node.Name, false); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTaint adds or removes the node's TaintDefrag
+func (e *Evictor) setTaint(ctx context.Context, nodeName string, tainted bool) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := e.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if hasTaint(node) == tainted {
+			return nil
+		}
+
+		taints := make([]v1.Taint, 0, len(node.Spec.Taints)+1)
+		for _, taint := range node.Spec.Taints {
+			if taint.Key != TaintDefrag {
+				taints = append(taints, taint)
+			}
+		}
+		if tainted {
+			taints = append(taints, v1.Taint{Key: TaintDefrag, Effect: v1.TaintEffectNoSchedule})
+		}
+
+		node.Spec.Taints = taints
+		_, err = e.Client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the %s taint of node %s: %w", TaintDefrag, nodeName, err)
+	}
+	return nil
+}
+
+// hasTaint reports whether the node carries TaintDefrag
+func hasTaint(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == TaintDefrag {
+			return true
+		}
+	}
+	return false
+}
+
+// pause waits WaveInterval, leaving time to reschedule the evicted pods elsewhere
+func (e *Evictor) pause(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(e.WaveInterval):
+		return nil
+	}
+}
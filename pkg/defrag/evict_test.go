@@ -0,0 +1,159 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defrag
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestEvictorExecute(t *testing.T) {
+	plan := &Plan{Islands: []IslandPlan{
+		{Node: "dgx-1", Migrations: []Migration{
+			{Pod: smallPod("a", "dgx-1", "1"), GPUs: 1, From: "dgx-1", To: "dgx-3"},
+			{Pod: smallPod("guarded", "dgx-1", "1"), GPUs: 1, From: "dgx-1", To: "dgx-3"},
+			{Pod: smallPod("c", "dgx-1", "1"), GPUs: 1, From: "dgx-1", To: "dgx-3"},
+		}},
+		{Node: "dgx-2", Migrations: []Migration{
+			{Pod: smallPod("b", "dgx-2", "1"), GPUs: 1, From: "dgx-2", To: "dgx-3"},
+		}},
+		{Node: "dgx-4", Migrations: []Migration{
+			{Pod: smallPod("gone", "dgx-4", "1"), GPUs: 1, From: "dgx-4", To: "dgx-3"},
+			{Pod: smallPod("d", "dgx-4", "1"), GPUs: 1, From: "dgx-4", To: "dgx-3"},
+		}},
+	}}
+
+	client := fake.NewClientset(nvswitchNode("dgx-1"), nvswitchNode("dgx-2"), nvswitchNode("dgx-4"))
+	var evicted []string
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(clienttesting.CreateAction).GetObject().(*policyv1.Eviction)
+		pod := podNamed(plan, eviction.Name)
+		if !nodeTainted(t, client, pod.Spec.NodeName) {
+			t.Errorf("node %s not tainted while evicting %s", pod.Spec.NodeName, eviction.Name)
+		}
+		switch eviction.Name {
+		case "guarded":
+			return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+		case "gone":
+			return true, nil, apierrors.NewNotFound(v1.Resource("pods"), eviction.Name)
+		}
+		evicted = append(evicted, eviction.Name)
+		return true, nil, nil
+	})
+
+	// c stays in place since dgx-1 can no longer be freed, and so does d behind the
+	// pod already gone from dgx-4
+	evictor := &Evictor{Client: client, WaveSize: 2}
+	count, err := evictor.Execute(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if count != 2 || len(evicted) != 2 || evicted[0] != "a" || evicted[1] != "b" {
+		t.Errorf("Execute() evicted %d: %v, want a and b with dgx-1 abandoned at the guarded pod", count, evicted)
+	}
+	for _, node := range []string{"dgx-1", "dgx-2", "dgx-4"} {
+		if nodeTainted(t, client, node) {
+			t.Errorf("node %s still tainted after Execute()", node)
+		}
+	}
+}
+
+func TestEvictorExecuteStopsBetweenWaves(t *testing.T) {
+	plan := &Plan{Islands: []IslandPlan{{Node: "dgx-1", Migrations: []Migration{
+		{Pod: smallPod("a", "dgx-1", "1"), GPUs: 1, From: "dgx-1", To: "dgx-3"},
+		{Pod: smallPod("b", "dgx-1", "1"), GPUs: 1, From: "dgx-1", To: "dgx-3"},
+	}}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	node := nvswitchNode("dgx-1")
+	node.Spec.Taints = []v1.Taint{{Key: "gpu", Effect: v1.TaintEffectNoSchedule}}
+	client := fake.NewClientset(node)
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		cancel()
+		return true, nil, nil
+	})
+
+	evictor := &Evictor{Client: client, WaveSize: 1, WaveInterval: DefaultWaveInterval}
+	count, err := evictor.Execute(ctx, plan)
+	if err == nil || count != 1 {
+		t.Errorf("Execute() = %d, %v, want 1 eviction and the context error", count, err)
+	}
+
+	// The aborted pass removes its taint and keeps the node's own
+	node, err = client.CoreV1().Nodes().Get(context.Background(), "dgx-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.Spec.Taints) != 1 || node.Spec.Taints[0].Key != "gpu" {
+		t.Errorf("taints after an aborted Execute() = %v, want only gpu", node.Spec.Taints)
+	}
+}
+
+func TestClearTaints(t *testing.T) {
+	leftover := nvswitchNode("dgx-1")
+	leftover.Spec.Taints = []v1.Taint{
+		{Key: "nvidia.com/gpu", Effect: v1.TaintEffectNoSchedule},
+		{Key: TaintDefrag, Effect: v1.TaintEffectNoSchedule},
+	}
+	client := fake.NewClientset(leftover, nvswitchNode("dgx-2"))
+
+	if err := (&Evictor{Client: client}).ClearTaints(context.Background()); err != nil {
+		t.Fatalf("ClearTaints() error = %v", err)
+	}
+	node, err := client.CoreV1().Nodes().Get(context.Background(), "dgx-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	if len(node.Spec.Taints) != 1 || node.Spec.Taints[0].Key != "nvidia.com/gpu" {
+		t.Errorf("taints after ClearTaints() = %v, want only nvidia.com/gpu", node.Spec.Taints)
+	}
+}
+
+// podNamed returns the planned pod of the name
+func podNamed(plan *Plan, name string) *v1.Pod {
+	for _, m := range plan.Migrations() {
+		if m.Pod.Name == name {
+			return m.Pod
+		}
+	}
+	return nil
+}
+
+// nodeTainted reports whether the node carries TaintDefrag, read from the tracker so it
+// may be called from a reactor
+func nodeTainted(t *testing.T, client *fake.Clientset, name string) bool {
+	t.Helper()
+	obj, err := client.Tracker().Get(v1.SchemeGroupVersion.WithResource("nodes"), "", name)
+	if err != nil {
+		t.Fatalf("failed to get node %s: %v", name, err)
+	}
+	return hasTaint(obj.(*v1.Node))
+}
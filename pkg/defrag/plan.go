@@ -0,0 +1,272 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defrag plans and carries out GPU defragmentation: moving small preemptible or
+// checkpointable GPU pods off partially used NVSwitch islands so that whole islands are
+// free again for large training jobs.
+//
+// Placement-time fragmentation scoring slows fragmentation down but cannot undo it; over
+// hours a cluster degrades into half-used 8-GPU nodes. The planner uses the GPU island
+// model of ResourceFragmentationScore and the preemptibility ProfileClassifier publishes
+// on bound pods:
+//   - a source island is a partially used NVSwitch island whose every GPU pod may move
+//   - its pods must fit, best fit first, into free GPUs of other partially used islands
+//     open to new pods; pristine, cordoned and NoSchedule-tainted islands are never
+//     used as destinations
+//   - islands cheapest to free, those with the fewest GPUs in use, are freed first
+//
+// Islands are freed one at a time, their node tainted NoSchedule meanwhile. Evictions
+// are made through the Eviction API, so PodDisruptionBudgets hold, in rate-limited
+// waves; an island whose pod cannot be evicted is abandoned. The recreated pods are placed by the scheduler, whose
+// fragmentation scoring steers them onto the partially used islands planned here.
+package defrag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/resourcefragmentation"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+const (
+	// AnnotationCheckpointable marks pods that resume from a checkpoint after eviction
+	AnnotationCheckpointable = "scheduling.kubenexus.io/checkpointable"
+
+	// TopologyNVSwitch is the island topology defragmentation recreates
+	TopologyNVSwitch = "nvswitch"
+
+	// DefaultMaxPodGPUs is the largest GPU request moved by default
+	DefaultMaxPodGPUs = resourcefragmentation.SmallRequestThreshold
+)
+
+// Options limits what a plan may move
+type Options struct {
+	// MaxPodGPUs is the largest GPU request of a pod that may be moved
+	MaxPodGPUs int
+	// MaxIslands is the number of islands freed by one plan, 0 for no limit
+	MaxIslands int
+}
+
+// Migration moves one pod off an island
+type Migration struct {
+	Pod  *v1.Pod
+	GPUs int
+	From string
+	To   string // Node expected to take the recreated pod
+}
+
+// IslandPlan frees one island
+type IslandPlan struct {
+	Node       string
+	GPUModel   string
+	TotalGPUs  int
+	Migrations []Migration
+}
+
+// Plan is the set of islands to free, in order
+type Plan struct {
+	Islands []IslandPlan
+}
+
+// Migrations returns the migrations of every island, in plan order
+func (p *Plan) Migrations() []Migration {
+	var migrations []Migration
+	for _, island := range p.Islands {
+		migrations = append(migrations, island.Migrations...)
+	}
+	return migrations
+}
+
+// String formats the plan for dry-run output
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, island := range p.Islands {
+		fmt.Fprintf(&b, "free island %s (%s, %d GPUs): move %d pod(s)\n", island.Node, island.GPUModel, island.TotalGPUs, len(island.Migrations))
+		for _, m := range island.Migrations {
+			fmt.Fprintf(&b, "  %s/%s (%d GPUs) %s -> %s\n", m.Pod.Namespace, m.Pod.Name, m.GPUs, m.From, m.To)
+		}
+	}
+	fmt.Fprintf(&b, "%d island(s) to free, %d pod(s) to move\n", len(p.Islands), len(p.Migrations()))
+	return b.String()
+}
+
+// Movable reports whether the pod may be evicted to defragment its island, and why not
+func Movable(pod *v1.Pod, opts Options) (bool, string) {
+	gpus := resourcefragmentation.GetGPURequest(pod)
+	switch {
+	case gpus == 0:
+		return false, "requests no GPUs"
+	case gpus > opts.MaxPodGPUs:
+		return false, fmt.Sprintf("requests %d GPUs, more than %d", gpus, opts.MaxPodGPUs)
+	case pod.DeletionTimestamp != nil:
+		return false, "is terminating"
+	case metav1.GetControllerOf(pod) == nil:
+		return false, "has no controller to recreate it"
+	case pod.Annotations[profileclassifier.AnnotationProfileGang] == "true" || utils.IsPodInPodGroup(pod):
+		return false, "is a gang member"
+	case !profileclassifier.IsPodPreemptible(pod) && !isCheckpointable(pod):
+		return false, "is neither preemptible nor checkpointable"
+	}
+	return true, ""
+}
+
+// isCheckpointable reports whether the pod resumes from a checkpoint: annotated as such,
+// or classified as fine-tuning
+func isCheckpointable(pod *v1.Pod) bool {
+	if value, ok := pod.Annotations[AnnotationCheckpointable]; ok {
+		return value == "true"
+	}
+	return pod.Annotations[profileclassifier.AnnotationProfileWorkloadType] == string(profileclassifier.WorkloadFineTuning)
+}
+
+// BuildPlan picks the NVSwitch islands to free and where their pods are expected to go
+func BuildPlan(nodes []*v1.Node, pods []*v1.Pod, opts Options) *Plan {
+	podsByNode := make(map[string][]*v1.Pod)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if resourcefragmentation.GetGPURequest(pod) > 0 {
+			podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+		}
+	}
+
+	closed := make(map[string]bool)
+	for _, node := range nodes {
+		if !acceptsPods(node) {
+			closed[node.Name] = true
+		}
+	}
+
+	// Free GPUs on partially used islands open to new pods take the moved pods
+	free := make(map[string]int)
+	var sources []*resourcefragmentation.GPUIsland
+	for _, island := range resourcefragmentation.BuildIslands(nodes, pods) {
+		if island.AllocatedGPUs == 0 || island.AvailableGPUs <= 0 {
+			continue
+		}
+		if !closed[island.NodeName] {
+			free[island.NodeName] = island.AvailableGPUs
+		}
+		if island.Topology == TopologyNVSwitch && allMovable(podsByNode[island.NodeName], opts) {
+			sources = append(sources, island)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].AllocatedGPUs != sources[j].AllocatedGPUs {
+			return sources[i].AllocatedGPUs < sources[j].AllocatedGPUs
+		}
+		return sources[i].NodeName < sources[j].NodeName
+	})
+
+	plan := &Plan{}
+	receiving := make(map[string]bool)
+	for _, source := range sources {
+		if opts.MaxIslands > 0 && len(plan.Islands) >= opts.MaxIslands {
+			break
+		}
+		if receiving[source.NodeName] {
+			continue
+		}
+		migrations, remaining, ok := placePods(source.NodeName, podsByNode[source.NodeName], free)
+		if !ok {
+			continue
+		}
+		free = remaining
+		delete(free, source.NodeName)
+		for _, m := range migrations {
+			receiving[m.To] = true
+		}
+		plan.Islands = append(plan.Islands, IslandPlan{
+			Node:       source.NodeName,
+			GPUModel:   source.GPUModel,
+			TotalGPUs:  source.TotalGPUs,
+			Migrations: migrations,
+		})
+	}
+	return plan
+}
+
+// acceptsPods reports whether new pods may land on the node: it is not cordoned and
+// has no NoSchedule or NoExecute taint, such as TaintDefrag on an island being freed
+func acceptsPods(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	return true
+}
+
+func allMovable(pods []*v1.Pod, opts Options) bool {
+	if len(pods) == 0 {
+		return false
+	}
+	for _, pod := range pods {
+		if ok, _ := Movable(pod, opts); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// placePods fits the source's pods, largest first, into the free GPUs of other islands,
+// each into the island it fills best. It returns the migrations and the free GPUs left,
+// or false when some pod does not fit.
+func placePods(source string, pods []*v1.Pod, free map[string]int) ([]Migration, map[string]int, bool) {
+	remaining := make(map[string]int, len(free))
+	for node, gpus := range free {
+		remaining[node] = gpus
+	}
+
+	sorted := append([]*v1.Pod(nil), pods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		gi, gj := resourcefragmentation.GetGPURequest(sorted[i]), resourcefragmentation.GetGPURequest(sorted[j])
+		if gi != gj {
+			return gi > gj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	migrations := make([]Migration, 0, len(sorted))
+	for _, pod := range sorted {
+		gpus := resourcefragmentation.GetGPURequest(pod)
+		best := ""
+		for node, available := range remaining {
+			if node == source || available < gpus {
+				continue
+			}
+			if best == "" || available < remaining[best] || (available == remaining[best] && node < best) {
+				best = node
+			}
+		}
+		if best == "" {
+			return nil, nil, false
+		}
+		remaining[best] -= gpus
+		migrations = append(migrations, Migration{Pod: pod, GPUs: gpus, From: source, To: best})
+	}
+	return migrations, remaining, true
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defrag
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/resourcefragmentation"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+var defaultOptions = Options{MaxPodGPUs: DefaultMaxPodGPUs}
+
+func nvswitchNode(name string) *v1.Node {
+	return testutil.MakeNode(name, map[string]string{
+		resourcefragmentation.LabelGPUTopology: TopologyNVSwitch,
+		resourcefragmentation.LabelGPUModel:    "H100",
+	}, v1.ResourceList{resourcefragmentation.ResourceGPU: resource.MustParse("8")})
+}
+
+// smallPod builds a preemptible, controller-owned pod bound to the node
+func smallPod(name, nodeName, gpus string) *v1.Pod {
+	pod := testutil.MakePod(name, "default", nodeName,
+		v1.ResourceList{resourcefragmentation.ResourceGPU: resource.MustParse(gpus)},
+		nil, map[string]string{profileclassifier.AnnotationProfilePreemptible: "true"})
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: name, Controller: &controller}}
+	pod.Status.Phase = v1.PodRunning
+	return pod
+}
+
+func TestMovable(t *testing.T) {
+	checkpointable := smallPod("ckpt", "n", "1")
+	checkpointable.Annotations = map[string]string{AnnotationCheckpointable: "true"}
+	fineTuning := smallPod("ft", "n", "1")
+	fineTuning.Annotations = map[string]string{profileclassifier.AnnotationProfileWorkloadType: "fine-tuning"}
+	notPreemptible := smallPod("serving", "n", "1")
+	notPreemptible.Annotations = map[string]string{profileclassifier.AnnotationProfilePreemptible: "false"}
+	bare := smallPod("bare", "n", "1")
+	bare.OwnerReferences = nil
+	gang := smallPod("gang", "n", "1")
+	gang.Annotations[profileclassifier.AnnotationProfileGang] = "true"
+
+	tests := []struct {
+		pod  *v1.Pod
+		want bool
+	}{
+		{pod: smallPod("preemptible", "n", "2"), want: true},
+		{pod: checkpointable, want: true},
+		{pod: fineTuning, want: true},
+		{pod: smallPod("large", "n", "4"), want: false},
+		{pod: notPreemptible, want: false},
+		{pod: bare, want: false},
+		{pod: gang, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pod.Name, func(t *testing.T) {
+			if got, reason := Movable(tt.pod, defaultOptions); got != tt.want {
+				t.Errorf("Movable() = %v (%s), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	nodes := []*v1.Node{
+		nvswitchNode("dgx-1"), // 2 GPUs used by movable pods: freed first
+		nvswitchNode("dgx-2"), // 3 GPUs used by movable pods
+		nvswitchNode("dgx-3"), // 6 GPUs used, one pod not movable: a destination
+		nvswitchNode("dgx-4"), // pristine: never a destination
+	}
+	serving := smallPod("serving", "dgx-3", "4")
+	serving.Annotations[profileclassifier.AnnotationProfilePreemptible] = "false"
+	pods := []*v1.Pod{
+		smallPod("a", "dgx-1", "1"),
+		smallPod("b", "dgx-1", "1"),
+		smallPod("c", "dgx-2", "2"),
+		smallPod("d", "dgx-2", "1"),
+		serving,
+		smallPod("e", "dgx-3", "2"),
+	}
+
+	plan := BuildPlan(nodes, pods, defaultOptions)
+	if len(plan.Islands) != 1 || plan.Islands[0].Node != "dgx-1" {
+		t.Fatalf("BuildPlan() islands = %+v, want only dgx-1", plan.Islands)
+	}
+	// dgx-3 has 2 free GPUs, fuller than dgx-2's 5, so best fit sends both pods there
+	for _, m := range plan.Islands[0].Migrations {
+		if m.From != "dgx-1" || m.To != "dgx-3" {
+			t.Errorf("migration %s: %s -> %s, want dgx-1 -> dgx-3", m.Pod.Name, m.From, m.To)
+		}
+	}
+	// dgx-2's pods no longer fit once dgx-3 is full and dgx-1 is freed
+	if len(plan.Migrations()) != 2 {
+		t.Errorf("Migrations() = %d, want 2", len(plan.Migrations()))
+	}
+	if out := plan.String(); !strings.Contains(out, "free island dgx-1 (H100, 8 GPUs): move 2 pod(s)") ||
+		!strings.Contains(out, "default/a (1 GPUs) dgx-1 -> dgx-3") {
+		t.Errorf("String() = %q, missing the island or a migration", out)
+	}
+
+	if limited := BuildPlan(nodes, pods, Options{MaxPodGPUs: 1}); len(limited.Islands) != 1 || limited.Islands[0].Node != "dgx-1" {
+		t.Errorf("BuildPlan(MaxPodGPUs=1) islands = %+v, want dgx-1", limited.Islands)
+	}
+}
+
+func TestBuildPlanSkipsIslandsThatCannotBeFreed(t *testing.T) {
+	nodes := []*v1.Node{nvswitchNode("dgx-1"), nvswitchNode("dgx-2")}
+	pods := []*v1.Pod{
+		smallPod("a", "dgx-1", "2"),
+		smallPod("b", "dgx-2", "2"),
+	}
+	// Each island's pods fit on the other, but only one of them can be freed
+	plan := BuildPlan(nodes, pods, defaultOptions)
+	if len(plan.Islands) != 1 || plan.Islands[0].Node != "dgx-1" || plan.Islands[0].Migrations[0].To != "dgx-2" {
+		t.Errorf("BuildPlan() = %+v, want dgx-1 freed onto dgx-2", plan.Islands)
+	}
+
+	plan = BuildPlan(nodes, pods, Options{MaxPodGPUs: 1})
+	if len(plan.Islands) != 0 {
+		t.Errorf("BuildPlan() with no movable pods = %+v, want empty", plan.Islands)
+	}
+}
+
+func TestBuildPlanSkipsClosedDestinations(t *testing.T) {
+	tainted := nvswitchNode("dgx-2")
+	tainted.Spec.Taints = []v1.Taint{{Key: TaintDefrag, Effect: v1.TaintEffectNoSchedule}}
+	cordoned := nvswitchNode("dgx-3")
+	cordoned.Spec.Unschedulable = true
+	nodes := []*v1.Node{nvswitchNode("dgx-1"), tainted, cordoned}
+	pods := []*v1.Pod{
+		smallPod("a", "dgx-1", "2"),
+		smallPod("b", "dgx-2", "4"),
+		smallPod("c", "dgx-3", "4"),
+	}
+
+	// dgx-1's pods fit on either node, but neither accepts new pods
+	for _, m := range BuildPlan(nodes, pods, defaultOptions).Migrations() {
+		if m.To == "dgx-2" || m.To == "dgx-3" {
+			t.Errorf("migration %s: %s -> %s, want no tainted or cordoned destination", m.Pod.Name, m.From, m.To)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gpucatalog is the scheduler's shared knowledge of GPU models: PCI IDs,
+// marketing names, VRAM, generation, NVLink capability, hardware tier and cost.
+//
+// Defaults are compiled in. Operators add or override models with the
+// kubenexus-gpu-catalog ConfigMap in kubenexus-system, so a new SKU needs no code change:
+//
+//	apiVersion: v1
+//	kind: ConfigMap
+//	metadata:
+//	  name: kubenexus-gpu-catalog
+//	  namespace: kubenexus-system
+//	data:
+//	  catalog.yaml: |
+//	    models:
+//	    - name: B200
+//	      vendor: nvidia
+//	      pciIDs: ["10de:2901"]
+//	      vram: 180Gi
+//	      generation: blackwell
+//	      nvlink: true
+//	      tier: premium
+//	      costPerHour: 6.00
+package gpucatalog
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+const (
+	// ConfigMapName is the ConfigMap holding catalog overrides
+	ConfigMapName = "kubenexus-gpu-catalog"
+
+	// ConfigMapKey is the ConfigMap data key containing the catalog document
+	ConfigMapKey = "catalog.yaml"
+
+	// Hardware tiers
+	TierPremium  = "premium"
+	TierStandard = "standard"
+	TierEconomy  = "economy"
+)
+
+//go:embed defaults.yaml
+var defaultCatalogYAML []byte
+
+// Model describes one GPU SKU
+type Model struct {
+	// Name is the canonical model name matched against gpu.kubenexus.io/model, e.g. "H100"
+	Name string `json:"name"`
+
+	// Aliases are other names for the model, e.g. "H100-80GB"
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Vendor is nvidia, amd or intel
+	Vendor string `json:"vendor,omitempty"`
+
+	// PCIIDs are "vendor:device" PCI IDs in hex, e.g. "10de:2330"
+	PCIIDs []string `json:"pciIDs,omitempty"`
+
+	// VRAM is the memory of one device
+	VRAM resource.Quantity `json:"vram"`
+
+	// Generation is the architecture, e.g. "hopper", "cdna3"
+	Generation string `json:"generation,omitempty"`
+
+	// NVLink reports a high-bandwidth GPU interconnect (NVLink, Infinity Fabric, Xe Link)
+	NVLink bool `json:"nvlink,omitempty"`
+
+	// Tier is the hardware tier: premium, standard or economy; empty when unclassified
+	Tier string `json:"tier,omitempty"`
+
+	// CostPerHour is a relative on-demand price per GPU-hour
+	CostPerHour float64 `json:"costPerHour,omitempty"`
+}
+
+// VRAMBytes returns the model's per-device VRAM in bytes
+func (m *Model) VRAMBytes() int64 {
+	return m.VRAM.Value()
+}
+
+// Document is the catalog file format
+type Document struct {
+	Models []Model `json:"models"`
+}
+
+// Catalog is an immutable, indexed set of GPU models
+type Catalog struct {
+	models []Model
+	byPCI  map[string]*Model
+	// names maps upper-cased names and aliases to models, for substring matching
+	names map[string]*Model
+}
+
+var (
+	defaultCatalog = mustParseDefaults()
+	current        atomic.Pointer[Catalog]
+	watchOnce      sync.Once
+)
+
+// Default returns the compiled-in catalog
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Get returns the catalog in effect: the defaults merged with the ConfigMap, if any
+func Get() *Catalog {
+	if c := current.Load(); c != nil {
+		return c
+	}
+	return defaultCatalog
+}
+
+// Watch starts hot-reloading the catalog from the ConfigMap. Plugins sharing the
+// catalog may all call it; only the first call starts a watch.
+func Watch(ctx context.Context, client kubernetes.Interface) error {
+	var err error
+	watchOnce.Do(func() {
+		err = utils.WatchConfigMap(ctx, client, utils.DefaultConfigNamespace, ConfigMapName, onCatalogChange)
+	})
+	return err
+}
+
+// onCatalogChange installs the defaults merged with the ConfigMap. An invalid
+// document is rejected and the previous catalog stays in effect.
+func onCatalogChange(cm *v1.ConfigMap) {
+	if cm == nil {
+		current.Store(nil)
+		klog.InfoS("GPU catalog ConfigMap removed, using compiled-in defaults")
+		return
+	}
+
+	overrides, err := Parse([]byte(cm.Data[ConfigMapKey]))
+	if err != nil {
+		klog.ErrorS(err, "Rejected GPU catalog, keeping previous catalog",
+			"configMap", klog.KObj(cm), "resourceVersion", cm.ResourceVersion)
+		return
+	}
+
+	merged := defaultCatalog.Merge(overrides)
+	current.Store(merged)
+	klog.InfoS("Loaded GPU catalog", "configMap", klog.KObj(cm),
+		"resourceVersion", cm.ResourceVersion, "overrides", len(overrides.models), "models", len(merged.models))
+}
+
+// Parse decodes and validates a YAML or JSON catalog document
+func Parse(data []byte) (*Catalog, error) {
+	doc := &Document{}
+	if err := yaml.UnmarshalStrict(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to decode GPU catalog: %w", err)
+	}
+	for i, m := range doc.Models {
+		if m.Name == "" {
+			return nil, fmt.Errorf("model %d: name is required", i)
+		}
+		switch m.Tier {
+		case "", TierPremium, TierStandard, TierEconomy:
+		default:
+			return nil, fmt.Errorf("model %q: unknown tier %q", m.Name, m.Tier)
+		}
+		for _, id := range m.PCIIDs {
+			if vendor, device, ok := strings.Cut(id, ":"); !ok || vendor == "" || device == "" {
+				return nil, fmt.Errorf("model %q: PCI ID %q must be vendor:device", m.Name, id)
+			}
+		}
+	}
+	return newCatalog(doc.Models), nil
+}
+
+// Merge returns a catalog with the other catalog's models added, replacing
+// models of the same name
+func (c *Catalog) Merge(other *Catalog) *Catalog {
+	replaced := make(map[string]bool, len(other.models))
+	for _, m := range other.models {
+		replaced[strings.ToUpper(m.Name)] = true
+	}
+	models := make([]Model, 0, len(c.models)+len(other.models))
+	for _, m := range c.models {
+		if !replaced[strings.ToUpper(m.Name)] {
+			models = append(models, m)
+		}
+	}
+	return newCatalog(append(models, other.models...))
+}
+
+func newCatalog(models []Model) *Catalog {
+	c := &Catalog{
+		models: models,
+		byPCI:  make(map[string]*Model),
+		names:  make(map[string]*Model),
+	}
+	for i := range c.models {
+		m := &c.models[i]
+		for _, id := range m.PCIIDs {
+			c.byPCI[strings.ToLower(id)] = m
+		}
+		for _, name := range append([]string{m.Name}, m.Aliases...) {
+			c.names[strings.ToUpper(name)] = m
+		}
+	}
+	return c
+}
+
+// LookupPCI returns the model with the given PCI vendor and device IDs (hex)
+func (c *Catalog) LookupPCI(vendorID, deviceID string) (*Model, bool) {
+	m, ok := c.byPCI[strings.ToLower(vendorID+":"+deviceID)]
+	return m, ok
+}
+
+// LookupModel matches a GPU model label such as "NVIDIA-H100-80GB-HBM3" against
+// model names and aliases. The longest contained name wins, so "A100-80GB" beats
+// "A100" and "L40S" beats "L40".
+func (c *Catalog) LookupModel(label string) (*Model, bool) {
+	upper := strings.ToUpper(label)
+	var best *Model
+	bestName := ""
+	for name, m := range c.names {
+		if !strings.Contains(upper, name) {
+			continue
+		}
+		if len(name) > len(bestName) || (len(name) == len(bestName) && name < bestName) {
+			best, bestName = m, name
+		}
+	}
+	return best, best != nil
+}
+
+// Models returns the catalog's models
+func (c *Catalog) Models() []Model {
+	return c.models
+}
+
+func mustParseDefaults() *Catalog {
+	c, err := Parse(defaultCatalogYAML)
+	if err != nil {
+		panic(fmt.Sprintf("invalid compiled-in GPU catalog: %v", err))
+	}
+	return c
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpucatalog
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GiB = 1024 * 1024 * 1024
+
+func TestLookupModel(t *testing.T) {
+	tests := []struct {
+		label string
+		name  string
+		vram  int64
+	}{
+		{"H100", "H100", 80 * GiB},
+		{"H100-80GB", "H100", 80 * GiB},
+		{"NVIDIA-H100-80GB-HBM3", "H100", 80 * GiB},
+		{"H200", "H200", 141 * GiB},
+		{"A100-80GB", "A100-80GB", 80 * GiB},
+		{"A100", "A100", 40 * GiB},
+		{"A40", "A40", 48 * GiB},
+		{"L40S", "L40S", 48 * GiB},
+		{"L4", "L4", 24 * GiB},
+		{"t4", "T4", 16 * GiB},
+		{"V100", "V100", 32 * GiB},
+		{"MI300X", "MI300X", 192 * GiB},
+		{"UNKNOWN-GPU", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			m, ok := Default().LookupModel(tt.label)
+			if tt.name == "" {
+				if ok {
+					t.Fatalf("expected no match, got %s", m.Name)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no model matched %q", tt.label)
+			}
+			if m.Name != tt.name || m.VRAMBytes() != tt.vram {
+				t.Errorf("LookupModel(%q) = %s/%d, want %s/%d", tt.label, m.Name, m.VRAMBytes(), tt.name, tt.vram)
+			}
+		})
+	}
+}
+
+func TestLookupPCI(t *testing.T) {
+	tests := []struct {
+		vendor, device string
+		name           string
+	}{
+		{"10de", "2330", "H100"},
+		{"10de", "20B5", "A100-80GB"},
+		{"1002", "740f", "MI300X"},
+		{"8086", "0bd5", "MAX1550"},
+		{"10de", "ffff", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vendor+":"+tt.device, func(t *testing.T) {
+			m, ok := Default().LookupPCI(tt.vendor, tt.device)
+			if ok != (tt.name != "") {
+				t.Fatalf("LookupPCI found=%v, want %v", ok, tt.name != "")
+			}
+			if ok && m.Name != tt.name {
+				t.Errorf("LookupPCI = %s, want %s", m.Name, tt.name)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{"valid", "models:\n- name: B200\n  pciIDs: [\"10de:2901\"]\n  vram: 180Gi\n  tier: premium\n", false},
+		{"missing name", "models:\n- vram: 80Gi\n", true},
+		{"unknown tier", "models:\n- name: X\n  vram: 80Gi\n  tier: gold\n", true},
+		{"bad PCI ID", "models:\n- name: X\n  vram: 80Gi\n  pciIDs: [\"2901\"]\n", true},
+		{"unknown field", "models:\n- name: X\n  vram: 80Gi\n  memory: 80Gi\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigMapOverrides(t *testing.T) {
+	defer current.Store(nil)
+
+	onCatalogChange(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "kubenexus-system"},
+		Data: map[string]string{ConfigMapKey: `
+models:
+- name: B200
+  vendor: nvidia
+  pciIDs: ["10de:2901"]
+  vram: 180Gi
+  tier: premium
+- name: L40S
+  vram: 48Gi
+  tier: standard
+`},
+	})
+
+	if m, ok := Get().LookupPCI("10de", "2901"); !ok || m.VRAMBytes() != 180*GiB {
+		t.Errorf("B200 not added by ConfigMap")
+	}
+	if m, ok := Get().LookupModel("L40S"); !ok || m.Tier != TierStandard {
+		t.Errorf("L40S tier not overridden by ConfigMap")
+	}
+	if _, ok := Get().LookupModel("H100"); !ok {
+		t.Errorf("defaults dropped by ConfigMap merge")
+	}
+
+	// An invalid document keeps the previous catalog
+	onCatalogChange(&v1.ConfigMap{Data: map[string]string{ConfigMapKey: "models: [{vram: 1Gi}]"}})
+	if _, ok := Get().LookupModel("B200"); !ok {
+		t.Errorf("invalid ConfigMap replaced the catalog")
+	}
+
+	onCatalogChange(nil)
+	if _, ok := Get().LookupModel("B200"); ok {
+		t.Errorf("deleting the ConfigMap didn't restore defaults")
+	}
+}
@@ -21,9 +21,7 @@ import (
 	"context"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	corelisters "k8s.io/client-go/listers/core/v1"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
 
@@ -73,8 +71,7 @@ const GPUResourceName = "nvidia.com/gpu"
 //	  - Node A: score = 20 (avoid disrupting existing workloads)
 //	  - Node B: score = 80 (use the idle capacity!)
 type BackfillScoring struct {
-	handle    framework.Handle
-	podLister corelisters.PodLister
+	handle framework.Handle
 }
 
 var _ framework.ScorePlugin = &BackfillScoring{}
@@ -139,37 +136,12 @@ func (b *BackfillScoring) Score(ctx context.Context, state framework.CycleState,
 		return MaxNodeScore / 2, framework.NewStatus(framework.Success, "")
 	}
 
-	// Safety check: podLister can be nil during initialization
-	if b.podLister == nil {
-		klog.V(5).InfoS("BackfillScoring: podLister not initialized, using neutral score")
-		return MaxNodeScore / 2, framework.NewStatus(framework.Success, "")
-	}
-
-	// Get currently requested resources on the node
-	// Sum up all pod requests on this node
-	allPods, err := b.podLister.List(labels.Everything())
-	if err != nil {
-		klog.V(4).InfoS("BackfillScoring: failed to list pods, using neutral score", "err", err)
-		// On error, return neutral score
-		return MaxNodeScore / 2, framework.NewStatus(framework.Success, "")
-	}
-
-	requestedCPU := float64(0)
-	requestedMemory := float64(0)
-	requestedGPU := float64(0)
-
-	// Only sum pods that are scheduled on THIS specific node
-	for _, podOnNode := range allPods {
-		if podOnNode.Spec.NodeName == node.Name {
-			for _, container := range podOnNode.Spec.Containers {
-				requestedCPU += float64(container.Resources.Requests.Cpu().MilliValue())
-				requestedMemory += float64(container.Resources.Requests.Memory().Value())
-				if gpu, ok := container.Resources.Requests[v1.ResourceName(GPUResourceName)]; ok {
-					requestedGPU += float64(gpu.Value())
-				}
-			}
-		}
-	}
+	// Get currently requested resources on the node. The cycle's NodeInfo also
+	// holds assumed pods and the pods preemption simulates removing or nominating.
+	requested := nodeInfo.GetRequested()
+	requestedCPU := float64(requested.GetMilliCPU())
+	requestedMemory := float64(requested.GetMemory())
+	requestedGPU := float64(requested.GetScalarResources()[v1.ResourceName(GPUResourceName)])
 
 	// Calculate utilization percentages (0-100)
 	cpuUtilization := (requestedCPU / allocatableCPU) * 100.0
@@ -383,11 +355,8 @@ func (b *BackfillScoring) calculateTenantAdjustment(tenantTier string, node *v1.
 
 // New initializes a new BackfillScoring plugin and returns it.
 func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	podLister := handle.SharedInformerFactory().Core().V1().Pods().Lister()
-
 	klog.V(3).InfoS("BackfillScoring plugin initialized")
 	return &BackfillScoring{
-		handle:    handle,
-		podLister: podLister,
+		handle: handle,
 	}, nil
 }
@@ -18,6 +18,7 @@ with the following enhancements:
 - ProfileClassifier integration for tenant/workload-aware gang detection
 - Enhanced starvation prevention with age-based priority boosting
 - Integration with ResourceReservation plugin for driver pod protection
+- Queueing hints that requeue a gang on member arrival or freed capacity
 */
 
 // Package coscheduling implements gang scheduling with enterprise features.
@@ -31,12 +32,15 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
 	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
@@ -51,6 +55,9 @@ type Coscheduling struct {
 	podGroupInfos sync.Map
 	// podGroupCount tracks the number of entries in podGroupInfos for size-cap enforcement
 	podGroupCount atomic.Int64
+	// quorumActivated holds the keys of pod groups whose pending members were activated
+	// when the group reached minAvailable
+	quorumActivated sync.Map
 	// Metrics and monitoring
 	schedulingAttempts map[string]int
 	// stopCh signals the cleanup goroutine to stop
@@ -82,6 +89,7 @@ var _ framework.QueueSortPlugin = &Coscheduling{}
 var _ framework.PreFilterPlugin = &Coscheduling{}
 var _ framework.PermitPlugin = &Coscheduling{}
 var _ framework.ReservePlugin = &Coscheduling{}
+var _ framework.EnqueueExtensions = &Coscheduling{}
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
@@ -94,8 +102,15 @@ const (
 	PermitWaitingTime = 10 * time.Second
 	// StarvationThreshold is the time after which a pod group gets priority boost to prevent starvation
 	StarvationThreshold = 60 * time.Second
+
+	// reservationPodGroupLabel is the label ResourceReservation puts on the CRDs it creates for a gang
+	reservationPodGroupLabel = "pod-group"
 )
 
+// resourceReservationResource is the dynamic informer resource for ResourceReservation CRDs,
+// in the <plural>.<version>.<group> form the scheduler expects for custom resources.
+var resourceReservationResource = framework.EventResource("resourcereservations." + v1alpha1.Version + "." + v1alpha1.GroupName)
+
 // Name returns name of the plugin. It is used in logs, etc.
 func (cs *Coscheduling) Name() string {
 	return Name
@@ -265,12 +280,14 @@ func (cs *Coscheduling) PreFilter(ctx context.Context, state framework.CycleStat
 		klog.V(3).InfoS("PreFilter: insufficient pods in group",
 			"namespace", p.Namespace, "podGroup", podGroupName, "total", total, "minAvailable", minAvailable, "pod", p.Name)
 		schedulermetrics.GangSchedulingDecisions.WithLabelValues("insufficient_pods", p.Namespace).Inc()
+		cs.quorumActivated.Delete(utils.GetPodGroupKey(p.Namespace, podGroupName))
 		return nil, framework.NewStatus(framework.Unschedulable,
 			fmt.Sprintf("pod group has %d pods, needs at least %d", total, minAvailable))
 	}
 
 	klog.V(4).InfoS("PreFilter: pod group has sufficient pods",
 		"namespace", p.Namespace, "podGroup", podGroupName, "total", total, "minAvailable", minAvailable)
+	cs.activateOnQuorum(ctx, p, podGroupName)
 	// Return empty PreFilterResult (not nil) to indicate processing succeeded
 	return &framework.PreFilterResult{}, framework.NewStatus(framework.Success, "")
 }
@@ -280,6 +297,156 @@ func (cs *Coscheduling) PreFilterExtensions() framework.PreFilterExtensions {
 	return nil //nolint:staticcheck // Acceptable pattern for no extensions
 }
 
+// EventsToRegister returns the cluster events that can make a rejected gang schedulable.
+// A gang is only requeued when a new member arrives or capacity is released, and only
+// once it has enough members for that capacity to matter; unrelated events are skipped
+// so waiting gangs don't burn scheduling cycles during backoff.
+// The reservation event relies on the ResourceReservation CRD being installed, which the
+// ResourceReservation plugin already requires.
+func (cs *Coscheduling) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	return []framework.ClusterEventWithHint{
+		// A new member pod is created, or an existing pod is relabeled into the gang
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Add | framework.UpdatePodLabel}, QueueingHintFn: cs.isSchedulableAfterPodAddedOrRelabeled},
+		// A bound pod is deleted, freeing node capacity
+		{Event: framework.ClusterEvent{Resource: framework.Pod, ActionType: framework.Delete}, QueueingHintFn: cs.isSchedulableAfterPodDeleted},
+		// Another gang's ResourceReservation is released
+		{Event: framework.ClusterEvent{Resource: resourceReservationResource, ActionType: framework.Delete}, QueueingHintFn: cs.isSchedulableAfterReservationDeleted},
+	}, nil
+}
+
+// isSchedulableAfterPodAddedOrRelabeled requeues the gang when the event brings a new member
+// into the same pod group and the group now reaches minAvailable.
+func (cs *Coscheduling) isSchedulableAfterPodAddedOrRelabeled(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return framework.Queue, fmt.Errorf("expected *v1.Pod in new object, got %T", newObj)
+	}
+	if newPod.Namespace == pod.Namespace && newPod.Name == pod.Name {
+		return framework.QueueSkip, nil
+	}
+
+	podGroupName, _, err := utils.GetPodGroupLabels(pod)
+	if err != nil || podGroupName == "" {
+		return framework.Queue, err
+	}
+
+	if !isSamePodGroup(newPod, pod.Namespace, podGroupName) {
+		return framework.QueueSkip, nil
+	}
+	// A label update on a pod that was already a member does not change the group size
+	if oldPod, ok := oldObj.(*v1.Pod); ok && isSamePodGroup(oldPod, pod.Namespace, podGroupName) {
+		return framework.QueueSkip, nil
+	}
+
+	if !cs.hasEnoughMembers(pod) {
+		logger.V(5).Info("Gang member arrived but group is still below minAvailable",
+			"pod", klog.KObj(pod), "newMember", klog.KObj(newPod), "podGroup", podGroupName)
+		return framework.QueueSkip, nil
+	}
+
+	logger.V(4).Info("Gang member arrived, requeueing gang",
+		"pod", klog.KObj(pod), "newMember", klog.KObj(newPod), "podGroup", podGroupName)
+	return framework.Queue, nil
+}
+
+// isSchedulableAfterPodDeleted requeues the gang when a bound pod is deleted, since that frees
+// capacity on its node. Deleting a pending pod frees nothing and is skipped.
+func (cs *Coscheduling) isSchedulableAfterPodDeleted(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	if d, ok := oldObj.(cache.DeletedFinalStateUnknown); ok {
+		oldObj = d.Obj
+	}
+	deletedPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return framework.Queue, fmt.Errorf("expected *v1.Pod in old object, got %T", oldObj)
+	}
+	if deletedPod.Spec.NodeName == "" {
+		return framework.QueueSkip, nil
+	}
+
+	if !cs.hasEnoughMembers(pod) {
+		return framework.QueueSkip, nil
+	}
+
+	logger.V(4).Info("Bound pod deleted, requeueing gang",
+		"pod", klog.KObj(pod), "deletedPod", klog.KObj(deletedPod), "node", deletedPod.Spec.NodeName)
+	return framework.Queue, nil
+}
+
+// isSchedulableAfterReservationDeleted requeues the gang when another gang's ResourceReservation
+// is released. Releasing the gang's own reservation hands back nothing it could not already use.
+func (cs *Coscheduling) isSchedulableAfterReservationDeleted(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	if d, ok := oldObj.(cache.DeletedFinalStateUnknown); ok {
+		oldObj = d.Obj
+	}
+	reservation, err := meta.Accessor(oldObj)
+	if err != nil {
+		return framework.Queue, err
+	}
+
+	podGroupName, _, err := utils.GetPodGroupLabels(pod)
+	if err != nil || podGroupName == "" {
+		return framework.Queue, err
+	}
+	if reservation.GetNamespace() == pod.Namespace && reservation.GetLabels()[reservationPodGroupLabel] == podGroupName {
+		return framework.QueueSkip, nil
+	}
+
+	if !cs.hasEnoughMembers(pod) {
+		return framework.QueueSkip, nil
+	}
+
+	logger.V(4).Info("ResourceReservation released, requeueing gang",
+		"pod", klog.KObj(pod), "reservation", klog.KRef(reservation.GetNamespace(), reservation.GetName()))
+	return framework.Queue, nil
+}
+
+// activateOnQuorum moves the group's pending members back to the active queue the first
+// time the group is seen with minAvailable members. The Add event of an unscheduled pod
+// only reaches the queueing hints with the GangScheduling feature gate on, so without
+// this the members rejected before the last one arrived would wait for the periodic
+// flush of unschedulable pods.
+func (cs *Coscheduling) activateOnQuorum(ctx context.Context, p *v1.Pod, podGroupName string) {
+	if cs.frameworkHandle == nil {
+		return
+	}
+	key := utils.GetPodGroupKey(p.Namespace, podGroupName)
+	if _, activated := cs.quorumActivated.LoadOrStore(key, time.Now()); activated {
+		return
+	}
+
+	pending := make(map[string]*v1.Pod)
+	for _, member := range cs.listGroupPods(podGroupName, p.Namespace) {
+		if member.Spec.NodeName == "" && member.UID != p.UID && member.Name != p.Name {
+			pending[member.Namespace+"/"+member.Name] = member
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	klog.V(4).InfoS("PreFilter: pod group reached minAvailable, activating pending members",
+		"namespace", p.Namespace, "podGroup", podGroupName, "pods", len(pending))
+	cs.frameworkHandle.Activate(klog.FromContext(ctx), pending)
+}
+
+// hasEnoughMembers reports whether the pod's group has at least minAvailable pods created.
+// Until it does, PreFilter rejects the gang regardless of free capacity.
+func (cs *Coscheduling) hasEnoughMembers(pod *v1.Pod) bool {
+	podGroupName, minAvailable, err := utils.GetPodGroupLabels(pod)
+	if err != nil || podGroupName == "" {
+		return true
+	}
+	return cs.calculateTotalPods(podGroupName, pod.Namespace) >= minAvailable
+}
+
+// isSamePodGroup reports whether the pod is labeled as a member of the given pod group.
+func isSamePodGroup(pod *v1.Pod, namespace, podGroupName string) bool {
+	if pod.Namespace != namespace {
+		return false
+	}
+	name, _, err := utils.GetPodGroupLabels(pod)
+	return err == nil && name == podGroupName
+}
+
 // Permit controls when pods are allowed to proceed to binding
 func (cs *Coscheduling) Permit(ctx context.Context, state framework.CycleState, p *v1.Pod, nodeName string) (*framework.Status, time.Duration) {
 	podGroupName, minAvailable, err := utils.GetPodGroupLabels(p)
@@ -375,6 +542,11 @@ func (cs *Coscheduling) Unreserve(ctx context.Context, state framework.CycleStat
 }
 
 func (cs *Coscheduling) calculateTotalPods(podGroupName, namespace string) int {
+	return len(cs.listGroupPods(podGroupName, namespace))
+}
+
+// listGroupPods returns the pods labeled as members of the pod group
+func (cs *Coscheduling) listGroupPods(podGroupName, namespace string) []*v1.Pod {
 	// Try new label first
 	selector := labels.Set{"pod-group.scheduling.kubenexus.io/name": podGroupName}.AsSelector()
 	pods, err := cs.podLister.Pods(namespace).List(selector)
@@ -383,11 +555,11 @@ func (cs *Coscheduling) calculateTotalPods(podGroupName, namespace string) int {
 		selector = labels.Set{PodGroupName: podGroupName}.AsSelector()
 		pods, err = cs.podLister.Pods(namespace).List(selector)
 		if err != nil {
-			klog.ErrorS(err, "calculateTotalPods: error listing pods")
-			return 0
+			klog.ErrorS(err, "listGroupPods: error listing pods")
+			return nil
 		}
 	}
-	return len(pods)
+	return pods
 }
 
 func (cs *Coscheduling) calculateRunningPodsExcluding(podGroupName, namespace string, excludeName string) int {
@@ -482,6 +654,12 @@ func (cs *Coscheduling) cleanupStaleEntries() {
 				}
 				return true
 			})
+			cs.quorumActivated.Range(func(key, value interface{}) bool {
+				if activatedAt, ok := value.(time.Time); !ok || now.Sub(activatedAt) > staleEntryTTL {
+					cs.quorumActivated.Delete(key)
+				}
+				return true
+			})
 		}
 	}
 }
@@ -18,14 +18,24 @@ package coscheduling
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
 	fwk "k8s.io/kube-scheduler/framework"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/backend/queue"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
 	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
 )
@@ -232,6 +242,61 @@ func TestPreFilterWithFramework(t *testing.T) {
 	}
 }
 
+// TestPreFilterActivatesGangOnQuorum runs a gang through a scheduling queue: the first
+// member is parked as unschedulable, and the arrival of the last member moves it back
+// to the active queue without waiting for an event or the unschedulable flush.
+func TestPreFilterActivatesGangOnQuorum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := klog.FromContext(ctx)
+
+	member := func(name string) *v1.Pod {
+		pod := testutil.MakePod(name, "default", "", v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			map[string]string{PodGroupName: "training-job", PodGroupMinAvailable: "2"}, nil)
+		pod.UID = types.UID(name)
+		return pod
+	}
+	first, last := member("gang-pod-1"), member("gang-pod-2")
+
+	metrics.Register()
+	queue := internalqueue.NewTestQueue(ctx, (&Coscheduling{}).Less)
+	fh, err := testutil.NewTestFramework(nil, frameworkruntime.WithPodActivator(queue))
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+
+	// The first member is rejected for want of members and parked
+	queue.Add(logger, first)
+	queuedInfo, err := queue.Pop(logger)
+	if err != nil {
+		t.Fatalf("Pop() error = %v", err)
+	}
+	plugin := &Coscheduling{podLister: testutil.NewFakePodLister([]*v1.Pod{first}), frameworkHandle: fh}
+	if _, status := plugin.PreFilter(ctx, framework.NewCycleState(), first, nil); status.Code() != fwk.Unschedulable {
+		t.Fatalf("PreFilter(first member) = %v, want Unschedulable", status)
+	}
+	queuedInfo.UnschedulablePlugins = sets.New(Name)
+	if err := queue.AddUnschedulableIfNotPresent(logger, queuedInfo, queue.SchedulingCycle()); err != nil {
+		t.Fatalf("AddUnschedulableIfNotPresent() error = %v", err)
+	}
+	if _, summary := queue.PendingPods(); !strings.Contains(summary, "unschedulablePods:1") {
+		t.Fatalf("queue = %s, want the first member unschedulable", summary)
+	}
+
+	// The last member completes the quorum and activates the first
+	plugin.podLister = testutil.NewFakePodLister([]*v1.Pod{first, last})
+	if _, status := plugin.PreFilter(ctx, framework.NewCycleState(), last, nil); !status.IsSuccess() {
+		t.Fatalf("PreFilter(last member) = %v, want Success", status)
+	}
+	if _, summary := queue.PendingPods(); !strings.Contains(summary, "activeQ:1") {
+		t.Fatalf("queue = %s, want the first member active", summary)
+	}
+	popped, err := queue.Pop(logger)
+	if err != nil || popped.Pod.Name != first.Name {
+		t.Errorf("Pop() = %v, %v, want %s", popped, err, first.Name)
+	}
+}
+
 // TestTimeoutHandling tests gang scheduling timeout behavior
 func TestTimeoutHandling(t *testing.T) {
 	pod := testutil.MakePod("gang-pod-timeout", "default", "",
@@ -316,3 +381,143 @@ func TestMultipleGangGroups(t *testing.T) {
 
 	t.Logf("Gang group 1: Success, Gang group 2: Waiting (%v)", timeout)
 }
+
+// TestEventsToRegister verifies the cluster events that can requeue a rejected gang
+func TestEventsToRegister(t *testing.T) {
+	cs := &Coscheduling{}
+	events, err := cs.EventsToRegister(context.Background())
+	if err != nil {
+		t.Fatalf("EventsToRegister returned error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 registered events, got %d", len(events))
+	}
+	for _, e := range events {
+		if e.QueueingHintFn == nil {
+			t.Errorf("Event %s has no queueing hint", e.Event.Label())
+		}
+	}
+	if events[2].Event.Resource != "resourcereservations.v1alpha1.scheduling.kubenexus.io" {
+		t.Errorf("Unexpected reservation event resource: %s", events[2].Event.Resource)
+	}
+}
+
+// TestQueueingHints tests that gangs are requeued only on events that can help them
+func TestQueueingHints(t *testing.T) {
+	gangLabels := func(name string) map[string]string {
+		return map[string]string{
+			PodGroupName:         name,
+			PodGroupMinAvailable: "2",
+		}
+	}
+	cpu := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	member1 := testutil.MakePod("member-1", "default", "", cpu, gangLabels("training-job"), nil)
+	member2 := testutil.MakePod("member-2", "default", "", cpu, gangLabels("training-job"), nil)
+	otherGang := testutil.MakePod("other-1", "default", "", cpu, gangLabels("other-job"), nil)
+	boundPod := testutil.MakePod("bound", "default", "node-1", cpu, nil, nil)
+	pendingPod := testutil.MakePod("pending", "default", "", cpu, nil, nil)
+
+	reservation := func(namespace, podGroup string) *v1alpha1.ResourceReservation {
+		return &v1alpha1.ResourceReservation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podGroup + "-0",
+				Namespace: namespace,
+				Labels:    map[string]string{"pod-group": podGroup},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		existingPods []*v1.Pod
+		hint         func(cs *Coscheduling) fwk.QueueingHintFn
+		oldObj       interface{}
+		newObj       interface{}
+		expected     fwk.QueueingHint
+	}{
+		{
+			name:         "member added completes the gang",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodAddedOrRelabeled },
+			newObj:       member2,
+			expected:     fwk.Queue,
+		},
+		{
+			name:         "member added but gang still incomplete",
+			existingPods: []*v1.Pod{member1},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodAddedOrRelabeled },
+			newObj:       member2,
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "pod from another gang added",
+			existingPods: []*v1.Pod{member1, member2, otherGang},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodAddedOrRelabeled },
+			newObj:       otherGang,
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "label update on existing member",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodAddedOrRelabeled },
+			oldObj:       member2,
+			newObj:       member2,
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "bound pod deleted frees capacity",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodDeleted },
+			oldObj:       boundPod,
+			expected:     fwk.Queue,
+		},
+		{
+			name:         "bound pod deleted but gang incomplete",
+			existingPods: []*v1.Pod{member1},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodDeleted },
+			oldObj:       boundPod,
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "pending pod deleted",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterPodDeleted },
+			oldObj:       pendingPod,
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "other gang reservation released",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterReservationDeleted },
+			oldObj:       reservation("default", "other-job"),
+			expected:     fwk.Queue,
+		},
+		{
+			name:         "own reservation released",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterReservationDeleted },
+			oldObj:       reservation("default", "training-job"),
+			expected:     fwk.QueueSkip,
+		},
+		{
+			name:         "reservation released via tombstone",
+			existingPods: []*v1.Pod{member1, member2},
+			hint:         func(cs *Coscheduling) fwk.QueueingHintFn { return cs.isSchedulableAfterReservationDeleted },
+			oldObj:       cache.DeletedFinalStateUnknown{Obj: reservation("other-ns", "training-job")},
+			expected:     fwk.Queue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &Coscheduling{podLister: testutil.NewFakePodLister(tt.existingPods)}
+			hint, err := tt.hint(cs)(klog.Background(), member1, tt.oldObj, tt.newObj)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if hint != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, hint)
+			}
+		})
+	}
+}
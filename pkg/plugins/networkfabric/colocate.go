@@ -0,0 +1,216 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+// Strict co-location at a topology level:
+//   - co-locate: strict with co-locate-level: clique|fabric|rack|az keeps the whole gang
+//     in one domain of that level
+//   - PreFilter finds the domain of members already placed, including members reserved
+//     and waiting at Permit, and fails the pod when that domain, or with no member placed
+//     any domain of the level, has no room for the members still to be placed
+//   - Filter rejects nodes outside the gang's domain, or in domains too small for it
+//   - strict without a level keeps the NVLink clique filter of require-clique
+
+const (
+	// AnnotationCoLocateLevel names the topology level co-locate: strict is enforced at
+	AnnotationCoLocateLevel = "scheduling.kubenexus.io/co-locate-level" // clique|fabric|rack|az
+
+	coLocationStateKey = Name + "/co-location"
+)
+
+var _ framework.PreFilterPlugin = &NetworkFabricScore{}
+
+// coLocation is the strict co-location constraint of the pod's gang for this cycle
+type coLocation struct {
+	Level    topologyLevel
+	PodGroup string
+	Domain   string         // Domain of the placed members, empty when none is placed
+	Fitting  map[string]int // Domains with room for the gang when none is placed -> free slots
+	Needed   int            // Members still to be placed, this pod included
+}
+
+// Clone implements framework.StateData
+func (c *coLocation) Clone() framework.StateData {
+	return c
+}
+
+// strictCoLocateLevel returns the topology level a strict gang must stay within, or
+// false when the pod doesn't ask for strict co-location at a named level
+func strictCoLocateLevel(pod *v1.Pod) (topologyLevel, bool) {
+	if strings.ToLower(pod.Annotations[AnnotationCoLocate]) != "strict" {
+		return topologyLevel{}, false
+	}
+	value := strings.ToLower(pod.Annotations[AnnotationCoLocateLevel])
+	if value == "" {
+		return topologyLevel{}, false
+	}
+	for _, level := range topologyLevels {
+		if level.name == value {
+			return level, true
+		}
+	}
+	klog.V(4).InfoS("NetworkFabricScore: ignoring unknown co-locate level", "pod", klog.KObj(pod), "level", value)
+	return topologyLevel{}, false
+}
+
+// PreFilter resolves the domain a strict gang is held to and fails the pod early
+// when no single domain can hold the rest of the gang, rather than scattering it.
+func (nf *NetworkFabricScore) PreFilter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodes []framework.NodeInfo) (*framework.PreFilterResult, *framework.Status) {
+	podGroup := pod.Annotations[utils.AnnotationPodGroup]
+	if podGroup == "" {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	level, strict := strictCoLocateLevel(pod)
+	if !strict {
+		if nf.requiresCliqueFilter(pod) {
+			return nil, framework.NewStatus(framework.Success)
+		}
+		return nil, framework.NewStatus(framework.Skip)
+	}
+
+	constraint, status := nf.resolveCoLocation(state, pod, podGroup, level, nodes)
+	if !status.IsSuccess() {
+		klog.V(3).InfoS("NetworkFabricScore: strict gang cannot be co-located", "pod", klog.KObj(pod), "podGroup", podGroup, "level", level.name, "reason", status.Message())
+		return nil, status
+	}
+	state.Write(coLocationStateKey, constraint)
+	return nil, framework.NewStatus(framework.Success)
+}
+
+// PreFilterExtensions returns nil
+func (nf *NetworkFabricScore) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// resolveCoLocation finds the gang's domain at the level from members already placed
+// on the nodes, and checks that it, or some domain when none is placed, has room for
+// the members left to place.
+func (nf *NetworkFabricScore) resolveCoLocation(state framework.CycleState, pod *v1.Pod, podGroup string, level topologyLevel, nodes []framework.NodeInfo) (*coLocation, *framework.Status) {
+	placed := 0
+	members := make(map[string]int) // domain -> placed members
+	for _, nodeInfo := range nodes {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		for _, podInfo := range nodeInfo.GetPods() {
+			member := podInfo.GetPod()
+			if member.Namespace != pod.Namespace || member.Name == pod.Name || member.Annotations[utils.AnnotationPodGroup] != podGroup {
+				continue
+			}
+			placed++
+			if domain := nf.nodeDomain(state, level, node); domain != "" {
+				members[domain]++
+			}
+		}
+	}
+
+	constraint := &coLocation{
+		Level:    level,
+		PodGroup: podGroup,
+		Needed:   max(nf.gangSize(state, pod, podGroup)-placed, 1),
+	}
+	free := make(map[string]int)
+	for node, slots := range memberSlots(pod, nodes) {
+		if domain := nf.nodeDomain(state, level, node); domain != "" {
+			free[domain] += slots
+		}
+	}
+
+	if len(members) > 0 {
+		// Members already split across domains are held to the domain with the most of them
+		for _, domain := range sortedKeys(members) {
+			if constraint.Domain == "" || members[domain] > members[constraint.Domain] {
+				constraint.Domain = domain
+			}
+		}
+		if free[constraint.Domain] < constraint.Needed {
+			return nil, framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("gang %s needs %d more member(s) in %s %s, which has room for %d",
+					podGroup, constraint.Needed, level.name, constraint.Domain, free[constraint.Domain]))
+		}
+		return constraint, nil
+	}
+
+	constraint.Fitting = make(map[string]int)
+	largest := 0
+	for domain, slots := range free {
+		largest = max(largest, slots)
+		if slots >= constraint.Needed {
+			constraint.Fitting[domain] = slots
+		}
+	}
+	if len(constraint.Fitting) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("no single %s can hold the %d member(s) of gang %s, the largest has room for %d",
+				level.name, constraint.Needed, podGroup, largest))
+	}
+	return constraint, nil
+}
+
+// filterCoLocation rejects nodes outside the strict gang's domain
+func (nf *NetworkFabricScore) filterCoLocation(state framework.CycleState, constraint *coLocation, node *v1.Node) *framework.Status {
+	domain := nf.nodeDomain(state, constraint.Level, node)
+	switch {
+	case domain == "":
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node %s has no %s label, but gang %s requires strict %s co-location",
+				node.Name, constraint.Level.name, constraint.PodGroup, constraint.Level.name))
+	case constraint.Domain != "" && domain != constraint.Domain:
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node %s is in %s %s, but gang %s is co-located in %s %s",
+				node.Name, constraint.Level.name, domain, constraint.PodGroup, constraint.Level.name, constraint.Domain))
+	case constraint.Domain == "" && constraint.Fitting[domain] == 0:
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("node %s is in %s %s, which has no room for the %d member(s) of gang %s",
+				node.Name, constraint.Level.name, domain, constraint.Needed, constraint.PodGroup))
+	}
+	return nil
+}
+
+// getCoLocation returns the strict co-location constraint PreFilter wrote, if any
+func getCoLocation(state framework.CycleState) *coLocation {
+	if state == nil {
+		return nil
+	}
+	data, err := state.Read(coLocationStateKey)
+	if err != nil {
+		return nil
+	}
+	constraint, _ := data.(*coLocation)
+	return constraint
+}
+
+// nodeDomain returns the node's domain at a topology level
+func (nf *NetworkFabricScore) nodeDomain(state framework.CycleState, level topologyLevel, node *v1.Node) string {
+	if level.label == LabelGPUClique {
+		return nf.getNodeClique(state, node)
+	}
+	return node.Labels[level.label]
+}
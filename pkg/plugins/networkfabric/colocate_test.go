@@ -0,0 +1,179 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// strictMember builds a gangMember held to one domain of the level
+func strictMember(name, nodeName, minAvailable, level string) *v1.Pod {
+	pod := gangMember(name, nodeName, "train", minAvailable)
+	pod.Annotations[AnnotationCoLocate] = "strict"
+	pod.Annotations[AnnotationCoLocateLevel] = level
+	return pod
+}
+
+func TestStrictCoLocateLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		coLocate  string
+		level     string
+		wantLevel string
+		wantOK    bool
+	}{
+		{name: "strict rack", coLocate: "strict", level: "rack", wantLevel: "rack", wantOK: true},
+		{name: "strict AZ, any case", coLocate: "Strict", level: "AZ", wantLevel: "az", wantOK: true},
+		{name: "strict without level", coLocate: "strict"},
+		{name: "preferred rack", coLocate: "preferred", level: "rack"},
+		{name: "unknown level", coLocate: "strict", level: "row"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := gangMember("p", "", "train", "2")
+			pod.Annotations[AnnotationCoLocate] = tt.coLocate
+			if tt.level != "" {
+				pod.Annotations[AnnotationCoLocateLevel] = tt.level
+			}
+			level, ok := strictCoLocateLevel(pod)
+			if ok != tt.wantOK || level.name != tt.wantLevel {
+				t.Errorf("strictCoLocateLevel() = %q, %v, want %q, %v", level.name, ok, tt.wantLevel, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFilterHoldsStrictGangToItsRack(t *testing.T) {
+	nodes := []*v1.Node{
+		gpuNode("a-1", "rack-a", "az-1"),
+		gpuNode("a-2", "rack-a", "az-1"),
+		gpuNode("b-1", "rack-b", "az-1"),
+		gpuNode("b-2", "rack-b", "az-1"),
+		bareGPUNode("bare"),
+	}
+	placed := strictMember("train-0", "a-1", "2", "rack")
+	pod := strictMember("train-1", "", "2", "rack")
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{placed, pod}, nodes)
+
+	state := framework.NewCycleState()
+	if _, status := plugin.PreFilter(context.Background(), state, pod, nodeInfos); !status.IsSuccess() {
+		t.Fatalf("PreFilter = %v, want Success", status.Message())
+	}
+
+	tests := []struct {
+		node       int
+		wantOK     bool
+		wantReason string
+	}{
+		{node: 1, wantOK: true},
+		{node: 2, wantReason: "node b-1 is in rack rack-b, but gang train is co-located in rack rack-a"},
+		{node: 4, wantReason: "node bare has no rack label"},
+	}
+	for _, tt := range tests {
+		status := plugin.Filter(context.Background(), state, pod, nodeInfos[tt.node])
+		name := nodeInfos[tt.node].Node().Name
+		if status.IsSuccess() != tt.wantOK {
+			t.Errorf("Filter(%s) = %v, want success %v", name, status.Code(), tt.wantOK)
+			continue
+		}
+		if !tt.wantOK && !strings.Contains(status.Message(), tt.wantReason) {
+			t.Errorf("Filter(%s) reason = %q, want %q", name, status.Message(), tt.wantReason)
+		}
+	}
+}
+
+func TestFilterSteersFirstStrictMemberToFittingDomain(t *testing.T) {
+	nodes := []*v1.Node{
+		gpuNode("a-1", "rack-a", "az-1"),
+		gpuNode("a-2", "rack-a", "az-1"),
+		gpuNode("a-3", "rack-a", "az-1"),
+		gpuNode("b-1", "rack-b", "az-1"),
+	}
+	pod := strictMember("train-0", "", "3", "rack")
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{pod}, nodes)
+
+	state := framework.NewCycleState()
+	if _, status := plugin.PreFilter(context.Background(), state, pod, nodeInfos); !status.IsSuccess() {
+		t.Fatalf("PreFilter = %v, want Success", status.Message())
+	}
+	if status := plugin.Filter(context.Background(), state, pod, nodeInfos[0]); !status.IsSuccess() {
+		t.Errorf("Filter(a-1) = %v, want Success", status.Message())
+	}
+	status := plugin.Filter(context.Background(), state, pod, nodeInfos[3])
+	if status.Code() != fwk.Unschedulable || !strings.Contains(status.Message(), "has no room for the 3 member(s)") {
+		t.Errorf("Filter(b-1) = %v %q, want Unschedulable for lack of room", status.Code(), status.Message())
+	}
+}
+
+func TestPreFilterFailsFastWhenNoDomainHoldsGang(t *testing.T) {
+	nodes := []*v1.Node{
+		gpuNode("a-1", "rack-a", "az-1"),
+		gpuNode("a-2", "rack-a", "az-1"),
+		gpuNode("b-1", "rack-b", "az-1"),
+		gpuNode("b-2", "rack-b", "az-1"),
+	}
+
+	t.Run("no domain fits", func(t *testing.T) {
+		pod := strictMember("train-0", "", "3", "rack")
+		plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{pod}, nodes)
+		_, status := plugin.PreFilter(context.Background(), framework.NewCycleState(), pod, nodeInfos)
+		if status.Code() != fwk.Unschedulable || !strings.Contains(status.Message(), "no single rack can hold the 3 member(s) of gang train") {
+			t.Errorf("PreFilter = %v %q, want Unschedulable", status.Code(), status.Message())
+		}
+	})
+
+	t.Run("established domain is full", func(t *testing.T) {
+		placed := strictMember("train-0", "a-1", "3", "rack")
+		pod := strictMember("train-1", "", "3", "rack")
+		plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{placed, pod}, nodes)
+		_, status := plugin.PreFilter(context.Background(), framework.NewCycleState(), pod, nodeInfos)
+		if status.Code() != fwk.Unschedulable || !strings.Contains(status.Message(), "needs 2 more member(s) in rack rack-a, which has room for 1") {
+			t.Errorf("PreFilter = %v %q, want Unschedulable", status.Code(), status.Message())
+		}
+	})
+
+	t.Run("broader level fits", func(t *testing.T) {
+		pod := strictMember("train-0", "", "3", "az")
+		plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{pod}, nodes)
+		if _, status := plugin.PreFilter(context.Background(), framework.NewCycleState(), pod, nodeInfos); !status.IsSuccess() {
+			t.Errorf("PreFilter = %v, want Success", status.Message())
+		}
+	})
+}
+
+func TestPreFilterSkipsPodsWithoutHardConstraint(t *testing.T) {
+	preferred := gangMember("train-0", "", "train", "2")
+	preferred.Annotations[AnnotationCoLocate] = "preferred"
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{preferred}, []*v1.Node{gpuNode("a-1", "rack-a", "az-1")})
+	if _, status := plugin.PreFilter(context.Background(), framework.NewCycleState(), preferred, nodeInfos); status.Code() != fwk.Skip {
+		t.Errorf("PreFilter = %v, want Skip", status.Code())
+	}
+}
+
+// bareGPUNode builds a gpuNode without topology labels
+func bareGPUNode(name string) *v1.Node {
+	node := gpuNode(name, "", "")
+	delete(node.Labels, LabelRackID)
+	delete(node.Labels, LabelAZ)
+	return node
+}
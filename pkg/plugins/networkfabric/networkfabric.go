@@ -30,8 +30,15 @@ limitations under the License.
 // This plugin provides both hard filtering and soft scoring across all topology levels:
 //   - Filter: For gang pods with require-clique or strict co-location, hard-reject nodes
 //     in the wrong NVLink partition (cross-partition = 10-50x bandwidth drop)
+//   - PreFilter/Filter: co-locate: strict with a co-locate-level holds the gang to one
+//     clique, fabric domain, rack or AZ, and fails fast when no such domain has room
+//   - PreScore: Plans the whole gang into the tightest topology domain with room for it,
+//     so members follow one plan rather than wherever the first member landed
 //   - Score: Multi-level locality scoring that packs gang members into the tightest
 //     topology domain: NVLink clique (+40) > fabric domain (+30) > rack (+20) > AZ (+10)
+//   - Score: Tensor- and pipeline-parallel gangs on rail-optimized fabrics prefer nodes
+//     whose rail i reaches the same rail switch as rail i of the placed members (±20)
+//   - Reserve: Reports the rail alignment of the chosen node
 //
 // TOPOLOGY HIERARCHY (tightest -> broadest):
 //  1. NVLink Clique: nvidia.com/gpu.clique (NVIDIA DRA driver / ComputeDomain)
@@ -39,6 +46,10 @@ limitations under the License.
 //  3. Rack: network.kubenexus.io/rack-id
 //  4. Availability Zone: network.kubenexus.io/az
 //
+// When a NetworkTopology object (scheduling.kubenexus.io/v1alpha1) describes the switch
+// tree, nodes it covers are scored by link bandwidth and oversubscription along the
+// tree instead of levels 2-4, e.g. superpod -> scalable unit -> leaf switch -> node.
+//
 // NETWORK FABRIC TIERS (from best to worst):
 //  1. NVSwitch Fabric: GPU-to-GPU direct, 900 GB/s, <1μs latency (DGX SuperPods)
 //  2. NVLink Domain: GPU-to-GPU in single node, 600 GB/s, <2μs latency
@@ -59,13 +70,17 @@ limitations under the License.
 //	network.kubenexus.io/rack-id: "<rack-identifier>"
 //	network.kubenexus.io/az: "<availability-zone>"
 //	nvidia.com/gpu.clique: "<NVLink-partition-id>"  (set by NVIDIA DRA driver)
+//	network.kubenexus.io/rail-<i>-switch: "<rail-switch>"  (fallback for DRA rail/rail-switch attributes)
 //
 // POD ANNOTATIONS (optional overrides):
 //
 //	scheduling.kubenexus.io/network-sensitive: "true|false"  # Boost scoring weight
 //	scheduling.kubenexus.io/min-fabric-tier: "nvswitch|infiniband|roce"  # Minimum required
 //	scheduling.kubenexus.io/co-locate: "strict|preferred|none"  # Gang locality requirement
+//	scheduling.kubenexus.io/co-locate-level: "clique|fabric|rack|az"  # Level of strict (default clique)
 //	scheduling.kubenexus.io/require-clique: "true"  # Hard: filter to same NVLink partition
+//	scheduling.kubenexus.io/tensor-parallel: "8"  # With pipeline-parallel, enables rail alignment
+//	scheduling.kubenexus.io/pipeline-parallel: "4"
 //
 // EXAMPLE TOPOLOGY:
 //
@@ -90,28 +105,41 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	resourcev1listers "k8s.io/client-go/listers/resource/v1"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
 
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/snapshot"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/workload"
 )
 
 // NetworkFabricScore implements network topology-aware scoring and NVLink
 // partition filtering for gang scheduling. It provides:
+//   - PreFilter: Strict gang co-location domain and fail-fast capacity check
 //   - Filter: Hard rejection of nodes in the wrong NVLink clique for gang pods
+//   - PreScore: Whole-gang placement plan over the topology tree
 //   - Score: Multi-level locality scoring (clique > fabric-id > rack > AZ)
+//   - Reserve: Rail alignment reporting for tensor- and pipeline-parallel gangs
 type NetworkFabricScore struct {
 	handle              framework.Handle
 	podLister           corelisters.PodLister
 	nodeLister          corelisters.NodeLister
 	resourceSliceLister resourcev1listers.ResourceSliceLister // DRA fallback for clique discovery
+	topology            *topologyCache                        // NetworkTopology tree, nil when the CRD is absent
+	index               *snapshot.Builder                     // Per-cycle slices and gang members
+
+	mu    sync.Mutex
+	plans map[string]*placementPlan // namespace/pod-group -> planned topology domain
 }
 
 var _ framework.FilterPlugin = &NetworkFabricScore{}
@@ -136,7 +164,6 @@ const (
 	AnnotationNetworkSensitive = "scheduling.kubenexus.io/network-sensitive" // true|false
 	AnnotationMinFabricTier    = "scheduling.kubenexus.io/min-fabric-tier"   // nvswitch|infiniband|roce
 	AnnotationCoLocate         = "scheduling.kubenexus.io/co-locate"         // strict|preferred|none
-	AnnotationPodGroup         = "pod-group.scheduling.sigs.k8s.io/name"     // Gang group name
 	AnnotationRequireClique    = "scheduling.kubenexus.io/require-clique"    // true = hard NVLink clique filter
 
 	// Fabric tier scores (higher is better)
@@ -165,6 +192,8 @@ const (
 	BoostInferenceLowTier    = 10 // Inference workloads can tolerate lower-tier fabrics
 	PenaltyTrainingLowTier   = 20 // Training workloads on low-tier fabrics (performance penalty)
 	PenaltyInferenceHighTier = 5  // Minor penalty for wasting high-tier fabric on inference
+	BoostFineTuningHighTier  = 8  // Fine-tuning benefits from high bandwidth but is smaller and checkpointable
+	PenaltyDataPrepHighTier  = 10 // CPU-bound preprocessing and sandboxes should not occupy high-tier fabric
 )
 
 // FabricType represents different network fabric technologies.
@@ -186,7 +215,8 @@ func (nf *NetworkFabricScore) Name() string {
 
 // Filter rejects nodes in the wrong NVLink clique for gang pods that require
 // strict clique co-location. This prevents cross-partition placement that would
-// force fallback to InfiniBand/Ethernet with 10-50x bandwidth degradation.
+// force fallback to InfiniBand/Ethernet with 10-50x bandwidth degradation. Gangs
+// with strict co-location at another level are held to their fabric domain, rack or AZ.
 func (nf *NetworkFabricScore) Filter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
 	node := nodeInfo.Node()
 	if node == nil {
@@ -194,19 +224,28 @@ func (nf *NetworkFabricScore) Filter(ctx context.Context, state framework.CycleS
 	}
 
 	// Only enforce clique filtering for gang pods with strict co-locate or require-clique
-	podGroup := pod.Annotations[AnnotationPodGroup]
+	podGroup := pod.Annotations[utils.AnnotationPodGroup]
 	if podGroup == "" {
 		return framework.NewStatus(framework.Success)
 	}
 
+	// Strict co-location at a named level, resolved by PreFilter
+	if constraint := getCoLocation(state); constraint != nil {
+		if status := nf.filterCoLocation(state, constraint, node); status != nil {
+			klog.V(3).InfoS("NetworkFabricScore: filtered node outside strict gang domain",
+				"pod", pod.Name, "node", node.Name, "level", constraint.Level.name, "reason", status.Message())
+			return status
+		}
+	}
+
 	if !nf.requiresCliqueFilter(pod) {
 		return framework.NewStatus(framework.Success)
 	}
 
-	candidateClique := nf.getNodeClique(node)
+	candidateClique := nf.getNodeClique(state, node)
 	if candidateClique == "" {
 		// Node is not in a NVLink environment — check if gang already has clique members
-		existingClique := nf.getGangClique(pod.Namespace, podGroup)
+		existingClique := nf.getGangClique(state, pod.Namespace, podGroup)
 		if existingClique != "" {
 			return framework.NewStatus(framework.Unschedulable,
 				fmt.Sprintf("node %s has no NVLink partition label, but gang %s requires clique %s",
@@ -216,7 +255,7 @@ func (nf *NetworkFabricScore) Filter(ctx context.Context, state framework.CycleS
 	}
 
 	// If gang members already scheduled, must match their clique
-	existingClique := nf.getGangClique(pod.Namespace, podGroup)
+	existingClique := nf.getGangClique(state, pod.Namespace, podGroup)
 	if existingClique == "" {
 		return framework.NewStatus(framework.Success)
 	}
@@ -233,20 +272,22 @@ func (nf *NetworkFabricScore) Filter(ctx context.Context, state framework.CycleS
 	return framework.NewStatus(framework.Success)
 }
 
-// requiresCliqueFilter checks if the pod needs hard NVLink clique filtering.
+// requiresCliqueFilter checks if the pod needs hard NVLink clique filtering. Strict
+// co-location without a co-locate-level means the NVLink clique.
 func (nf *NetworkFabricScore) requiresCliqueFilter(pod *v1.Pod) bool {
 	if pod.Annotations[AnnotationRequireClique] == "true" {
 		return true
 	}
 	if strings.ToLower(pod.Annotations[AnnotationCoLocate]) == "strict" {
-		return true
+		_, hasLevel := strictCoLocateLevel(pod)
+		return !hasLevel
 	}
 	return false
 }
 
 // getGangClique returns the NVLink partition that already-scheduled gang members are placed in.
-func (nf *NetworkFabricScore) getGangClique(namespace, podGroup string) string {
-	gangPods := nf.getGangMemberPods(namespace, podGroup)
+func (nf *NetworkFabricScore) getGangClique(state framework.CycleState, namespace, podGroup string) string {
+	gangPods := nf.getGangMemberPods(state, namespace, podGroup)
 	for _, gangPod := range gangPods {
 		if gangPod.Spec.NodeName == "" {
 			continue
@@ -255,7 +296,7 @@ func (nf *NetworkFabricScore) getGangClique(namespace, podGroup string) string {
 		if err != nil {
 			continue
 		}
-		if clique := nf.getNodeClique(node); clique != "" {
+		if clique := nf.getNodeClique(state, node); clique != "" {
 			return clique
 		}
 	}
@@ -265,24 +306,14 @@ func (nf *NetworkFabricScore) getGangClique(namespace, podGroup string) string {
 // getNodeClique returns the NVLink partition ID for a node using graceful degradation:
 //  1. Node label nvidia.com/gpu.clique (set by NVIDIA DRA driver)
 //  2. DRA ResourceSlice nvlink-domain attribute (K8s 1.34+)
-func (nf *NetworkFabricScore) getNodeClique(node *v1.Node) string {
+func (nf *NetworkFabricScore) getNodeClique(state framework.CycleState, node *v1.Node) string {
 	// Priority 1: Node label (fastest, set by NVIDIA GPU operator / DRA driver)
 	if clique := node.Labels[LabelGPUClique]; clique != "" {
 		return clique
 	}
 
 	// Priority 2: DRA ResourceSlice attributes (fallback for environments without label)
-	if nf.resourceSliceLister == nil {
-		return ""
-	}
-	allSlices, err := nf.resourceSliceLister.List(labels.Everything())
-	if err != nil {
-		return ""
-	}
-	for _, slice := range allSlices {
-		if slice.Spec.NodeName == nil || *slice.Spec.NodeName != node.Name {
-			continue
-		}
+	for _, slice := range nf.nodeResourceSlices(state, node.Name) {
 		if !isGPUDriver(slice.Spec.Driver) {
 			continue
 		}
@@ -324,7 +355,7 @@ func (nf *NetworkFabricScore) Score(ctx context.Context, state framework.CycleSt
 	}
 
 	// Check if pod is part of a gang (pod group)
-	podGroup := pod.Annotations[AnnotationPodGroup]
+	podGroup := pod.Annotations[utils.AnnotationPodGroup]
 	isGangMember := podGroup != ""
 
 	// Get network fabric topology for candidate node
@@ -342,18 +373,36 @@ func (nf *NetworkFabricScore) Score(ctx context.Context, state framework.CycleSt
 		return int64(baseScore), nil
 	}
 
+	// Steer every member into the domain planned for the whole gang
+	plan := getPlacementPlan(state)
+	planAdjustment := planScore(plan, node)
+
 	// For gang members, analyze existing pod placements
-	gangPods := nf.getGangMemberPods(pod.Namespace, podGroup)
+	gangPods := nf.getGangMemberPods(state, pod.Namespace, podGroup)
 
 	if len(gangPods) == 0 {
-		// First pod in gang, return base fabric score
-		klog.V(4).InfoS("NetworkFabricScore: scoring first pod in gang", "namespace", pod.Namespace, "pod", pod.Name, "podGroup", podGroup, "node", node.Name, "fabric", fabricType, "score", baseScore)
-		return int64(baseScore), nil
+		// First pod in gang, return base fabric score and plan adjustment
+		score := clampScore(baseScore + planAdjustment)
+		klog.V(4).InfoS("NetworkFabricScore: scoring first pod in gang", "namespace", pod.Namespace, "pod", pod.Name, "podGroup", podGroup, "node", node.Name, "fabric", fabricType, "planAdjustment", planAdjustment, "score", score)
+		return int64(score), nil
+	}
+
+	// Calculate locality bonuses/penalties based on gang member placement. The NVLink
+	// clique sits below the NetworkTopology tree's leaves, so it always counts; the
+	// fabric, rack and AZ levels come from the tree when it covers the node and
+	// members, else from the labels
+	candidateClique := nf.getNodeClique(state, node)
+	localityScore, fromTopology := nf.topologyLocalityScore(node, gangPods)
+	if fromTopology {
+		localityScore += calculateLocalityScore(gangPods, candidateClique, "", "", "", nf.nodeLister)
+	} else {
+		localityScore = calculateLocalityScore(gangPods, candidateClique, fabricID, rackID, az, nf.nodeLister)
 	}
+	localityScore += planAdjustment
 
-	// Calculate locality bonuses/penalties based on gang member placement
-	candidateClique := nf.getNodeClique(node)
-	localityScore := calculateLocalityScore(gangPods, candidateClique, fabricID, rackID, az, nf.nodeLister)
+	// Line tensor- and pipeline-parallel ranks up on the same rail switches
+	railAdjustment := nf.railScore(state, pod, node, gangPods)
+	localityScore += railAdjustment
 
 	// Apply workload-aware fabric tier adjustment
 	workloadAdjustment := nf.getWorkloadFabricBonus(state, pod, fabricType)
@@ -374,18 +423,38 @@ func (nf *NetworkFabricScore) Score(ctx context.Context, state framework.CycleSt
 	}
 
 	// Cap score at framework maximum
-	if finalScore > 100 {
-		finalScore = 100
-	}
-	if finalScore < 0 {
-		finalScore = 0
-	}
+	finalScore = clampScore(finalScore)
 
-	klog.V(4).InfoS("NetworkFabricScore: scored gang pod", "namespace", pod.Namespace, "pod", pod.Name, "podGroup", podGroup, "node", node.Name, "fabric", fabricType, "baseScore", baseScore, "localityScore", localityScore, "workloadAdjustment", workloadAdjustment, "finalScore", finalScore)
+	klog.V(4).InfoS("NetworkFabricScore: scored gang pod", "namespace", pod.Namespace, "pod", pod.Name, "podGroup", podGroup, "node", node.Name, "fabric", fabricType, "baseScore", baseScore, "localityScore", localityScore, "railAdjustment", railAdjustment, "workloadAdjustment", workloadAdjustment, "finalScore", finalScore)
 
 	return int64(finalScore), nil
 }
 
+// clampScore caps a score to the framework's [0, 100] range.
+func clampScore(score int) int {
+	if score > 100 {
+		return 100
+	}
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// topologyLocalityScore scores the node against placed gang members from the
+// NetworkTopology tree. It returns false when there is no tree covering them.
+func (nf *NetworkFabricScore) topologyLocalityScore(node *v1.Node, gangPods []*v1.Pod) (int, bool) {
+	model := nf.topology.current()
+	if model == nil {
+		return 0, false
+	}
+	members := make([]string, 0, len(gangPods))
+	for _, pod := range gangPods {
+		members = append(members, pod.Spec.NodeName)
+	}
+	return model.localityScore(node.Name, members)
+}
+
 // getFabricType extracts fabric type from node labels.
 func getFabricType(node *v1.Node) FabricType {
 	fabricStr := node.Labels[LabelFabricType]
@@ -423,12 +492,45 @@ func getFabricTierScore(fabricType FabricType) int {
 	}
 }
 
-// getGangMemberPods returns all scheduled pods in the same gang group.
-func (nf *NetworkFabricScore) getGangMemberPods(namespace, podGroup string) []*v1.Pod {
+// nodeResourceSlices returns the node's ResourceSlices from the cycle's snapshot index,
+// falling back to listing every slice when there is no index.
+func (nf *NetworkFabricScore) nodeResourceSlices(state framework.CycleState, nodeName string) []*resourcev1.ResourceSlice {
+	if idx := nf.index.Get(state); idx != nil {
+		return idx.Slices(nodeName)
+	}
+	if nf.resourceSliceLister == nil {
+		return nil
+	}
+	allSlices, err := nf.resourceSliceLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var slices []*resourcev1.ResourceSlice
+	for _, slice := range allSlices {
+		if slice.Spec.NodeName != nil && *slice.Spec.NodeName == nodeName {
+			slices = append(slices, slice)
+		}
+	}
+	return slices
+}
+
+// getGangMemberPods returns all scheduled pods in the same gang group. Pods reserved
+// in the scheduler's cache count as scheduled when the cycle's snapshot index is used.
+func (nf *NetworkFabricScore) getGangMemberPods(state framework.CycleState, namespace, podGroup string) []*v1.Pod {
 	if podGroup == "" {
 		return nil
 	}
 
+	if idx := nf.index.Get(state); idx != nil {
+		var gangPods []*v1.Pod
+		for _, pod := range idx.GangMembers(namespace, podGroup) {
+			if pod.Annotations[utils.AnnotationPodGroup] == podGroup {
+				gangPods = append(gangPods, pod)
+			}
+		}
+		return gangPods
+	}
+
 	allPods, err := nf.podLister.Pods(namespace).List(labels.Everything())
 	if err != nil {
 		klog.ErrorS(err, "NetworkFabricScore: failed to list pods", "namespace", namespace)
@@ -438,12 +540,12 @@ func (nf *NetworkFabricScore) getGangMemberPods(namespace, podGroup string) []*v
 	// Filter for gang members that are scheduled
 	var gangPods []*v1.Pod
 	for _, pod := range allPods {
-		// Only consider scheduled pods (have NodeName assigned)
-		if pod.Spec.NodeName == "" {
+		// Only consider scheduled pods (have NodeName assigned) that still run
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
 			continue
 		}
 		// Check if pod is in same gang group
-		if pod.Annotations[AnnotationPodGroup] == podGroup {
+		if pod.Annotations[utils.AnnotationPodGroup] == podGroup {
 			gangPods = append(gangPods, pod)
 		}
 	}
@@ -453,6 +555,7 @@ func (nf *NetworkFabricScore) getGangMemberPods(namespace, podGroup string) []*v
 
 // calculateLocalityScore computes bonus/penalty based on gang member co-location
 // across all topology levels: NVLink clique > fabric domain > rack > AZ.
+// Levels the candidate has no label for are not scored.
 func calculateLocalityScore(gangPods []*v1.Pod, candidateClique, candidateFabricID, candidateRackID, candidateAZ string, nodeLister corelisters.NodeLister) int {
 	if len(gangPods) == 0 {
 		return 0
@@ -549,6 +652,9 @@ func calculateLocalityScore(gangPods []*v1.Pod, candidateClique, candidateFabric
 //   - Inference workloads: Lower bandwidth needs, can use lower-tier fabrics
 //   - Batch workloads: Similar to training for distributed batch jobs
 //   - Service workloads: Similar to inference for serving endpoints
+//   - Distributed inference: Tensor/pipeline parallel across nodes, treated like training
+//   - Fine-tuning workloads: Smaller boost on high-tier fabrics, no low-tier penalty
+//   - Data-prep and sandbox workloads: Network-insensitive, steered away from high-tier fabrics
 //
 // Integration with ProfileClassifier:
 //   - Uses profile.WorkloadType for centralized classification
@@ -574,13 +680,19 @@ func (nf *NetworkFabricScore) getWorkloadFabricBonus(state framework.CycleState,
 		klog.V(5).InfoS("NetworkFabricScore: workload type from local classification", "namespace", pod.Namespace, "pod", pod.Name, "workloadType", workloadTypeStr)
 	}
 
+	return workloadFabricBonus(workloadTypeStr, fabricType)
+}
+
+// workloadFabricBonus returns the fabric tier bonus (positive) or penalty (negative)
+// for a normalized workload type.
+func workloadFabricBonus(workloadTypeStr string, fabricType FabricType) int {
 	// Classify fabric tier (high vs low)
 	isHighTierFabric := fabricType == FabricNVSwitch || fabricType == FabricInfiniBand
 	isLowTierFabric := fabricType == FabricRoCE || fabricType == FabricEthernet
 
 	// Apply workload-specific bonuses/penalties
 	switch workloadTypeStr {
-	case "training", "batch":
+	case "training", "batch", "distributed-inference":
 		// Training needs high bandwidth for collective operations (all-reduce, all-gather)
 		if isHighTierFabric {
 			klog.V(5).InfoS("NetworkFabricScore: training workload on high-tier fabric", "bonus", BoostTrainingHighTier)
@@ -591,6 +703,24 @@ func (nf *NetworkFabricScore) getWorkloadFabricBonus(state framework.CycleState,
 			return -PenaltyTrainingLowTier
 		}
 
+	case "fine-tuning":
+		// Fine-tuning jobs are smaller (often a single node) and checkpointable, so
+		// a faster fabric helps but a slower one is acceptable
+		if isHighTierFabric {
+			klog.V(5).InfoS("NetworkFabricScore: fine-tuning workload on high-tier fabric", "bonus", BoostFineTuningHighTier)
+			return BoostFineTuningHighTier
+		}
+
+	case "data-prep", "sandbox":
+		// Neither exchanges collectives; leave high-tier fabric to GPU jobs
+		if isLowTierFabric {
+			return BoostInferenceLowTier
+		}
+		if isHighTierFabric {
+			klog.V(5).InfoS("NetworkFabricScore: network-insensitive workload on high-tier fabric", "workloadType", workloadTypeStr, "penalty", -PenaltyDataPrepHighTier)
+			return -PenaltyDataPrepHighTier
+		}
+
 	case "inference", "service":
 		// Inference has lower bandwidth needs, can tolerate lower-tier fabrics
 		if isLowTierFabric {
@@ -662,10 +792,23 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		klog.V(3).InfoS("NetworkFabricScore: DRA ResourceSlice lister initialized for clique discovery")
 	}
 
+	// Watch the NetworkTopology tree when the CRD is installed
+	var topology *topologyCache
+	if handle.ClientSet() != nil && handle.KubeConfig() != nil {
+		dynamicClient, err := dynamic.NewForConfig(handle.KubeConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+		topology = newTopologyCache(ctx, handle.ClientSet().Discovery(), dynamicClient)
+	}
+
 	return &NetworkFabricScore{
 		handle:              handle,
+		topology:            topology,
 		podLister:           podLister,
 		nodeLister:          nodeLister,
 		resourceSliceLister: resourceSliceLister,
+		index:               snapshot.NewBuilder(handle),
+		plans:               make(map[string]*placementPlan),
 	}, nil
 }
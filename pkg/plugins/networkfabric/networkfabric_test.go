@@ -480,6 +480,11 @@ func TestRequiresCliqueFilter(t *testing.T) {
 			annotations: map[string]string{AnnotationCoLocate: "strict"},
 			want:        true,
 		},
+		{
+			name:        "strict co-locate at rack level - rack filter instead",
+			annotations: map[string]string{AnnotationCoLocate: "strict", AnnotationCoLocateLevel: "rack"},
+			want:        false,
+		},
 		{
 			name:        "preferred co-locate - no hard filter",
 			annotations: map[string]string{AnnotationCoLocate: "preferred"},
@@ -505,3 +510,32 @@ func TestRequiresCliqueFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkloadFabricBonus(t *testing.T) {
+	tests := []struct {
+		workloadType string
+		fabric       FabricType
+		expected     int
+	}{
+		{"training", FabricInfiniBand, BoostTrainingHighTier},
+		{"training", FabricEthernet, -PenaltyTrainingLowTier},
+		{"distributed-inference", FabricNVSwitch, BoostTrainingHighTier},
+		{"distributed-inference", FabricRoCE, -PenaltyTrainingLowTier},
+		{"fine-tuning", FabricInfiniBand, BoostFineTuningHighTier},
+		{"fine-tuning", FabricEthernet, 0},
+		{"data-prep", FabricInfiniBand, -PenaltyDataPrepHighTier},
+		{"data-prep", FabricEthernet, BoostInferenceLowTier},
+		{"sandbox", FabricNVSwitch, -PenaltyDataPrepHighTier},
+		{"inference", FabricRoCE, BoostInferenceLowTier},
+		{"interactive", FabricInfiniBand, 0},
+		{"training", FabricNVLink, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s", tt.workloadType, tt.fabric), func(t *testing.T) {
+			if got := workloadFabricBonus(tt.workloadType, tt.fabric); got != tt.expected {
+				t.Errorf("workloadFabricBonus(%q, %v) = %d, want %d", tt.workloadType, tt.fabric, got, tt.expected)
+			}
+		})
+	}
+}
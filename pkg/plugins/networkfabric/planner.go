@@ -0,0 +1,348 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	resourcehelper "k8s.io/component-helpers/resource"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+// Whole-gang placement planning:
+//   - PreScore picks the tightest topology domain (clique, then the smallest NetworkTopology
+//     subtree, then fabric-id, rack, AZ) whose feasible nodes have free room for every gang
+//     member still to be placed and that holds all members already placed
+//   - The plan is kept per gang, so later members are steered into the same domain
+//     instead of each member following whichever domain the first one landed in
+//   - A plan is kept while its domain still has room, and replanned otherwise
+
+const (
+	// BonusPlannedDomain is added to nodes inside the gang's planned domain
+	BonusPlannedDomain = 30
+	// PenaltyOutsidePlan is subtracted from nodes outside the gang's planned domain
+	PenaltyOutsidePlan = 30
+
+	placementPlanStateKey = Name + "/placement-plan"
+
+	// placementPlanTTL drops plans of gangs that stopped scheduling
+	placementPlanTTL = 30 * time.Minute
+)
+
+var _ framework.PreScorePlugin = &NetworkFabricScore{}
+
+// topologyLevel is one level of the gang topology tree
+type topologyLevel struct {
+	name  string
+	label string
+}
+
+// topologyLevels lists the gang topology tree from the tightest level to the broadest
+var topologyLevels = []topologyLevel{
+	{name: "clique", label: LabelGPUClique},
+	{name: "fabric", label: LabelFabricID},
+	{name: "rack", label: LabelRackID},
+	{name: "az", label: LabelAZ},
+}
+
+// placementPlan is the topology domain a gang is steered into
+type placementPlan struct {
+	Level     string          // Topology level name, e.g. rack
+	Label     string          // Node label of the level, empty for a NetworkTopology domain
+	Domain    string          // Label value or NetworkTopology domain of the plan
+	Nodes     map[string]bool // Nodes of a NetworkTopology domain
+	Needed    int             // Members still to be placed when planned
+	Capacity  int             // Free member slots in the domain when planned
+	UpdatedAt time.Time       // When a member last used the plan
+}
+
+// Clone implements framework.StateData
+func (p *placementPlan) Clone() framework.StateData {
+	return p
+}
+
+// empty reports whether the plan steers nowhere, as for pods outside a gang
+func (p *placementPlan) empty() bool {
+	return p.Domain == "" && p.Nodes == nil
+}
+
+// contains reports whether the node is in the planned domain
+func (p *placementPlan) contains(node *v1.Node) bool {
+	if p.Nodes != nil {
+		return p.Nodes[node.Name]
+	}
+	return node.Labels[p.Label] == p.Domain
+}
+
+// holds reports whether every placed member's node is in the planned domain
+func (p *placementPlan) holds(placedNodes []*v1.Node) bool {
+	for _, node := range placedNodes {
+		if !p.contains(node) {
+			return false
+		}
+	}
+	return true
+}
+
+// PreScore plans the placement of the pod's whole gang over the topology tree.
+// Pods outside a gang get an empty plan: returning Skip would also skip Score and
+// drop their fabric tier score.
+func (nf *NetworkFabricScore) PreScore(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodes []framework.NodeInfo) *framework.Status {
+	podGroup := pod.Annotations[utils.AnnotationPodGroup]
+	if podGroup == "" {
+		state.Write(placementPlanStateKey, &placementPlan{})
+		return nil
+	}
+
+	gangPods := nf.getGangMemberPods(state, pod.Namespace, podGroup)
+	needed := nf.gangSize(state, pod, podGroup) - len(gangPods)
+	if needed < 1 {
+		needed = 1
+	}
+	placedNodes := nf.placedMemberNodes(gangPods)
+	capacities := memberSlots(pod, nodes)
+	key := pod.Namespace + "/" + podGroup
+
+	nf.mu.Lock()
+	defer nf.mu.Unlock()
+	nf.prunePlans()
+
+	plan := nf.plans[key]
+	if plan != nil && !planStillFits(plan, needed, placedNodes, capacities) {
+		klog.V(4).InfoS("NetworkFabricScore: replanning gang, planned domain no longer fits",
+			"pod", klog.KObj(pod), "podGroup", podGroup, "level", plan.Level, "domain", plan.Domain, "needed", needed)
+		plan = nil
+	}
+	if plan == nil {
+		plan = planGangPlacement(needed, placedNodes, capacities, nf.topology.current())
+		if plan == nil {
+			klog.V(4).InfoS("NetworkFabricScore: no topology domain fits the gang, scoring greedily",
+				"pod", klog.KObj(pod), "podGroup", podGroup, "needed", needed)
+			delete(nf.plans, key)
+			return framework.NewStatus(framework.Success)
+		}
+		nf.plans[key] = plan
+		klog.V(3).InfoS("NetworkFabricScore: planned gang placement",
+			"pod", klog.KObj(pod), "podGroup", podGroup, "level", plan.Level, "domain", plan.Domain,
+			"needed", plan.Needed, "capacity", plan.Capacity)
+	}
+	plan.UpdatedAt = time.Now()
+
+	state.Write(placementPlanStateKey, plan)
+	return framework.NewStatus(framework.Success)
+}
+
+// getPlacementPlan returns the gang plan PreScore wrote for this cycle, if any
+func getPlacementPlan(state framework.CycleState) *placementPlan {
+	if state == nil {
+		return nil
+	}
+	data, err := state.Read(placementPlanStateKey)
+	if err != nil {
+		return nil
+	}
+	plan, _ := data.(*placementPlan)
+	if plan == nil || plan.empty() {
+		return nil
+	}
+	return plan
+}
+
+// planScore returns the plan bonus or penalty for a node
+func planScore(plan *placementPlan, node *v1.Node) int {
+	if plan == nil {
+		return 0
+	}
+	if plan.contains(node) {
+		return BonusPlannedDomain
+	}
+	return -PenaltyOutsidePlan
+}
+
+// planGangPlacement picks, at the tightest level that has one, the domain with room for
+// the needed members and holding every placed member. NVLink cliques come first, then
+// the NetworkTopology tree when there is one, then the fabric-id, rack and AZ labels.
+func planGangPlacement(needed int, placedNodes []*v1.Node, capacities map[*v1.Node]int, topology *topologyModel) *placementPlan {
+	if plan := planLabelLevel(topologyLevels[0], needed, placedNodes, capacities); plan != nil {
+		return plan
+	}
+	if topology != nil {
+		if plan := topology.plan(needed, placedNodes, capacities); plan != nil {
+			return plan
+		}
+	}
+	for _, level := range topologyLevels[1:] {
+		if plan := planLabelLevel(level, needed, placedNodes, capacities); plan != nil {
+			return plan
+		}
+	}
+	return nil
+}
+
+// planLabelLevel picks the level's domain with room for the needed members and holding
+// every placed member. Among those it takes the one with the fewest free slots, so
+// larger domains stay whole for larger gangs.
+func planLabelLevel(level topologyLevel, needed int, placedNodes []*v1.Node, capacities map[*v1.Node]int) *placementPlan {
+	domains := make(map[string]int)
+	for node, slots := range capacities {
+		if domain := node.Labels[level.label]; domain != "" {
+			domains[domain] += slots
+		}
+	}
+
+	var best *placementPlan
+	for _, domain := range sortedKeys(domains) {
+		capacity := domains[domain]
+		if capacity < needed || (best != nil && capacity >= best.Capacity) {
+			continue
+		}
+		candidate := &placementPlan{
+			Level:     level.name,
+			Label:     level.label,
+			Domain:    domain,
+			Needed:    needed,
+			Capacity:  capacity,
+			UpdatedAt: time.Now(),
+		}
+		if candidate.holds(placedNodes) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// planStillFits reports whether a stored plan still holds the placed members and has
+// room for the members left to place
+func planStillFits(plan *placementPlan, needed int, placedNodes []*v1.Node, capacities map[*v1.Node]int) bool {
+	if !plan.holds(placedNodes) {
+		return false
+	}
+	capacity := 0
+	for node, slots := range capacities {
+		if plan.contains(node) {
+			capacity += slots
+		}
+	}
+	return capacity >= needed
+}
+
+// memberSlots returns how many more pods shaped like this one fit on each node
+func memberSlots(pod *v1.Pod, nodes []framework.NodeInfo) map[*v1.Node]int {
+	requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	capacities := make(map[*v1.Node]int, len(nodes))
+	for _, nodeInfo := range nodes {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		allocatable := nodeInfo.GetAllocatable()
+		requested := nodeInfo.GetRequested()
+
+		slots := allocatable.GetAllowedPodNumber() - len(nodeInfo.GetPods())
+		fit := func(free, request int64) {
+			if request <= 0 {
+				return
+			}
+			if n := int(free / request); n < slots {
+				slots = n
+			}
+		}
+		fit(allocatable.GetMilliCPU()-requested.GetMilliCPU(), requests.Cpu().MilliValue())
+		fit(allocatable.GetMemory()-requested.GetMemory(), requests.Memory().Value())
+		for name, quantity := range requests {
+			if name == v1.ResourceCPU || name == v1.ResourceMemory {
+				continue
+			}
+			fit(allocatable.GetScalarResources()[name]-requested.GetScalarResources()[name], quantity.Value())
+		}
+		if slots > 0 {
+			capacities[node] = slots
+		}
+	}
+	return capacities
+}
+
+// gangSize returns the number of members the gang needs: min-available when the pod
+// group sets it, otherwise the gang's live pods: scheduled ones from the cycle's
+// snapshot index and the rest from the lister, skipping Succeeded and Failed pods
+func (nf *NetworkFabricScore) gangSize(state framework.CycleState, pod *v1.Pod, podGroup string) int {
+	if _, minAvailable, err := utils.GetPodGroupLabels(pod); err == nil && minAvailable > 0 {
+		return minAvailable
+	}
+	scheduled := nf.getGangMemberPods(state, pod.Namespace, podGroup)
+	counted := make(map[string]bool, len(scheduled))
+	for _, member := range scheduled {
+		counted[member.Name] = true
+	}
+	size := len(scheduled)
+
+	// Pending members are not in the snapshot
+	allPods, err := nf.podLister.Pods(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "NetworkFabricScore: failed to list pods", "namespace", pod.Namespace)
+		return max(size, 1)
+	}
+	for _, p := range allPods {
+		if p.Annotations[utils.AnnotationPodGroup] != podGroup || counted[p.Name] {
+			continue
+		}
+		if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		size++
+	}
+	return size
+}
+
+// placedMemberNodes returns the nodes of already scheduled gang members
+func (nf *NetworkFabricScore) placedMemberNodes(gangPods []*v1.Pod) []*v1.Node {
+	var nodes []*v1.Node
+	for _, pod := range gangPods {
+		node, err := nf.nodeLister.Get(pod.Spec.NodeName)
+		if err != nil {
+			klog.V(5).InfoS("NetworkFabricScore: failed to get node", "node", pod.Spec.NodeName, "err", err)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// prunePlans drops plans unused for placementPlanTTL. Caller must hold nf.mu.
+func (nf *NetworkFabricScore) prunePlans() {
+	for key, plan := range nf.plans {
+		if time.Since(plan.UpdatedAt) > placementPlanTTL {
+			delete(nf.plans, key)
+		}
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
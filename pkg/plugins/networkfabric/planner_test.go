@@ -0,0 +1,198 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+// gpuNode builds an 8-GPU InfiniBand node in the given rack and AZ
+func gpuNode(name, rack, az string) *v1.Node {
+	return testutil.MakeNode(name, map[string]string{
+		LabelFabricType: "infiniband",
+		LabelRackID:     rack,
+		LabelAZ:         az,
+	}, v1.ResourceList{
+		v1.ResourceCPU:                    resource.MustParse("64"),
+		v1.ResourceMemory:                 resource.MustParse("512Gi"),
+		v1.ResourcePods:                   resource.MustParse("110"),
+		v1.ResourceName("nvidia.com/gpu"): resource.MustParse("8"),
+	})
+}
+
+// gangMember builds an 8-GPU member of a gang with the given min-available
+func gangMember(name, nodeName, gang, minAvailable string) *v1.Pod {
+	return testutil.MakePod(name, "default", nodeName, v1.ResourceList{
+		v1.ResourceCPU:                    resource.MustParse("8"),
+		v1.ResourceName("nvidia.com/gpu"): resource.MustParse("8"),
+	}, map[string]string{
+		utils.PodGroupNameLabel:         gang,
+		utils.PodGroupMinAvailableLabel: minAvailable,
+	}, map[string]string{utils.AnnotationPodGroup: gang})
+}
+
+func newPlannerPlugin(pods []*v1.Pod, nodes []*v1.Node) (*NetworkFabricScore, []fwk.NodeInfo) {
+	plugin := &NetworkFabricScore{
+		podLister:  testutil.NewFakePodLister(pods),
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+		plans:      make(map[string]*placementPlan),
+	}
+	var nodeInfos []fwk.NodeInfo
+	for _, node := range nodes {
+		var nodePods []*v1.Pod
+		for _, pod := range pods {
+			if pod.Spec.NodeName == node.Name {
+				nodePods = append(nodePods, pod)
+			}
+		}
+		nodeInfo := framework.NewNodeInfo(nodePods...)
+		nodeInfo.SetNode(node)
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+	return plugin, nodeInfos
+}
+
+func TestPreScorePlansWholeGang(t *testing.T) {
+	nodes := []*v1.Node{
+		gpuNode("a-1", "rack-a", "az-1"),
+		gpuNode("a-2", "rack-a", "az-1"),
+		gpuNode("b-1", "rack-b", "az-1"),
+		gpuNode("b-2", "rack-b", "az-1"),
+		gpuNode("b-3", "rack-b", "az-1"),
+	}
+
+	tests := []struct {
+		name       string
+		pods       []*v1.Pod
+		wantLevel  string
+		wantDomain string
+	}{
+		{
+			name:       "smallest rack with room for the whole gang",
+			pods:       []*v1.Pod{gangMember("worker-0", "", "job", "3")},
+			wantLevel:  "rack",
+			wantDomain: "rack-b",
+		},
+		{
+			name:       "tighter rack when the gang is smaller",
+			pods:       []*v1.Pod{gangMember("worker-0", "", "job", "2")},
+			wantLevel:  "rack",
+			wantDomain: "rack-a",
+		},
+		{
+			name: "widens to the AZ holding a placed member",
+			pods: []*v1.Pod{
+				gangMember("worker-0", "a-1", "job", "4"),
+				gangMember("worker-1", "", "job", "4"),
+			},
+			wantLevel:  "az",
+			wantDomain: "az-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, nodeInfos := newPlannerPlugin(tt.pods, nodes)
+			pod := tt.pods[len(tt.pods)-1]
+			state := framework.NewCycleState()
+			if status := plugin.PreScore(context.Background(), state, pod, nodeInfos); !status.IsSuccess() {
+				t.Fatalf("PreScore failed: %v", status.Message())
+			}
+			plan := getPlacementPlan(state)
+			if plan == nil {
+				t.Fatal("PreScore wrote no plan")
+			}
+			if plan.Level != tt.wantLevel || plan.Domain != tt.wantDomain {
+				t.Errorf("plan = %s/%s, want %s/%s", plan.Level, plan.Domain, tt.wantLevel, tt.wantDomain)
+			}
+		})
+	}
+}
+
+func TestPreScoreKeepsFabricScoreForNonGangPods(t *testing.T) {
+	node := gpuNode("a-1", "rack-a", "az-1")
+	pod := testutil.MakePod("web", "default", "", nil, nil, nil)
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{pod}, []*v1.Node{node})
+	state := framework.NewCycleState()
+
+	// Skip would make the framework skip Score too
+	if status := plugin.PreScore(context.Background(), state, pod, nodeInfos); status.Code() != fwk.Success {
+		t.Fatalf("PreScore = %v, want Success", status.Code())
+	}
+	if plan := getPlacementPlan(state); plan != nil {
+		t.Errorf("PreScore planned %s/%s for a pod outside a gang", plan.Level, plan.Domain)
+	}
+	score, status := plugin.Score(context.Background(), state, pod, nodeInfos[0])
+	if !status.IsSuccess() {
+		t.Fatalf("Score failed: %v", status.Message())
+	}
+	if want := int64(getFabricTierScore(FabricInfiniBand)); score != want {
+		t.Errorf("Score = %d, want the InfiniBand tier score %d", score, want)
+	}
+}
+
+func TestScoreSteersFirstMemberIntoPlan(t *testing.T) {
+	nodes := []*v1.Node{
+		gpuNode("a-1", "rack-a", "az-1"),
+		gpuNode("b-1", "rack-b", "az-1"),
+		gpuNode("b-2", "rack-b", "az-1"),
+	}
+	pod := gangMember("worker-0", "", "job", "2")
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{pod}, nodes)
+
+	state := framework.NewCycleState()
+	if status := plugin.PreScore(context.Background(), state, pod, nodeInfos); !status.IsSuccess() {
+		t.Fatalf("PreScore failed: %v", status.Message())
+	}
+	outside, _ := plugin.Score(context.Background(), state, pod, nodeInfos[0])
+	inside, _ := plugin.Score(context.Background(), state, pod, nodeInfos[1])
+	if inside <= outside {
+		t.Errorf("planned rack scored %d, want more than %d outside it", inside, outside)
+	}
+
+	// The next cycle reuses the stored plan
+	if status := plugin.PreScore(context.Background(), framework.NewCycleState(), pod, nodeInfos); !status.IsSuccess() {
+		t.Fatalf("PreScore failed: %v", status.Message())
+	}
+	if plan := plugin.plans["default/job"]; plan == nil || plan.Domain != "rack-b" {
+		t.Errorf("stored plan = %+v, want rack-b", plan)
+	}
+}
+
+func TestGangSizeSkipsTerminatedPods(t *testing.T) {
+	nodes := []*v1.Node{gpuNode("node-a", "rack-a", "az-1")}
+	running := gangMember("llm-0", "node-a", "llm", "")
+	done := gangMember("llm-1", "node-a", "llm", "")
+	done.Status.Phase = v1.PodSucceeded
+	failed := gangMember("llm-2", "", "llm", "")
+	failed.Status.Phase = v1.PodFailed
+	pending := gangMember("llm-3", "", "llm", "")
+	plugin, _ := newPlannerPlugin([]*v1.Pod{running, done, failed, pending}, nodes)
+
+	if got := plugin.gangSize(nil, pending, "llm"); got != 2 {
+		t.Errorf("gangSize() = %d, want 2 live members", got)
+	}
+}
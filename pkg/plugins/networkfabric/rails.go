@@ -0,0 +1,314 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+
+	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+// Rail-optimized placement:
+//   - On rail-optimized fabrics GPU i talks to NIC i on rail i, and every rail has its
+//     own leaf switch; traffic between different rails crosses the spine
+//   - Tensor- and pipeline-parallel ranks exchange data with the same rank on other
+//     nodes, so members are steered to nodes whose rail i reaches the same rail switch
+//     as rail i on the nodes already holding the gang
+//   - VRAMScheduler hands GPUs out in rail order to these pods, keeping rank i on rail i,
+//     and records per node the rails it would hand out; only those rails are compared
+
+const (
+	// DRA device attributes describing the GPU's rail
+	AttributeRail       = "rail"        // Rail of the NIC paired with the GPU
+	AttributeRailSwitch = "rail-switch" // Leaf switch serving the rail
+
+	// LabelRailSwitchFormat is the node label fallback naming the switch of rail i
+	LabelRailSwitchFormat = "network.kubenexus.io/rail-%d-switch"
+
+	// Parallelism of gang members spanning nodes; rail alignment is scored when either exceeds 1
+	AnnotationTensorParallel   = "scheduling.kubenexus.io/tensor-parallel"
+	AnnotationPipelineParallel = "scheduling.kubenexus.io/pipeline-parallel"
+
+	// BonusRailAligned is added when every rail matches the placed members' rail switches
+	BonusRailAligned = 20
+	// PenaltyRailMisaligned is subtracted when no rail matches
+	PenaltyRailMisaligned = 20
+
+	railLabelPrefix = "network.kubenexus.io/rail-"
+	railLabelSuffix = "-switch"
+
+	railSelectionStateKey = Name + "/rail-selection"
+)
+
+var _ framework.ReservePlugin = &NetworkFabricScore{}
+
+// railSelection holds, per node, the rails of the GPUs VRAMScheduler would assign the
+// pod being scheduled. Filter runs on nodes in parallel, so it is locked.
+type railSelection struct {
+	lock   sync.Mutex
+	byNode map[string][]int
+}
+
+// Clone implements framework.StateData
+func (s *railSelection) Clone() framework.StateData {
+	return s
+}
+
+// railSelectionLock serializes creating the cycle's railSelection
+var railSelectionLock sync.Mutex
+
+// RecordSelectedRails records the rails of the GPUs a rail-aligned pod would get on
+// the node, so its rail alignment there is judged on those rails alone
+func RecordSelectedRails(state framework.CycleState, nodeName string, rails []int) {
+	if state == nil {
+		return
+	}
+	railSelectionLock.Lock()
+	selection := readRailSelection(state)
+	if selection == nil {
+		selection = &railSelection{byNode: make(map[string][]int)}
+		state.Write(railSelectionStateKey, selection)
+	}
+	railSelectionLock.Unlock()
+
+	selection.lock.Lock()
+	selection.byNode[nodeName] = rails
+	selection.lock.Unlock()
+}
+
+// selectedRails returns the rails recorded for the node by RecordSelectedRails
+func selectedRails(state framework.CycleState, nodeName string) ([]int, bool) {
+	selection := readRailSelection(state)
+	if selection == nil {
+		return nil, false
+	}
+	selection.lock.Lock()
+	defer selection.lock.Unlock()
+	rails, ok := selection.byNode[nodeName]
+	return rails, ok
+}
+
+func readRailSelection(state framework.CycleState) *railSelection {
+	if state == nil {
+		return nil
+	}
+	data, err := state.Read(railSelectionStateKey)
+	if err != nil {
+		return nil
+	}
+	selection, _ := data.(*railSelection)
+	return selection
+}
+
+// Reserve reports the rail alignment of the chosen node for tensor- and
+// pipeline-parallel gang members
+func (nf *NetworkFabricScore) Reserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if !IsRailAligned(pod) || nf.nodeLister == nil {
+		return nil
+	}
+	parallelism := podParallelism(pod)
+	podGroup := pod.Annotations[utils.AnnotationPodGroup]
+	node, err := nf.nodeLister.Get(nodeName)
+	if err != nil {
+		return nil
+	}
+	alignment, ok := nf.railAlignment(state, node, nf.getGangMemberPods(state, pod.Namespace, podGroup))
+	if !ok {
+		return nil
+	}
+	schedulermetrics.RailAlignmentScore.WithLabelValues(parallelism).Observe(alignment * 100)
+	klog.V(4).InfoS("NetworkFabricScore: rail alignment at reserve", "pod", klog.KObj(pod), "podGroup", podGroup, "node", nodeName, "alignment", alignment)
+	return nil
+}
+
+// Unreserve has nothing to release
+func (nf *NetworkFabricScore) Unreserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+}
+
+// IsRailAligned reports whether the pod is a gang member that declares tensor or
+// pipeline parallelism above 1, whose ranks talk to the same ranks on other nodes, so
+// it takes GPUs in rail order
+func IsRailAligned(pod *v1.Pod) bool {
+	return pod.Annotations[utils.AnnotationPodGroup] != "" && podParallelism(pod) != ""
+}
+
+// podParallelism returns "tensor", "pipeline" or "tensor+pipeline" when the pod
+// declares a degree above 1, or "" otherwise
+func podParallelism(pod *v1.Pod) string {
+	var kinds []string
+	if parallelDegree(pod, AnnotationTensorParallel) > 1 {
+		kinds = append(kinds, "tensor")
+	}
+	if parallelDegree(pod, AnnotationPipelineParallel) > 1 {
+		kinds = append(kinds, "pipeline")
+	}
+	return strings.Join(kinds, "+")
+}
+
+func parallelDegree(pod *v1.Pod, annotation string) int {
+	n, err := strconv.Atoi(pod.Annotations[annotation])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// railScore returns the rail alignment bonus (positive) or penalty (negative) of the
+// node for a tensor- or pipeline-parallel gang member, 0 when rails are unknown
+func (nf *NetworkFabricScore) railScore(state framework.CycleState, pod *v1.Pod, node *v1.Node, gangPods []*v1.Pod) int {
+	if podParallelism(pod) == "" {
+		return 0
+	}
+	alignment, ok := nf.railAlignment(state, node, gangPods)
+	if !ok {
+		return 0
+	}
+	return int(math.Round(alignment*float64(BonusRailAligned+PenaltyRailMisaligned))) - PenaltyRailMisaligned
+}
+
+// railAlignment returns the share of rails on the node that reach the same rail
+// switch as on the placed gang members' nodes, averaged over those nodes. Only the
+// rails of the GPUs VRAMScheduler would assign on the node count when it recorded
+// them; otherwise every rail does. It returns false when the node or every member
+// node has no rail information.
+func (nf *NetworkFabricScore) railAlignment(state framework.CycleState, node *v1.Node, gangPods []*v1.Pod) (float64, bool) {
+	candidate := nf.getNodeRails(state, node)
+	if rails, ok := selectedRails(state, node.Name); ok {
+		assigned := make(map[int]string, len(rails))
+		for _, rail := range rails {
+			if sw, ok := candidate[rail]; ok {
+				assigned[rail] = sw
+			}
+		}
+		candidate = assigned
+	}
+	if len(candidate) == 0 || nf.nodeLister == nil {
+		return 0, false
+	}
+
+	seen := make(map[string]bool)
+	var total float64
+	compared := 0
+	for _, pod := range gangPods {
+		if pod.Spec.NodeName == "" || seen[pod.Spec.NodeName] {
+			continue
+		}
+		seen[pod.Spec.NodeName] = true
+		if pod.Spec.NodeName == node.Name {
+			// Members on the same node share every rail switch
+			total++
+			compared++
+			continue
+		}
+		memberNode, err := nf.nodeLister.Get(pod.Spec.NodeName)
+		if err != nil {
+			continue
+		}
+		if alignment, ok := compareRails(candidate, nf.getNodeRails(state, memberNode)); ok {
+			total += alignment
+			compared++
+		}
+	}
+	if compared == 0 {
+		return 0, false
+	}
+	return total / float64(compared), true
+}
+
+// compareRails returns the share of rails known on both nodes that reach the same switch
+func compareRails(a, b map[int]string) (float64, bool) {
+	common, matched := 0, 0
+	for rail, sw := range a {
+		other, ok := b[rail]
+		if !ok {
+			continue
+		}
+		common++
+		if other == sw {
+			matched++
+		}
+	}
+	if common == 0 {
+		return 0, false
+	}
+	return float64(matched) / float64(common), true
+}
+
+// getNodeRails returns the rail switch of each rail on the node:
+//  1. DRA ResourceSlice rail and rail-switch attributes of the node's GPUs
+//  2. Node labels network.kubenexus.io/rail-<i>-switch
+func (nf *NetworkFabricScore) getNodeRails(state framework.CycleState, node *v1.Node) map[int]string {
+	if rails := nf.railsFromResourceSlices(state, node.Name); len(rails) > 0 {
+		return rails
+	}
+	return railsFromLabels(node)
+}
+
+func (nf *NetworkFabricScore) railsFromResourceSlices(state framework.CycleState, nodeName string) map[int]string {
+	rails := make(map[int]string)
+	for _, slice := range nf.nodeResourceSlices(state, nodeName) {
+		if !isGPUDriver(slice.Spec.Driver) {
+			continue
+		}
+		for _, device := range slice.Spec.Devices {
+			railAttr, hasRail := device.Attributes[AttributeRail]
+			switchAttr, hasSwitch := device.Attributes[AttributeRailSwitch]
+			if !hasRail || !hasSwitch || switchAttr.StringValue == nil || *switchAttr.StringValue == "" {
+				continue
+			}
+			rail := -1
+			if railAttr.IntValue != nil {
+				rail = int(*railAttr.IntValue)
+			} else if railAttr.StringValue != nil {
+				if n, err := strconv.Atoi(*railAttr.StringValue); err == nil {
+					rail = n
+				}
+			}
+			if rail >= 0 {
+				rails[rail] = *switchAttr.StringValue
+			}
+		}
+	}
+	return rails
+}
+
+func railsFromLabels(node *v1.Node) map[int]string {
+	rails := make(map[int]string)
+	for key, value := range node.Labels {
+		index, ok := strings.CutPrefix(key, railLabelPrefix)
+		if !ok || value == "" {
+			continue
+		}
+		index, ok = strings.CutSuffix(index, railLabelSuffix)
+		if !ok {
+			continue
+		}
+		if rail, err := strconv.Atoi(index); err == nil && rail >= 0 {
+			rails[rail] = value
+		}
+	}
+	return rails
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcev1listers "k8s.io/client-go/listers/resource/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+// railNode builds a gpuNode whose rail i reaches the switch named by switches[i]. The
+// fabric is RoCE so locality bonuses stay below the score cap.
+func railNode(name string, switches ...string) *v1.Node {
+	node := gpuNode(name, "rack-a", "az-1")
+	node.Labels[LabelFabricType] = string(FabricRoCE)
+	for i, sw := range switches {
+		node.Labels[fmt.Sprintf(LabelRailSwitchFormat, i)] = sw
+	}
+	return node
+}
+
+// parallelMember builds a gangMember declaring tensor and pipeline parallelism
+func parallelMember(name, nodeName string) *v1.Pod {
+	pod := gangMember(name, nodeName, "llm", "4")
+	pod.Annotations[AnnotationTensorParallel] = "8"
+	pod.Annotations[AnnotationPipelineParallel] = "4"
+	return pod
+}
+
+func TestPodParallelism(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{name: "none", want: ""},
+		{name: "tensor", annotations: map[string]string{AnnotationTensorParallel: "8"}, want: "tensor"},
+		{name: "pipeline", annotations: map[string]string{AnnotationPipelineParallel: "2"}, want: "pipeline"},
+		{name: "both", annotations: map[string]string{AnnotationTensorParallel: "8", AnnotationPipelineParallel: "4"}, want: "tensor+pipeline"},
+		{name: "degree 1 is not parallel", annotations: map[string]string{AnnotationTensorParallel: "1"}, want: ""},
+		{name: "invalid degree", annotations: map[string]string{AnnotationTensorParallel: "eight"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := podParallelism(pod); got != tt.want {
+				t.Errorf("podParallelism() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareRails(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   map[int]string
+		want   float64
+		wantOK bool
+	}{
+		{name: "all rails match", a: map[int]string{0: "r0", 1: "r1"}, b: map[int]string{0: "r0", 1: "r1"}, want: 1, wantOK: true},
+		{name: "half the rails match", a: map[int]string{0: "r0", 1: "r1"}, b: map[int]string{0: "r0", 1: "r1-su2"}, want: 0.5, wantOK: true},
+		{name: "no rail in common", a: map[int]string{0: "r0"}, b: map[int]string{1: "r1"}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := compareRails(tt.a, tt.b)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("compareRails() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetNodeRailsFromDRA(t *testing.T) {
+	nodeName := "dgx-1"
+	rail := func(i int64, sw string) resourcev1.Device {
+		return resourcev1.Device{
+			Name: fmt.Sprintf("gpu-%d", i),
+			Attributes: map[resourcev1.QualifiedName]resourcev1.DeviceAttribute{
+				AttributeRail:       {IntValue: &i},
+				AttributeRailSwitch: {StringValue: &sw},
+			},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&resourcev1.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dgx-1-gpus"},
+		Spec: resourcev1.ResourceSliceSpec{
+			Driver:   "gpu.nvidia.com",
+			NodeName: &nodeName,
+			Devices:  []resourcev1.Device{rail(0, "rail0-su1"), rail(1, "rail1-su1")},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	plugin := &NetworkFabricScore{resourceSliceLister: resourcev1listers.NewResourceSliceLister(indexer)}
+
+	// DRA attributes take precedence over the label fallback
+	rails := plugin.getNodeRails(nil, railNode(nodeName, "stale-0", "stale-1"))
+	if len(rails) != 2 || rails[0] != "rail0-su1" || rails[1] != "rail1-su1" {
+		t.Errorf("getNodeRails() = %v, want rails from DRA", rails)
+	}
+	if rails := plugin.getNodeRails(nil, railNode("dgx-2", "rail0-su2")); rails[0] != "rail0-su2" {
+		t.Errorf("getNodeRails() = %v, want rails from labels", rails)
+	}
+}
+
+func TestScorePrefersRailAlignedNodes(t *testing.T) {
+	nodes := []*v1.Node{
+		railNode("su1-a", "rail0-su1", "rail1-su1"),
+		railNode("su1-b", "rail0-su1", "rail1-su1"),
+		railNode("su2-a", "rail0-su2", "rail1-su2"),
+	}
+	placed := parallelMember("llm-0", "su1-a")
+	pending := parallelMember("llm-1", "")
+	plugin, nodeInfos := newPlannerPlugin([]*v1.Pod{placed, pending}, nodes)
+
+	state := framework.NewCycleState()
+	aligned, _ := plugin.Score(context.Background(), state, pending, nodeInfos[1])
+	misaligned, _ := plugin.Score(context.Background(), state, pending, nodeInfos[2])
+	if aligned <= misaligned {
+		t.Errorf("rail-aligned node scored %d, misaligned node %d; want aligned higher", aligned, misaligned)
+	}
+
+	// Without declared parallelism rails don't matter
+	plain := gangMember("plain-1", "", "llm", "4")
+	alignedPlain, _ := plugin.Score(context.Background(), state, plain, nodeInfos[1])
+	misalignedPlain, _ := plugin.Score(context.Background(), state, plain, nodeInfos[2])
+	if alignedPlain != misalignedPlain {
+		t.Errorf("plain gang member scored %d and %d, want equal", alignedPlain, misalignedPlain)
+	}
+}
+
+func TestRailScore(t *testing.T) {
+	nodes := []*v1.Node{
+		railNode("su1-a", "rail0-su1", "rail1-su1"),
+		railNode("half", "rail0-su1", "rail1-su2"),
+		railNode("su2-a", "rail0-su2", "rail1-su2"),
+		gpuNode("no-rails", "rack-a", "az-1"),
+	}
+	placed := parallelMember("llm-0", "su1-a")
+	plugin, _ := newPlannerPlugin([]*v1.Pod{placed}, nodes)
+	pod := parallelMember("llm-1", "")
+
+	tests := []struct {
+		node string
+		want int
+	}{
+		{node: "su1-a", want: BonusRailAligned},
+		{node: "half", want: 0},
+		{node: "su2-a", want: -PenaltyRailMisaligned},
+		{node: "no-rails", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			node, _ := plugin.nodeLister.Get(tt.node)
+			if got := plugin.railScore(nil, pod, node, []*v1.Pod{placed}); got != tt.want {
+				t.Errorf("railScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRailScoreUsesSelectedRails(t *testing.T) {
+	nodes := []*v1.Node{
+		railNode("su1-a", "rail0-su1", "rail1-su1"),
+		railNode("half", "rail0-su1", "rail1-su2"),
+	}
+	placed := parallelMember("llm-0", "su1-a")
+	plugin, _ := newPlannerPlugin([]*v1.Pod{placed}, nodes)
+	pod := parallelMember("llm-1", "")
+	node, _ := plugin.nodeLister.Get("half")
+
+	// The pod gets only the GPU on rail 0, which matches the placed member
+	state := framework.NewCycleState()
+	RecordSelectedRails(state, "half", []int{0})
+	if got := plugin.railScore(state, pod, node, []*v1.Pod{placed}); got != BonusRailAligned {
+		t.Errorf("railScore() on selected aligned rail = %d, want %d", got, BonusRailAligned)
+	}
+
+	state = framework.NewCycleState()
+	RecordSelectedRails(state, "half", []int{1})
+	if got := plugin.railScore(state, pod, node, []*v1.Pod{placed}); got != -PenaltyRailMisaligned {
+		t.Errorf("railScore() on selected misaligned rail = %d, want %d", got, -PenaltyRailMisaligned)
+	}
+}
+
+func TestIsRailAligned(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationPipelineParallel: "4"}}}
+	if IsRailAligned(pod) {
+		t.Error("pod outside a gang is rail aligned")
+	}
+	pod.Annotations[utils.AnnotationPodGroup] = "llm"
+	if !IsRailAligned(pod) {
+		t.Error("pipeline-parallel gang member is not rail aligned")
+	}
+	delete(pod.Annotations, AnnotationPipelineParallel)
+	if IsRailAligned(pod) {
+		t.Error("gang member without parallelism is rail aligned")
+	}
+}
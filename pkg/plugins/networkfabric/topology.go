@@ -0,0 +1,388 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+)
+
+// Hierarchical network topology:
+//   - NetworkTopology objects describe the network as a tree of switch domains of any
+//     depth, e.g. superpod -> scalable unit -> leaf switch -> node
+//   - Bandwidth between two nodes is the narrowest link on the path through their lowest
+//     common domain; leaving a domain divides its link bandwidth by its oversubscription
+//   - Nodes covered by the tree are scored and planned from it; other nodes keep using
+//     the flat fabric labels
+
+// MaxTopologyLocalityScore bounds the locality bonus or penalty from the topology tree,
+// matching the full range of the label-based bonuses
+const MaxTopologyLocalityScore = BonusSameClique + BonusSameFabricDomain + BonusSameRack + BonusSameAZ
+
+var networkTopologyGVR = v1alpha1.SchemeGroupVersion.WithResource("networktopologies")
+
+// topologyDomain is a switch domain of the topology tree
+type topologyDomain struct {
+	name             string
+	level            string
+	parent           *topologyDomain
+	depth            int     // 1 for a root
+	linkBandwidth    int64   // Gb/s of each link into the domain's switches, 0 if unknown
+	oversubscription float64 // Downlink to uplink ratio toward the parent, at least 1
+	nodes            []string
+}
+
+// topologyModel indexes the topology trees by node
+type topologyModel struct {
+	domains      []*topologyDomain
+	leaf         map[string]*topologyDomain // node -> domain it attaches to
+	maxBandwidth int64
+}
+
+// topologyCache keeps the topology model built from NetworkTopology objects and
+// rebuilds it when they change
+type topologyCache struct {
+	lister    cache.GenericLister
+	hasSynced func() bool
+
+	mu    sync.Mutex
+	stale bool
+	model *topologyModel
+}
+
+// newTopologyCache starts a NetworkTopology informer. It returns nil when the CRD is
+// not installed, so clusters without it keep using the fabric labels.
+func newTopologyCache(ctx context.Context, disc discovery.DiscoveryInterface, client dynamic.Interface) *topologyCache {
+	if disc == nil || client == nil {
+		return nil
+	}
+	groupVersion := v1alpha1.SchemeGroupVersion.String()
+	if _, err := disc.ServerResourcesForGroupVersion(groupVersion); err != nil {
+		klog.V(3).InfoS("NetworkFabricScore: NetworkTopology API not available, using fabric labels", "groupVersion", groupVersion, "error", err)
+		return nil
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	informer := factory.ForResource(networkTopologyGVR)
+	c := &topologyCache{
+		lister:    informer.Lister(),
+		hasSynced: informer.Informer().HasSynced,
+		stale:     true,
+	}
+	markStale := func(interface{}) { c.invalidate() }
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    markStale,
+		UpdateFunc: func(_, newObj interface{}) { markStale(newObj) },
+		DeleteFunc: markStale,
+	}); err != nil {
+		klog.ErrorS(err, "NetworkFabricScore: failed to watch NetworkTopology objects")
+		return nil
+	}
+	factory.Start(ctx.Done())
+	return c
+}
+
+func (c *topologyCache) invalidate() {
+	c.mu.Lock()
+	c.stale = true
+	c.mu.Unlock()
+}
+
+// current returns the topology model, or nil when no topology covers any node
+func (c *topologyCache) current() *topologyModel {
+	if c == nil || !c.hasSynced() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.stale {
+		return c.model
+	}
+
+	objs, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "NetworkFabricScore: failed to list NetworkTopology objects")
+		return c.model
+	}
+	var topologies []*v1alpha1.NetworkTopology
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		topology := &v1alpha1.NetworkTopology{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, topology); err != nil {
+			klog.V(3).InfoS("NetworkFabricScore: ignoring invalid NetworkTopology", "name", u.GetName(), "error", err)
+			continue
+		}
+		topologies = append(topologies, topology)
+	}
+	c.model = buildTopologyModel(topologies)
+	c.stale = false
+	return c.model
+}
+
+// buildTopologyModel links the domains of each topology into trees. Domain names are
+// scoped to their topology; a domain with an unknown parent, or in a parent cycle, is
+// treated as a root.
+func buildTopologyModel(topologies []*v1alpha1.NetworkTopology) *topologyModel {
+	model := &topologyModel{leaf: make(map[string]*topologyDomain)}
+	sort.Slice(topologies, func(i, j int) bool { return topologies[i].Name < topologies[j].Name })
+
+	for _, topology := range topologies {
+		byName := make(map[string]*topologyDomain, len(topology.Spec.Domains))
+		parents := make(map[string]string, len(topology.Spec.Domains))
+		for _, spec := range topology.Spec.Domains {
+			if spec.Name == "" || byName[spec.Name] != nil {
+				klog.V(3).InfoS("NetworkFabricScore: ignoring unnamed or duplicate topology domain", "topology", topology.Name, "domain", spec.Name)
+				continue
+			}
+			oversubscription, err := parseOversubscription(spec.Oversubscription)
+			if err != nil {
+				klog.V(3).InfoS("NetworkFabricScore: ignoring invalid oversubscription", "topology", topology.Name, "domain", spec.Name, "error", err)
+				oversubscription = 1
+			}
+			domain := &topologyDomain{
+				name:             topology.Name + "/" + spec.Name,
+				level:            spec.Level,
+				linkBandwidth:    spec.LinkBandwidthGbps,
+				oversubscription: oversubscription,
+			}
+			byName[spec.Name] = domain
+			parents[spec.Name] = spec.Parent
+			model.domains = append(model.domains, domain)
+			if domain.linkBandwidth > model.maxBandwidth {
+				model.maxBandwidth = domain.linkBandwidth
+			}
+		}
+
+		for _, spec := range topology.Spec.Domains {
+			name, domain := spec.Name, byName[spec.Name]
+			if domain == nil || domain.parent != nil {
+				continue
+			}
+			parent := byName[parents[name]]
+			if parent == nil {
+				if parents[name] != "" {
+					klog.V(3).InfoS("NetworkFabricScore: topology domain parent not found", "topology", topology.Name, "domain", name, "parent", parents[name])
+				}
+				continue
+			}
+			domain.parent = parent
+			if domain.inCycle() {
+				klog.V(3).InfoS("NetworkFabricScore: topology domain parents form a cycle", "topology", topology.Name, "domain", name)
+				domain.parent = nil
+			}
+		}
+
+		for _, spec := range topology.Spec.Domains {
+			domain := byName[spec.Name]
+			if domain == nil {
+				continue
+			}
+			for _, node := range spec.Nodes {
+				if existing := model.leaf[node]; existing != nil {
+					klog.V(3).InfoS("NetworkFabricScore: node attached to several topology domains", "node", node, "domain", existing.name, "ignored", domain.name)
+					continue
+				}
+				model.leaf[node] = domain
+			}
+		}
+	}
+
+	for _, domain := range model.domains {
+		for d := domain; d != nil; d = d.parent {
+			domain.depth++
+		}
+	}
+	for node, leaf := range model.leaf {
+		for d := leaf; d != nil; d = d.parent {
+			d.nodes = append(d.nodes, node)
+		}
+	}
+	return model
+}
+
+// inCycle reports whether following parents from the domain returns to it
+func (d *topologyDomain) inCycle() bool {
+	seen := map[*topologyDomain]bool{d: true}
+	for p := d.parent; p != nil; p = p.parent {
+		if seen[p] {
+			return true
+		}
+		seen[p] = true
+	}
+	return false
+}
+
+// parseOversubscription parses "N:M" or "N" into a ratio of at least 1
+func parseOversubscription(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 1, nil
+	}
+	down, up, hasUp := strings.Cut(value, ":")
+	ratio, err := strconv.ParseFloat(down, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid oversubscription %q", value)
+	}
+	if hasUp {
+		divisor, err := strconv.ParseFloat(up, 64)
+		if err != nil || divisor <= 0 {
+			return 0, fmt.Errorf("invalid oversubscription %q", value)
+		}
+		ratio /= divisor
+	}
+	if ratio < 1 {
+		return 0, fmt.Errorf("oversubscription %q is below 1:1", value)
+	}
+	return ratio, nil
+}
+
+// lowestCommonDomain returns the deepest domain holding both nodes, or nil
+func (m *topologyModel) lowestCommonDomain(a, b string) *topologyDomain {
+	ancestors := make(map[*topologyDomain]bool)
+	for d := m.leaf[a]; d != nil; d = d.parent {
+		ancestors[d] = true
+	}
+	for d := m.leaf[b]; d != nil; d = d.parent {
+		if ancestors[d] {
+			return d
+		}
+	}
+	return nil
+}
+
+// pairBandwidth returns the bandwidth in Gb/s between two nodes: the narrowest link on
+// the path through their lowest common domain. Links of unknown bandwidth are skipped;
+// 0 means no common domain or no known bandwidth on the path.
+func (m *topologyModel) pairBandwidth(a, b string) int64 {
+	common := m.lowestCommonDomain(a, b)
+	if common == nil {
+		return 0
+	}
+	bandwidth := math.Inf(1)
+	narrow := func(gbps float64) {
+		if gbps > 0 && gbps < bandwidth {
+			bandwidth = gbps
+		}
+	}
+	for _, node := range []string{a, b} {
+		for d := m.leaf[node]; d != common; d = d.parent {
+			narrow(float64(d.linkBandwidth) / d.oversubscription)
+		}
+	}
+	narrow(float64(common.linkBandwidth))
+	if math.IsInf(bandwidth, 1) {
+		return 0
+	}
+	return int64(bandwidth)
+}
+
+// localityScore scores placing a gang member on the candidate node next to the members
+// already placed, in [-MaxTopologyLocalityScore, MaxTopologyLocalityScore]. Bandwidth to
+// each member counts for three quarters and the depth of the common domain for the rest,
+// so a tighter domain wins when bandwidth ties. It returns false when the tree does not
+// cover the candidate or any of the members.
+func (m *topologyModel) localityScore(candidate string, members []string) (int, bool) {
+	leaf := m.leaf[candidate]
+	if leaf == nil {
+		return 0, false
+	}
+
+	total, counted := 0.0, 0
+	for _, member := range members {
+		if m.leaf[member] == nil {
+			continue
+		}
+		counted++
+		if member == candidate {
+			total++
+			continue
+		}
+		bandwidthRatio := 0.0
+		if m.maxBandwidth > 0 {
+			bandwidthRatio = float64(m.pairBandwidth(candidate, member)) / float64(m.maxBandwidth)
+		}
+		depthRatio := 0.0
+		if common := m.lowestCommonDomain(candidate, member); common != nil {
+			depthRatio = float64(common.depth) / float64(leaf.depth)
+		}
+		total += 0.75*bandwidthRatio + 0.25*depthRatio
+	}
+	if counted == 0 {
+		return 0, false
+	}
+	ratio := total / float64(counted)
+	return int(math.Round((2*ratio - 1) * MaxTopologyLocalityScore)), true
+}
+
+// plan picks the smallest topology subtree with room for the needed members and holding
+// every placed member, preferring the deeper domain when free slots tie
+func (m *topologyModel) plan(needed int, placedNodes []*v1.Node, capacities map[*v1.Node]int) *placementPlan {
+	slots := make(map[string]int, len(capacities))
+	for node, n := range capacities {
+		slots[node.Name] = n
+	}
+
+	var best *placementPlan
+	var bestDepth int
+	for _, domain := range m.domains {
+		capacity := 0
+		members := make(map[string]bool, len(domain.nodes))
+		for _, node := range domain.nodes {
+			members[node] = true
+			capacity += slots[node]
+		}
+		if capacity < needed {
+			continue
+		}
+		candidate := &placementPlan{
+			Level:     domain.level,
+			Domain:    domain.name,
+			Nodes:     members,
+			Needed:    needed,
+			Capacity:  capacity,
+			UpdatedAt: time.Now(),
+		}
+		if !candidate.holds(placedNodes) {
+			continue
+		}
+		if best == nil || capacity < best.Capacity || (capacity == best.Capacity && domain.depth > bestDepth) {
+			best, bestDepth = candidate, domain.depth
+		}
+	}
+	return best
+}
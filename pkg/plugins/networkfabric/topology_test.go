@@ -0,0 +1,186 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkfabric
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+)
+
+// fatTree is a 3-level InfiniBand fat-tree: one superpod, two scalable units with 2:1
+// oversubscribed spine uplinks, and two leaf switches of two nodes per unit
+func fatTree() *v1alpha1.NetworkTopology {
+	return &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "ib"},
+		Spec: v1alpha1.NetworkTopologySpec{Domains: []v1alpha1.TopologyDomain{
+			{Name: "superpod", Level: "superpod", LinkBandwidthGbps: 400},
+			{Name: "su-1", Level: "scalable-unit", Parent: "superpod", LinkBandwidthGbps: 400, Oversubscription: "2:1"},
+			{Name: "su-2", Level: "scalable-unit", Parent: "superpod", LinkBandwidthGbps: 400, Oversubscription: "2:1"},
+			{Name: "leaf-1", Level: "leaf", Parent: "su-1", LinkBandwidthGbps: 400, Nodes: []string{"n1", "n2"}},
+			{Name: "leaf-2", Level: "leaf", Parent: "su-1", LinkBandwidthGbps: 400, Nodes: []string{"n3", "n4"}},
+			{Name: "leaf-3", Level: "leaf", Parent: "su-2", LinkBandwidthGbps: 400, Nodes: []string{"n5", "n6"}},
+			{Name: "leaf-4", Level: "leaf", Parent: "su-2", LinkBandwidthGbps: 400, Nodes: []string{"n7", "n8"}},
+		}},
+	}
+}
+
+func TestParseOversubscription(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"2:1", 2, false},
+		{"3", 3, false},
+		{"5:2", 2.5, false},
+		{"1:2", 0, true},
+		{"fast", 0, true},
+		{"2:0", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseOversubscription(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseOversubscription(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTopologyPairBandwidth(t *testing.T) {
+	model := buildTopologyModel([]*v1alpha1.NetworkTopology{fatTree()})
+
+	tests := []struct {
+		a, b string
+		want int64
+	}{
+		{"n1", "n2", 400}, // same leaf
+		{"n1", "n3", 400}, // same scalable unit, non-blocking leaves
+		{"n1", "n5", 200}, // across the oversubscribed spine
+		{"n1", "other", 0},
+	}
+	for _, tt := range tests {
+		if got := model.pairBandwidth(tt.a, tt.b); got != tt.want {
+			t.Errorf("pairBandwidth(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTopologyLocalityScore(t *testing.T) {
+	model := buildTopologyModel([]*v1alpha1.NetworkTopology{fatTree()})
+
+	sameLeaf, _ := model.localityScore("n2", []string{"n1"})
+	sameUnit, _ := model.localityScore("n3", []string{"n1"})
+	crossSpine, _ := model.localityScore("n5", []string{"n1"})
+	if !(sameLeaf > sameUnit && sameUnit > crossSpine) {
+		t.Errorf("scores same leaf %d, same unit %d, cross spine %d; want strictly decreasing", sameLeaf, sameUnit, crossSpine)
+	}
+	if sameLeaf > MaxTopologyLocalityScore || crossSpine < -MaxTopologyLocalityScore {
+		t.Errorf("scores out of range: %d, %d", sameLeaf, crossSpine)
+	}
+	if _, ok := model.localityScore("other", []string{"n1"}); ok {
+		t.Error("node outside the tree should fall back to labels")
+	}
+}
+
+func TestTopologyScoreKeepsCliqueBonus(t *testing.T) {
+	member := gpuNode("n1", "rack-a", "az-1")
+	member.Labels[LabelGPUClique] = "clique-1"
+	sameClique := gpuNode("n2", "rack-a", "az-1")
+	sameClique.Labels[LabelGPUClique] = "clique-1"
+	otherClique := gpuNode("n2", "rack-a", "az-1")
+	otherClique.Labels[LabelGPUClique] = "clique-2"
+	// Ethernet keeps the scores clear of the cap
+	for _, node := range []*v1.Node{member, sameClique, otherClique} {
+		node.Labels[LabelFabricType] = "ethernet"
+	}
+
+	pods := []*v1.Pod{gangMember("worker-0", "n1", "job", "2"), gangMember("worker-1", "", "job", "2")}
+	plugin, _ := newPlannerPlugin(pods, []*v1.Node{member, sameClique})
+	plugin.topology = &topologyCache{
+		hasSynced: func() bool { return true },
+		model:     buildTopologyModel([]*v1alpha1.NetworkTopology{fatTree()}),
+	}
+
+	scores := make(map[string]int64)
+	for name, node := range map[string]*v1.Node{"same clique": sameClique, "other clique": otherClique} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		score, status := plugin.Score(context.Background(), framework.NewCycleState(), pods[1], nodeInfo)
+		if !status.IsSuccess() {
+			t.Fatalf("Score(%s) failed: %v", name, status.Message())
+		}
+		scores[name] = score
+	}
+	// Same leaf switch either way; only the NVLink clique differs
+	if scores["same clique"] <= scores["other clique"] {
+		t.Errorf("scores = %v, want the member's NVLink clique higher", scores)
+	}
+}
+
+func TestBuildTopologyModelBreaksCycles(t *testing.T) {
+	topology := &v1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "loop"},
+		Spec: v1alpha1.NetworkTopologySpec{Domains: []v1alpha1.TopologyDomain{
+			{Name: "a", Parent: "b", Nodes: []string{"n1"}},
+			{Name: "b", Parent: "a", Nodes: []string{"n2"}},
+		}},
+	}
+	model := buildTopologyModel([]*v1alpha1.NetworkTopology{topology})
+	if common := model.lowestCommonDomain("n1", "n2"); common == nil {
+		t.Error("nodes under the broken cycle should still share a domain")
+	}
+}
+
+func TestPlanUsesSmallestTopologySubtree(t *testing.T) {
+	model := buildTopologyModel([]*v1alpha1.NetworkTopology{fatTree()})
+	var nodes []*v1.Node
+	capacities := make(map[*v1.Node]int)
+	for _, name := range []string{"n1", "n2", "n3", "n4", "n5", "n6", "n7", "n8"} {
+		node := gpuNode(name, "rack-a", "az-1")
+		nodes = append(nodes, node)
+		capacities[node] = 1
+	}
+
+	tests := []struct {
+		name       string
+		needed     int
+		placed     []*v1.Node
+		wantDomain string
+	}{
+		{"fits in a leaf", 2, nil, "ib/leaf-1"},
+		{"needs a scalable unit", 3, nil, "ib/su-1"},
+		{"follows a placed member", 2, []*v1.Node{nodes[6]}, "ib/leaf-4"},
+		{"needs the superpod", 5, nil, "ib/superpod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planGangPlacement(tt.needed, tt.placed, capacities, model)
+			if plan == nil || plan.Domain != tt.wantDomain {
+				t.Fatalf("plan = %+v, want %s", plan, tt.wantDomain)
+			}
+			if !plan.contains(nodes[0]) && tt.wantDomain != "ib/leaf-4" {
+				t.Errorf("plan %s does not contain n1", plan.Domain)
+			}
+		})
+	}
+}
@@ -553,7 +553,7 @@ func TestRecordGangPlacement(t *testing.T) {
 	}
 
 	// Record first placement
-	plugin.recordGangPlacement(pod, 0, node)
+	plugin.recordGangPlacement(pod, 0, node.Name)
 
 	// Check gang state was created
 	gangState, exists := plugin.gangState["gang-1"]
@@ -584,7 +584,7 @@ func TestRecordGangPlacement(t *testing.T) {
 		},
 	}
 
-	plugin.recordGangPlacement(pod2, 1, node)
+	plugin.recordGangPlacement(pod2, 1, node.Name)
 
 	if len(gangState.AssignedMembers) != 2 {
 		t.Errorf("AssignedMembers length = %d, want 2", len(gangState.AssignedMembers))
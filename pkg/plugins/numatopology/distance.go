@@ -0,0 +1,168 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// AnnotationNUMADistances is the node's SLIT distance matrix, one row per NUMA node in
+	// ID order as in /sys/devices/system/node/nodeN/distance, rows separated by ";":
+	//   numa.kubenexus.io/distances: "10 21 32 32;21 10 32 32;32 32 10 21;32 32 21 10"
+	AnnotationNUMADistances = "numa.kubenexus.io/distances"
+
+	// AnnotationNUMAMemoryBandwidth is the memory bandwidth of each NUMA node in MB/s:
+	//   numa.kubenexus.io/memory-bandwidth: "0=204800,1=204800,2=51200"
+	AnnotationNUMAMemoryBandwidth = "numa.kubenexus.io/memory-bandwidth"
+
+	// NRTAttributeMemoryBandwidth is the NodeResourceTopology zone attribute carrying
+	// the zone's memory bandwidth in MB/s
+	NRTAttributeMemoryBandwidth = "memory-bandwidth"
+
+	// LabelNUMASocketFormat is the node label with the socket holding a NUMA node, for
+	// servers with several NUMA nodes per socket (AMD NPS2/NPS4, Intel SNC)
+	LabelNUMASocketFormat = "numa.kubenexus.io/node-%d-socket"
+)
+
+// applyNUMAAttributes fills in NUMA distances and memory bandwidth the topology source
+// left unset. Sources in order: the compact node annotations, then the per-pair
+// numa.kubenexus.io/node-N-distance-M and node-N-bandwidth labels. Sockets come from
+// numa.kubenexus.io/node-N-socket labels when set.
+func applyNUMAAttributes(node *v1.Node, numaNodes []NUMANode) {
+	distances, err := parseDistanceMatrix(node.Annotations[AnnotationNUMADistances])
+	if err != nil {
+		klog.V(4).InfoS("Ignoring invalid NUMA distance annotation", "node", node.Name, "error", err)
+		distances = nil
+	}
+	bandwidths, err := parseBandwidthList(node.Annotations[AnnotationNUMAMemoryBandwidth])
+	if err != nil {
+		klog.V(4).InfoS("Ignoring invalid NUMA memory bandwidth annotation", "node", node.Name, "error", err)
+		bandwidths = nil
+	}
+
+	for i := range numaNodes {
+		numa := &numaNodes[i]
+		if len(numa.Distance) == 0 {
+			numa.Distance = distances[numa.ID]
+			if len(numa.Distance) == 0 {
+				numa.Distance = labelDistances(node, numa.ID, numaNodes)
+			}
+		}
+		if numa.MemoryBandwidth == 0 {
+			numa.MemoryBandwidth = bandwidths[numa.ID]
+			if numa.MemoryBandwidth == 0 {
+				numa.MemoryBandwidth = labelBandwidth(node, numa.ID)
+			}
+		}
+		if socket, ok := node.Labels[fmt.Sprintf(LabelNUMASocketFormat, numa.ID)]; ok {
+			if id, err := strconv.Atoi(socket); err == nil {
+				numa.Socket = id
+			}
+		}
+	}
+	linkSockets(numaNodes)
+}
+
+// parseDistanceMatrix parses AnnotationNUMADistances into NUMA ID -> NUMA ID -> distance
+func parseDistanceMatrix(value string) (map[int]map[int]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	rows := strings.Split(value, ";")
+	matrix := make(map[int]map[int]int, len(rows))
+	for i, row := range rows {
+		fields := strings.Fields(strings.ReplaceAll(row, ",", " "))
+		if len(fields) != len(rows) {
+			return nil, fmt.Errorf("row %d has %d distances, want %d", i, len(fields), len(rows))
+		}
+		matrix[i] = make(map[int]int, len(fields))
+		for j, field := range fields {
+			distance, err := strconv.Atoi(field)
+			if err != nil || distance <= 0 {
+				return nil, fmt.Errorf("row %d: invalid distance %q", i, field)
+			}
+			matrix[i][j] = distance
+		}
+	}
+	return matrix, nil
+}
+
+// parseBandwidthList parses AnnotationNUMAMemoryBandwidth into NUMA ID -> MB/s
+func parseBandwidthList(value string) (map[int]int64, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	bandwidths := make(map[int]int64)
+	for _, entry := range strings.Split(value, ",") {
+		id, bw, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("entry %q must be <numa-id>=<MB/s>", entry)
+		}
+		numaID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: invalid NUMA ID", entry)
+		}
+		bandwidth, err := strconv.ParseInt(bw, 10, 64)
+		if err != nil || bandwidth < 0 {
+			return nil, fmt.Errorf("entry %q: invalid bandwidth", entry)
+		}
+		bandwidths[numaID] = bandwidth
+	}
+	return bandwidths, nil
+}
+
+// labelDistances reads numa.kubenexus.io/node-<id>-distance-<other> labels
+func labelDistances(node *v1.Node, numaID int, numaNodes []NUMANode) map[int]int {
+	distances := make(map[int]int)
+	for _, other := range numaNodes {
+		distLabel := fmt.Sprintf("numa.kubenexus.io/node-%d-distance-%d", numaID, other.ID)
+		if distStr, exists := node.Labels[distLabel]; exists {
+			if dist, err := strconv.Atoi(distStr); err == nil {
+				distances[other.ID] = dist
+			}
+		}
+	}
+	return distances
+}
+
+// labelBandwidth reads the numa.kubenexus.io/node-<id>-bandwidth label
+func labelBandwidth(node *v1.Node, numaID int) int64 {
+	memBandwidthLabel := fmt.Sprintf("numa.kubenexus.io/node-%d-bandwidth", numaID)
+	if bwStr, exists := node.Labels[memBandwidthLabel]; exists {
+		if bw, err := strconv.ParseInt(bwStr, 10, 64); err == nil {
+			return bw
+		}
+	}
+	return 0
+}
+
+// maxMemoryBandwidth returns the highest memory bandwidth among the NUMA nodes
+func maxMemoryBandwidth(numaNodes []NUMANode) int64 {
+	var highest int64
+	for _, numa := range numaNodes {
+		if numa.MemoryBandwidth > highest {
+			highest = numa.MemoryBandwidth
+		}
+	}
+	return highest
+}
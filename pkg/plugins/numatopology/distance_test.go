@@ -0,0 +1,105 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"testing"
+)
+
+func TestParseDistanceMatrix(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[int]map[int]int
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{
+			name:  "sysfs rows",
+			value: "10 21;21 10",
+			want:  map[int]map[int]int{0: {0: 10, 1: 21}, 1: {0: 21, 1: 10}},
+		},
+		{
+			name:  "comma separated",
+			value: "10,32;32,10",
+			want:  map[int]map[int]int{0: {0: 10, 1: 32}, 1: {0: 32, 1: 10}},
+		},
+		{name: "ragged", value: "10 21;21", wantErr: true},
+		{name: "not a number", value: "10 x;21 10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDistanceMatrix(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDistanceMatrix(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			for i, row := range tt.want {
+				for j, distance := range row {
+					if got[i][j] != distance {
+						t.Errorf("distance[%d][%d] = %d, want %d", i, j, got[i][j], distance)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestApplyNUMAAttributes(t *testing.T) {
+	node := numaNode("node-1")
+	node.Annotations = map[string]string{
+		AnnotationNUMADistances:       "10 32;32 10",
+		AnnotationNUMAMemoryBandwidth: "0=204800",
+	}
+	node.Labels["numa.kubenexus.io/node-1-bandwidth"] = "51200"
+
+	numaNodes := []NUMANode{{ID: 0}, {ID: 1}}
+	applyNUMAAttributes(node, numaNodes)
+
+	if got := numaNodes[1].Distance[0]; got != 32 {
+		t.Errorf("distance 1->0 = %d, want 32", got)
+	}
+	if got := numaNodes[0].MemoryBandwidth; got != 204800 {
+		t.Errorf("NUMA 0 bandwidth = %d, want 204800 from the annotation", got)
+	}
+	if got := numaNodes[1].MemoryBandwidth; got != 51200 {
+		t.Errorf("NUMA 1 bandwidth = %d, want 51200 from the label", got)
+	}
+
+	// Values from the topology source are kept
+	numaNodes = []NUMANode{{ID: 0, Distance: map[int]int{0: 10, 1: 12}, MemoryBandwidth: 1000}}
+	applyNUMAAttributes(node, numaNodes)
+	if numaNodes[0].Distance[1] != 12 || numaNodes[0].MemoryBandwidth != 1000 {
+		t.Errorf("applyNUMAAttributes overwrote source values: %+v", numaNodes[0])
+	}
+}
+
+func TestBandwidthScorePrefersFasterMemory(t *testing.T) {
+	plugin := &NUMATopology{}
+	node := numaNode("node-1")
+	numaNodes := []NUMANode{
+		{ID: 0, TotalCPUs: 16, AvailableCPUs: 16, TotalMemory: 64 * gib, AvailableMemory: 64 * gib, MemoryBandwidth: 51200},
+		{ID: 1, TotalCPUs: 16, AvailableCPUs: 16, TotalMemory: 64 * gib, AvailableMemory: 64 * gib, MemoryBandwidth: 204800},
+	}
+	pod := numaPod("stream", "", "4", "32Gi", "")
+	pod.Annotations[AnnotationMemoryIntensive] = "true"
+
+	choice := plugin.evaluateNUMANodes(pod, node, numaNodes, nil)
+	if choice.numaID != 1 {
+		t.Errorf("chose NUMA %d, want the high-bandwidth NUMA 1", choice.numaID)
+	}
+}
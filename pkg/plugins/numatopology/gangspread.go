@@ -0,0 +1,89 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	framework "k8s.io/kube-scheduler/framework"
+)
+
+// isIsolatedGangMember reports whether the pod belongs to a gang with the isolated spread
+func isIsolatedGangMember(pod *v1.Pod) bool {
+	return pod.Annotations[AnnotationGangGroup] != "" &&
+		pod.Annotations[AnnotationGangNUMASpread] == GangNUMASpreadIsolated
+}
+
+// blockedNUMANodes returns the NUMA nodes the pod may not use on this node under the
+// isolated gang spread. An isolated member needs a NUMA node no other pinned pod uses,
+// and a NUMA node held by an isolated member is not shared with any pod outside its
+// gang. Pods in the shared CPU pool have no NUMA node and are not constrained.
+func (n *NUMATopology) blockedNUMANodes(pod *v1.Pod, nodeInfo framework.NodeInfo) map[int]bool {
+	isolated := isIsolatedGangMember(pod)
+	gang := pod.Annotations[AnnotationGangGroup]
+	key := podKey(pod)
+
+	blocked := make(map[int]bool)
+	for _, podInfo := range nodeInfo.GetPods() {
+		other := podInfo.GetPod()
+		if podKey(other) == key {
+			continue
+		}
+		numaID, pending := n.pendingNUMANode(other)
+		if !pending {
+			numaID = recordedNUMANode(other)
+		}
+		if numaID < 0 {
+			continue
+		}
+		if isolated || (isIsolatedGangMember(other) && other.Annotations[AnnotationGangGroup] != gang) {
+			blocked[numaID] = true
+		}
+	}
+	return blocked
+}
+
+// linkSockets sets each NUMA node's Siblings: the NUMA nodes sharing its socket
+func linkSockets(numaNodes []NUMANode) {
+	bySocket := make(map[int][]int)
+	for _, numa := range numaNodes {
+		bySocket[numa.Socket] = append(bySocket[numa.Socket], numa.ID)
+	}
+	for i := range numaNodes {
+		siblings := append([]int(nil), bySocket[numaNodes[i].Socket]...)
+		sort.Ints(siblings)
+		numaNodes[i].Siblings = siblings
+	}
+}
+
+// sameSocket reports whether a gang member on memberNode/memberNUMA shares the socket
+// of numa on node. Members recorded without a node are compared by NUMA ID only.
+func sameSocket(memberNode string, memberNUMA int, node *v1.Node, numa NUMANode) bool {
+	if memberNode != "" && memberNode != node.Name {
+		return false
+	}
+	if memberNUMA == numa.ID {
+		return true
+	}
+	for _, sibling := range numa.Siblings {
+		if sibling == memberNUMA {
+			return true
+		}
+	}
+	return false
+}
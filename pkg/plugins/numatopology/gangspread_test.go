@@ -0,0 +1,117 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func isolatedPod(name, nodeName, gang, numaID string) *v1.Pod {
+	pod := gangPod(name, nodeName, gang, numaID)
+	pod.Annotations[AnnotationGangNUMASpread] = GangNUMASpreadIsolated
+	return pod
+}
+
+func TestFilterEnforcesIsolatedGangSpread(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		pods    []*v1.Pod
+		success bool
+	}{
+		{
+			name:    "isolated member takes the free NUMA node",
+			pod:     isolatedPod("worker-1", "", "job-1", ""),
+			pods:    []*v1.Pod{numaPod("other", "node-1", "2", "4Gi", "0")},
+			success: true,
+		},
+		{
+			name: "isolated member rejected when every NUMA node is pinned",
+			pod:  isolatedPod("worker-1", "", "job-1", ""),
+			pods: []*v1.Pod{
+				numaPod("other", "node-1", "2", "4Gi", "0"),
+				isolatedPod("worker-0", "node-1", "job-1", "1"),
+			},
+			success: false,
+		},
+		{
+			name: "other gang rejected from NUMA nodes held by isolated members",
+			pod:  gangPod("rank-0", "", "job-2", ""),
+			pods: []*v1.Pod{
+				isolatedPod("worker-0", "node-1", "job-1", "0"),
+				isolatedPod("worker-1", "node-1", "job-1", "1"),
+			},
+			success: false,
+		},
+		{
+			name:    "shared pool pods do not block isolated members",
+			pod:     isolatedPod("worker-0", "", "job-1", ""),
+			pods:    []*v1.Pod{numaPod("other", "node-1", "2", "4Gi", "")},
+			success: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := numaNode("node-1")
+			plugin := newTestPlugin(t, tt.pods, []*v1.Node{node})
+			nodeInfo := framework.NewNodeInfo(tt.pods...)
+			nodeInfo.SetNode(node)
+
+			status := plugin.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+			if status.IsSuccess() != tt.success {
+				t.Errorf("Filter success = %v, want %v: %v", status.IsSuccess(), tt.success, status.Message())
+			}
+		})
+	}
+}
+
+func TestBalancedGangSpreadsAcrossSockets(t *testing.T) {
+	node := numaNode("node-1")
+	node.Labels[LabelNUMANodeCount] = "4"
+	for id, socket := range []string{"0", "0", "1", "1"} {
+		node.Labels[fmt.Sprintf(LabelNUMASocketFormat, id)] = socket
+	}
+	numaNodes := []NUMANode{{ID: 0}, {ID: 1}, {ID: 2}, {ID: 3}}
+	applyNUMAAttributes(node, numaNodes)
+	if got := numaNodes[1].Siblings; len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("NUMA 1 siblings = %v, want [0 1]", got)
+	}
+
+	plugin := &NUMATopology{gangState: make(map[string]*GangNUMAState)}
+	member := gangPod("rank-0", "node-1", "mpi", "0")
+	member.Annotations[AnnotationGangNUMASpread] = GangNUMASpreadBalanced
+	plugin.recordGangPlacement(member, 0, "node-1")
+
+	pod := gangPod("rank-1", "", "mpi", "")
+	pod.Annotations[AnnotationGangNUMASpread] = GangNUMASpreadBalanced
+	sameSocket := plugin.calculateGangAffinityScore(pod, numaNodes[1], node)
+	otherSocket := plugin.calculateGangAffinityScore(pod, numaNodes[2], node)
+	if otherSocket <= sameSocket {
+		t.Errorf("other socket score %.1f should beat same socket score %.1f", otherSocket, sameSocket)
+	}
+
+	otherNode := numaNode("node-2")
+	if score := plugin.calculateGangAffinityScore(pod, numaNodes[0], otherNode); score != otherSocket {
+		t.Errorf("NUMA 0 on another node scored %.1f, want %.1f", score, otherSocket)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// Gang NUMA state lifecycle:
+//   - Reserve records the member's NUMA node; Unreserve removes it
+//   - PreBind writes the NUMA node to the pod (AnnotationNUMANode)
+//   - The pod informer replays bound members from that annotation, which rebuilds the
+//     state after a scheduler restart or leader failover, and drops deleted or finished pods
+
+// gangPodEventHandler keeps gangState in sync with gang members in the cluster
+func (n *NUMATopology) gangPodEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*v1.Pod); ok {
+				n.observeGangPod(pod)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if pod, ok := newObj.(*v1.Pod); ok {
+				n.observeGangPod(pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*v1.Pod); ok {
+				n.removeGangMember(pod)
+			}
+		},
+	}
+}
+
+// observeGangPod records a bound gang member from its NUMA node annotation
+func (n *NUMATopology) observeGangPod(pod *v1.Pod) {
+	if pod.Annotations[AnnotationGangGroup] == "" {
+		return
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		n.removeGangMember(pod)
+		return
+	}
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	if numaID := recordedNUMANode(pod); numaID >= 0 {
+		n.recordGangPlacement(pod, numaID, pod.Spec.NodeName)
+	}
+}
+
+// recordGangPlacement records the NUMA placement decision for a gang member.
+func (n *NUMATopology) recordGangPlacement(pod *v1.Pod, numaID int, nodeName string) {
+	gangGroup, exists := pod.Annotations[AnnotationGangGroup]
+	if !exists || gangGroup == "" {
+		return
+	}
+
+	// Lock for gang state access
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Initialize gang state if needed
+	if n.gangState == nil {
+		n.gangState = make(map[string]*GangNUMAState)
+	}
+
+	gangState, exists := n.gangState[gangGroup]
+	if !exists {
+		gangState = &GangNUMAState{
+			GangGroup:       gangGroup,
+			AssignedMembers: make(map[string]int),
+			MemberNodes:     make(map[string]string),
+		}
+
+		// Get spread policy
+		if policy, exists := pod.Annotations[AnnotationGangNUMASpread]; exists {
+			gangState.SpreadPolicy = policy
+		} else {
+			gangState.SpreadPolicy = GangNUMASpreadPacked
+		}
+
+		n.gangState[gangGroup] = gangState
+	}
+
+	// Record assignment
+	key := podKey(pod)
+	if current, ok := gangState.AssignedMembers[key]; ok && current == numaID && gangState.MemberNodes[key] == nodeName {
+		return
+	}
+	gangState.AssignedMembers[key] = numaID
+	if gangState.MemberNodes == nil {
+		gangState.MemberNodes = make(map[string]string)
+	}
+	gangState.MemberNodes[key] = nodeName
+
+	klog.V(4).InfoS("Recorded gang placement",
+		"podKey", key, "numaNode", numaID, "node", nodeName, "policy", gangState.SpreadPolicy, "totalMembers", len(gangState.AssignedMembers))
+}
+
+// removeGangMember forgets a gang member, and the gang once it has no members left
+func (n *NUMATopology) removeGangMember(pod *v1.Pod) {
+	gangGroup := pod.Annotations[AnnotationGangGroup]
+	if gangGroup == "" {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	gangState, exists := n.gangState[gangGroup]
+	if !exists {
+		return
+	}
+	key := podKey(pod)
+	if _, ok := gangState.AssignedMembers[key]; !ok {
+		return
+	}
+	delete(gangState.AssignedMembers, key)
+	delete(gangState.MemberNodes, key)
+	if len(gangState.AssignedMembers) == 0 {
+		delete(n.gangState, gangGroup)
+	}
+
+	klog.V(4).InfoS("Removed gang placement", "podKey", key, "gangGroup", gangGroup, "remainingMembers", len(gangState.AssignedMembers))
+}
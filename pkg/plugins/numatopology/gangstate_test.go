@@ -0,0 +1,101 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// gangPod builds a guaranteed gang member, recorded on numaID when it is set
+func gangPod(name, nodeName, gang, numaID string) *v1.Pod {
+	pod := numaPod(name, nodeName, "4", "8Gi", numaID)
+	pod.Annotations[AnnotationGangGroup] = gang
+	return pod
+}
+
+func (n *NUMATopology) gangMembers(gang string) map[string]int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if state, ok := n.gangState[gang]; ok {
+		return state.AssignedMembers
+	}
+	return nil
+}
+
+func TestGangPlacementRecordedAtReserve(t *testing.T) {
+	node := numaNode("node-1")
+	pod := gangPod("worker-0", "", "job-1", "")
+	plugin := newTestPlugin(t, []*v1.Pod{pod}, []*v1.Node{node})
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	state := framework.NewCycleState()
+	if _, status := plugin.Score(context.Background(), state, pod, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("Score failed: %v", status.Message())
+	}
+	if members := plugin.gangMembers("job-1"); len(members) != 0 {
+		t.Fatalf("Score recorded gang placement: %v", members)
+	}
+
+	if status := plugin.Reserve(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("Reserve failed: %v", status.Message())
+	}
+	if members := plugin.gangMembers("job-1"); len(members) != 1 {
+		t.Fatalf("Reserve recorded %v, want one member", members)
+	}
+
+	plugin.Unreserve(context.Background(), state, pod, node.Name)
+	plugin.mu.RLock()
+	_, exists := plugin.gangState["job-1"]
+	plugin.mu.RUnlock()
+	if exists {
+		t.Error("gang state not removed after its only member was unreserved")
+	}
+}
+
+func TestGangStateRebuiltFromBoundPods(t *testing.T) {
+	plugin := &NUMATopology{gangState: make(map[string]*GangNUMAState)}
+	handler := plugin.gangPodEventHandler()
+
+	bound := gangPod("worker-0", "node-1", "job-1", "1")
+	pending := gangPod("worker-1", "", "job-1", "")
+	finished := gangPod("worker-2", "node-2", "job-1", "0")
+
+	handler.OnAdd(bound, true)
+	handler.OnAdd(pending, true)
+	handler.OnAdd(finished, true)
+	if members := plugin.gangMembers("job-1"); len(members) != 2 || members["default/worker-0"] != 1 {
+		t.Fatalf("rebuilt members = %v, want worker-0 on NUMA 1 and worker-2", members)
+	}
+
+	done := finished.DeepCopy()
+	done.Status.Phase = v1.PodSucceeded
+	handler.OnUpdate(finished, done)
+	if members := plugin.gangMembers("job-1"); len(members) != 1 {
+		t.Errorf("members after completion = %v, want only worker-0", members)
+	}
+
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/worker-0", Obj: bound})
+	if members := plugin.gangMembers("job-1"); members != nil {
+		t.Errorf("members after deletion = %v, want gang removed", members)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	nrtGroupVersion = "topology.node.k8s.io/v1alpha2"
+
+	// nrtZoneTypeNode is the zone type NodeResourceTopology uses for NUMA nodes
+	nrtZoneTypeNode = "Node"
+)
+
+var nrtGVR = schema.GroupVersionResource{Group: "topology.node.k8s.io", Version: "v1alpha2", Resource: "noderesourcetopologies"}
+
+// nrtLister reads per-NUMA available resources from NodeResourceTopology objects,
+// published per node by the NFD topology-updater or the RTE exporter. Available
+// capacity there already reflects the kubelet's exclusive CPU and memory allocations.
+type nrtLister struct {
+	lister    cache.GenericLister
+	hasSynced func() bool
+}
+
+// newNRTLister starts a NodeResourceTopology informer. It returns nil when the CRD
+// is not installed, so clusters without it fall back to label-based accounting.
+func newNRTLister(ctx context.Context, disc discovery.DiscoveryInterface, client dynamic.Interface) *nrtLister {
+	if disc == nil || client == nil {
+		return nil
+	}
+	if _, err := disc.ServerResourcesForGroupVersion(nrtGroupVersion); err != nil {
+		klog.V(3).InfoS("NodeResourceTopology API not available, using label-based NUMA accounting", "groupVersion", nrtGroupVersion, "error", err)
+		return nil
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	informer := factory.ForResource(nrtGVR)
+	factory.Start(ctx.Done())
+
+	return &nrtLister{
+		lister:    informer.Lister(),
+		hasSynced: informer.Informer().HasSynced,
+	}
+}
+
+// numaNodes returns the node's NUMA zones from its NodeResourceTopology object and
+// the object's resourceVersion, which changes each time the exporter refreshes it
+func (l *nrtLister) numaNodes(nodeName string) ([]NUMANode, string, bool) {
+	if l == nil || !l.hasSynced() {
+		return nil, "", false
+	}
+	obj, err := l.lister.Get(nodeName)
+	if err != nil {
+		klog.V(5).InfoS("No NodeResourceTopology for node", "node", nodeName, "error", err)
+		return nil, "", false
+	}
+	nrt, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, "", false
+	}
+
+	numaNodes, err := parseNRTZones(nrt)
+	if err != nil || len(numaNodes) == 0 {
+		klog.V(4).InfoS("Ignoring NodeResourceTopology without usable NUMA zones", "node", nodeName, "error", err)
+		return nil, "", false
+	}
+	return numaNodes, nrt.GetResourceVersion(), true
+}
+
+// parseNRTZones converts the Node-type zones of a NodeResourceTopology:
+//
+//	zones:
+//	- name: node-0
+//	  type: Node
+//	  resources:
+//	  - {name: cpu, capacity: "32", allocatable: "30", available: "12"}
+//	  - {name: memory, capacity: "270Gi", allocatable: "260Gi", available: "100Gi"}
+//	  costs:
+//	  - {name: node-0, value: 10}
+//	  - {name: node-1, value: 21}
+//	  attributes:
+//	  - {name: memory-bandwidth, value: "204800"}
+func parseNRTZones(nrt *unstructured.Unstructured) ([]NUMANode, error) {
+	zones, found, err := unstructured.NestedSlice(nrt.Object, "zones")
+	if err != nil || !found {
+		return nil, fmt.Errorf("zones missing: %v", err)
+	}
+
+	var numaNodes []NUMANode
+	for _, z := range zones {
+		zone, ok := z.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if zoneType, _, _ := unstructured.NestedString(zone, "type"); zoneType != nrtZoneTypeNode {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(zone, "name")
+		id, err := strconv.Atoi(strings.TrimPrefix(name, "node-"))
+		if err != nil {
+			klog.V(5).InfoS("Skipping NodeResourceTopology zone with unexpected name", "zone", name)
+			continue
+		}
+
+		numa := NUMANode{ID: id, Socket: id, Distance: nrtZoneCosts(zone)}
+		// Node zones are children of their socket zone, e.g. parent: socket-1
+		if parent, _, _ := unstructured.NestedString(zone, "parent"); parent != "" {
+			if socket, err := strconv.Atoi(parent[strings.LastIndex(parent, "-")+1:]); err == nil {
+				numa.Socket = socket
+			}
+		}
+		resources, _, _ := unstructured.NestedSlice(zone, "resources")
+		for _, r := range resources {
+			res, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch resName, _, _ := unstructured.NestedString(res, "name"); v1.ResourceName(resName) {
+			case v1.ResourceCPU:
+				numa.TotalCPUs = int(nrtQuantity(res, "allocatable"))
+				numa.AvailableCPUs = int(nrtQuantity(res, "available"))
+			case v1.ResourceMemory:
+				numa.TotalMemory = nrtQuantity(res, "allocatable")
+				numa.AvailableMemory = nrtQuantity(res, "available")
+			default:
+				// Hugepages and device plugin resources such as GPUs and SR-IOV VFs
+				if numa.Resources == nil {
+					numa.Resources = make(map[v1.ResourceName]int64)
+					numa.AvailableResources = make(map[v1.ResourceName]int64)
+				}
+				numa.Resources[v1.ResourceName(resName)] = nrtQuantity(res, "allocatable")
+				numa.AvailableResources[v1.ResourceName(resName)] = nrtQuantity(res, "available")
+			}
+		}
+		if bw, ok := nrtZoneAttribute(zone, NRTAttributeMemoryBandwidth); ok {
+			numa.MemoryBandwidth, _ = strconv.ParseInt(bw, 10, 64)
+		}
+		numaNodes = append(numaNodes, numa)
+	}
+
+	sort.Slice(numaNodes, func(i, j int) bool { return numaNodes[i].ID < numaNodes[j].ID })
+	return numaNodes, nil
+}
+
+// nrtQuantity parses a quantity field of an NRT resource entry, 0 if absent or invalid
+func nrtQuantity(res map[string]interface{}, field string) int64 {
+	value, found, _ := unstructured.NestedFieldNoCopy(res, field)
+	if !found {
+		return 0
+	}
+	switch v := value.(type) {
+	case string:
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return 0
+		}
+		return q.Value()
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// nrtZoneCosts converts a zone's costs, the SLIT distances to each NUMA zone
+func nrtZoneCosts(zone map[string]interface{}) map[int]int {
+	distances := make(map[int]int)
+	costs, _, _ := unstructured.NestedSlice(zone, "costs")
+	for _, c := range costs {
+		cost, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(cost, "name")
+		id, err := strconv.Atoi(strings.TrimPrefix(name, "node-"))
+		if err != nil {
+			continue
+		}
+		if value := nrtQuantity(cost, "value"); value > 0 {
+			distances[id] = int(value)
+		}
+	}
+	return distances
+}
+
+// nrtZoneAttribute returns the value of a zone attribute
+func nrtZoneAttribute(zone map[string]interface{}, name string) (string, bool) {
+	attributes, _, _ := unstructured.NestedSlice(zone, "attributes")
+	for _, a := range attributes {
+		attr, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if attrName, _, _ := unstructured.NestedString(attr, "name"); attrName == name {
+			value, _, _ := unstructured.NestedString(attr, "value")
+			return value, true
+		}
+	}
+	return "", false
+}
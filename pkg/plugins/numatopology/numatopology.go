@@ -27,6 +27,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
 
@@ -57,6 +58,8 @@ import (
 //   3. Multi-node awareness: Choose nodes with optimal NUMA topology
 //   4. Gang scheduling support: Ensure all gang members get NUMA locality
 //   5. Workload-aware: Only applies strict NUMA rules to batch/ML workloads
+//   6. Usage accounting: Free capacity per NUMA node from NodeResourceTopology, or from
+//      labels minus pods on the node (see getNUMANodes)
 //
 // EXAMPLE:
 //   Node A (2 NUMA nodes):
@@ -136,33 +139,47 @@ const (
 	WeightGangAffinity    = 0.15 // 15% weight for gang member affinity
 
 	// GPU-NUMA co-alignment
-	BonusGPUNUMACoLocation = 15 // Bonus when GPU and CPU are on same NUMA
-	PenaltyGPUNUMAMismatch = 25 // Penalty when GPU and CPU on different NUMA
+	BonusGPUNUMACoLocation = 15 // Bonus when GPUs/NICs and CPU are on same NUMA
+	PenaltyGPUNUMAMismatch = 25 // Penalty when GPUs/NICs and CPU on different NUMA
 )
 
 // NUMANode represents a single NUMA node on a server
 type NUMANode struct {
 	ID              int         // NUMA node ID (0, 1, 2, ...)
 	CPUs            []int       // CPU IDs in this NUMA node
+	TotalCPUs       int         // Allocatable CPUs
 	TotalMemory     int64       // Total memory in bytes
 	AvailableCPUs   int         // Available (unallocated) CPUs
 	AvailableMemory int64       // Available memory in bytes
 	Distance        map[int]int // Distance to other NUMA nodes (node ID -> distance)
 	MemoryBandwidth int64       // Memory bandwidth in MB/s (optional)
+	Socket          int         // Socket (package) holding this NUMA node; defaults to the NUMA node ID
+	Siblings        []int       // NUMA node IDs in the same socket, including this one
+
+	Resources          map[v1.ResourceName]int64 // Hugepages (bytes) and devices attached to this NUMA node
+	AvailableResources map[v1.ResourceName]int64 // Unallocated hugepages and devices
 }
 
 // GangNUMAState tracks NUMA placement decisions for gang members
 type GangNUMAState struct {
-	GangGroup       string         // Gang group name
-	AssignedMembers map[string]int // Pod name -> NUMA node ID
-	SpreadPolicy    string         // Gang NUMA spread policy
+	GangGroup       string            // Gang group name
+	AssignedMembers map[string]int    // Pod name -> NUMA node ID
+	MemberNodes     map[string]string // Pod name -> node name
+	SpreadPolicy    string            // Gang NUMA spread policy
 }
 
 // NUMATopology implements NUMA-aware scheduling with advanced features
 type NUMATopology struct {
 	handle    framework.Handle
-	mu        sync.RWMutex              // Protect gangState from concurrent access
+	nrt       *nrtLister                // NodeResourceTopology lister, nil when the CRD is absent
+	mu        sync.RWMutex              // Protect gangState and assignments from concurrent access
 	gangState map[string]*GangNUMAState // Gang group -> state
+	// assignments holds pods placed here that the node's accounting source has not yet
+	// seen (pod key -> assignment)
+	assignments map[string]podAssignment
+	// nrtVersions is the NodeResourceTopology resourceVersion assignments were last
+	// pruned against (node name -> resourceVersion)
+	nrtVersions map[string]string
 }
 
 // Name returns the name of the plugin.
@@ -177,7 +194,7 @@ func (n *NUMATopology) Name() string {
 //
 // Algorithm:
 //  1. Check if pod requires NUMA awareness (batch/ML workload or explicit annotation)
-//  2. Parse node's NUMA topology from labels
+//  2. Get node's NUMA topology and free capacity (NodeResourceTopology or labels)
 //  3. Check if pod's resource requests fit in ANY single NUMA node
 //  4. If yes → allow node; if no → reject node
 func (n *NUMATopology) Filter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
@@ -195,15 +212,15 @@ func (n *NUMATopology) Filter(ctx context.Context, state framework.CycleState, p
 		return framework.NewStatus(framework.Success, "")
 	}
 
-	if policy == NUMAPolicyBestEffort {
+	if policy == NUMAPolicyBestEffort && !isIsolatedGangMember(pod) {
 		// Best effort policy - don't filter, just score
 		klog.V(5).InfoS("NUMATopology: pod has best-effort NUMA policy, allowing node",
 			"pod", klog.KObj(pod), "node", node.Name)
 		return framework.NewStatus(framework.Success, "")
 	}
 
-	// Policy is single-numa-node (strict) - enforce filtering
-	numaNodes, err := n.parseNUMATopology(node)
+	// Policy is single-numa-node (strict) or an isolated gang spread - enforce filtering
+	numaNodes, err := n.getNUMANodes(nodeInfo)
 	if err != nil {
 		// Node has no NUMA topology information, allow it (assume single NUMA or kubelet will handle)
 		klog.V(4).InfoS("NUMATopology: node has no NUMA topology labels", "node", node.Name, "err", err)
@@ -216,31 +233,43 @@ func (n *NUMATopology) Filter(ctx context.Context, state framework.CycleState, p
 		return framework.NewStatus(framework.Success, "")
 	}
 
-	// Calculate pod resource requirements
-	podCPU, podMemory := n.getPodResourceRequests(pod)
-
-	// Get GPU-NUMA mapping if pod requests GPUs
-	gpuNUMAMapping := n.getGPUNUMAMapping(node, pod)
+	// Calculate pod resource requirements (init containers, overhead, hugepages, devices)
+	req := podNUMARequest(pod)
+	tracked := trackedResources(numaNodes)
+	blocked := n.blockedNUMANodes(pod, nodeInfo)
 
 	// Check if pod fits in any single NUMA node
-	for _, numa := range numaNodes {
-		if numa.AvailableCPUs >= int(podCPU) && numa.AvailableMemory >= podMemory {
-			// If pod requests GPUs, verify co-alignment
-			if len(gpuNUMAMapping) > 0 && !n.canFitGPUsInNUMA(pod, &numa, gpuNUMAMapping) {
-				klog.V(5).InfoS("NUMATopology: skipping NUMA due to GPU-NUMA mismatch",
+	for i := range numaNodes {
+		numa := &numaNodes[i]
+		if blocked[numa.ID] {
+			klog.V(5).InfoS("NUMATopology: skipping NUMA held under isolated gang spread",
+				"pod", klog.KObj(pod), "numaNode", numa.ID, "node", node.Name)
+			continue
+		}
+		if fitsNUMANode(req, numa, tracked) {
+			// If pod requests GPUs or NICs, verify co-alignment as the kubelet topology manager will
+			if requested, aligned := devicesOnNUMANode(req, numa, tracked); requested && !aligned {
+				klog.V(5).InfoS("NUMATopology: skipping NUMA due to device-NUMA mismatch",
 					"pod", klog.KObj(pod), "numaNode", numa.ID, "node", node.Name)
 				continue
 			}
 
 			klog.V(4).InfoS("NUMATopology: pod fits in NUMA node",
-				"pod", klog.KObj(pod), "cpu", podCPU, "memoryGB", podMemory/(1024*1024*1024), "numaNode", numa.ID, "node", node.Name)
+				"pod", klog.KObj(pod), "cpu", req.cpus(), "memoryGB", req.memory/(1024*1024*1024), "numaNode", numa.ID, "node", node.Name)
 			return framework.NewStatus(framework.Success, "")
 		}
 	}
 
 	// Pod cannot fit in any single NUMA node
 	reason := fmt.Sprintf("pod requires %d CPUs and %d bytes memory, but no single NUMA node has sufficient capacity on node %s",
-		podCPU, podMemory, node.Name)
+		req.cpus(), req.memory, node.Name)
+	if len(blocked) > 0 {
+		reason = fmt.Sprintf("pod requires %d CPUs and %d bytes memory on a NUMA node not held under an isolated gang spread, but none has sufficient capacity on node %s",
+			req.cpus(), req.memory, node.Name)
+	} else if len(req.resources) > 0 {
+		reason = fmt.Sprintf("pod requires %d CPUs, %d bytes memory and %v, but no single NUMA node has sufficient capacity on node %s",
+			req.cpus(), req.memory, req.resources, node.Name)
+	}
 
 	klog.V(3).InfoS("NUMATopology: rejecting node for pod",
 		"node", node.Name, "pod", klog.KObj(pod), "reason", reason)
@@ -279,16 +308,59 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 		return MaxNodeScore / 2, framework.NewStatus(framework.Success, "")
 	}
 
-	// Parse NUMA topology
-	numaNodes, err := n.parseNUMATopology(node)
+	// Get NUMA topology with current usage
+	numaNodes, err := n.getNUMANodes(nodeInfo)
 	if err != nil || len(numaNodes) <= 1 {
 		// No NUMA info or single NUMA node, return neutral score
 		schedulermetrics.NumaPlacementDecisions.WithLabelValues(policy, "no_numa_info", workload.ClassifyPod(pod).String()).Inc()
 		return MaxNodeScore / 2, framework.NewStatus(framework.Success, "")
 	}
 
+	choice := n.evaluateNUMANodes(pod, node, numaNodes, n.blockedNUMANodes(pod, nodeInfo))
+	bestNUMAID, bestScore := choice.numaID, choice.score
+
+	if bestNUMAID == -1 {
+		// Pod doesn't fit in any NUMA node (only possible with best-effort policy)
+		// Return low score (but not zero - still schedulable)
+		klog.V(4).InfoS("NUMATopology: pod requires cross-NUMA placement (best-effort policy)",
+			"pod", klog.KObj(pod), "node", node.Name)
+		schedulermetrics.NumaPlacementDecisions.WithLabelValues(policy, "cross_numa", workload.ClassifyPod(pod).String()).Inc()
+		return MaxNodeScore / 4, framework.NewStatus(framework.Success, "")
+	}
+
+	klog.V(4).InfoS("NUMATopology: pod best fits in NUMA node",
+		"pod", klog.KObj(pod), "numaNode", bestNUMAID, "node", node.Name, "score", bestScore, "fitScore", choice.fitScore, "memBandwidthScore", choice.memBandwidthScore, "distanceScore", choice.distanceScore, "gangScore", choice.gangScore)
+	schedulermetrics.NumaFitQuality.WithLabelValues(policy).Observe(choice.fitScore)
+
+	// Track successful single-NUMA placement
+	schedulermetrics.NumaPlacementDecisions.WithLabelValues(policy, "single_numa", workload.ClassifyPod(pod).String()).Inc()
+
+	return int64(bestScore), framework.NewStatus(framework.Success, "")
+}
+
+// ScoreExtensions returns a ScoreExtensions interface if it implements one, or nil if not.
+func (n *NUMATopology) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// numaChoice is the best NUMA node for a pod on one node and its score components
+type numaChoice struct {
+	numaID            int // -1 when the pod fits in no single NUMA node
+	score             float64
+	fitScore          float64
+	memBandwidthScore float64
+	distanceScore     float64
+	gangScore         float64
+}
+
+// evaluateNUMANodes scores every NUMA node the pod fits in, except blocked ones, and
+// returns the best one. Score uses it to rank nodes and Reserve to pick the NUMA node
+// on the chosen node.
+func (n *NUMATopology) evaluateNUMANodes(pod *v1.Pod, node *v1.Node, numaNodes []NUMANode, blocked map[int]bool) numaChoice {
 	// Calculate pod requirements
-	podCPU, podMemory := n.getPodResourceRequests(pod)
+	req := podNUMARequest(pod)
+	podCPU, podMemory := req.cpus(), req.memory
+	tracked := trackedResources(numaNodes)
 
 	// Check if pod is memory-intensive
 	isMemoryIntensive := n.isMemoryIntensive(pod)
@@ -296,19 +368,10 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 	// Get NUMA affinity preferences
 	preferredNUMAs, avoidNUMAs := n.getNUMAAffinityPreferences(pod)
 
-	// Get GPU-NUMA mapping
-	gpuNUMAMapping := n.getGPUNUMAMapping(node, pod)
-
-	// Find best NUMA node fit
-	var bestScore float64
-	bestNUMAID := -1
-
-	// Component scores
-	var fitScore, memBandwidthScore, distanceScore, gangScore float64
-
+	best := numaChoice{numaID: -1}
 	for _, numa := range numaNodes {
-		if numa.AvailableCPUs < int(podCPU) || numa.AvailableMemory < podMemory {
-			// Pod doesn't fit in this NUMA node
+		if blocked[numa.ID] || !fitsNUMANode(req, &numa, tracked) {
+			// Pod doesn't fit in this NUMA node, or it is held under an isolated gang spread
 			continue
 		}
 
@@ -324,17 +387,23 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 		memRemaining := float64(numa.AvailableMemory - podMemory)
 
 		// Normalize to 0-1 range (higher is better - more room for growth)
-		cpuFitScore := cpuRemaining / float64(len(numa.CPUs))
+		cpuFitScore := 0.0
+		if numa.TotalCPUs > 0 {
+			cpuFitScore = cpuRemaining / float64(numa.TotalCPUs)
+		}
 		if cpuFitScore < 0 {
 			cpuFitScore = 0
 		}
-		memFitScore := memRemaining / float64(numa.TotalMemory)
+		memFitScore := 0.0
+		if numa.TotalMemory > 0 {
+			memFitScore = memRemaining / float64(numa.TotalMemory)
+		}
 		if memFitScore < 0 {
 			memFitScore = 0
 		}
 
 		// Weighted average: 60% CPU, 40% memory (prefer CPU locality)
-		fitScore = (cpuFitScore*0.6 + memFitScore*0.4) * 100.0
+		fitScore := (cpuFitScore*0.6 + memFitScore*0.4) * 100.0
 
 		// Boost if in preferred NUMA list
 		if n.isNUMAInList(numa.ID, preferredNUMAs) {
@@ -343,12 +412,16 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 		}
 
 		// 2. MEMORY BANDWIDTH SCORE (25%)
-		memBandwidthScore = 50.0 // Default neutral score
-		if isMemoryIntensive && numa.MemoryBandwidth > 0 {
-			// Calculate memory bandwidth pressure based on requested vs available memory
+		memBandwidthScore := 50.0 // Default neutral score
+		if isMemoryIntensive && numa.MemoryBandwidth > 0 && numa.TotalMemory > 0 {
+			// Calculate memory bandwidth pressure based on memory in use plus the request
 			// Lower utilization = higher available bandwidth = higher score
-			bandwidthUtilization := (float64(podMemory) / float64(numa.TotalMemory)) * 100.0
-			memBandwidthScore = 100.0 - bandwidthUtilization
+			usedMemory := numa.TotalMemory - numa.AvailableMemory + podMemory
+			bandwidthUtilization := (float64(usedMemory) / float64(numa.TotalMemory)) * 100.0
+			// Scale by the NUMA node's share of the best bandwidth on the node, so
+			// DRAM-backed NUMA nodes beat slower CXL or remote memory tiers
+			bandwidthShare := float64(numa.MemoryBandwidth) / float64(maxMemoryBandwidth(numaNodes))
+			memBandwidthScore = (100.0 - bandwidthUtilization) * bandwidthShare
 			if memBandwidthScore < 0 {
 				memBandwidthScore = 0
 			}
@@ -357,13 +430,13 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 		}
 
 		// 3. NUMA DISTANCE SCORE (20%)
-		distanceScore = n.calculateNUMADistanceScore(numa, numaNodes, pod)
+		distanceScore := n.calculateNUMADistanceScore(numa, numaNodes, pod)
 
 		// 4. GANG AFFINITY SCORE (15%)
-		gangScore = n.calculateGangAffinityScore(pod, numa, node)
+		gangScore := n.calculateGangAffinityScore(pod, numa, node)
 
-		// 5. GPU-NUMA CO-ALIGNMENT BONUS (applied as adjustment)
-		gpuBonus := float64(n.calculateGPUNUMABonus(pod, &numa, gpuNUMAMapping))
+		// 5. DEVICE-NUMA CO-ALIGNMENT BONUS (applied as adjustment)
+		gpuBonus := float64(calculateDeviceNUMABonus(req, &numa, tracked))
 
 		// Calculate weighted total score
 		totalScore := (fitScore * WeightNUMAFit) +
@@ -379,37 +452,18 @@ func (n *NUMATopology) Score(ctx context.Context, state framework.CycleState, po
 			totalScore = 0
 		}
 
-		if totalScore > bestScore {
-			bestScore = totalScore
-			bestNUMAID = numa.ID
-			schedulermetrics.NumaFitQuality.WithLabelValues(policy).Observe(fitScore)
+		if best.numaID == -1 || totalScore > best.score {
+			best = numaChoice{
+				numaID:            numa.ID,
+				score:             totalScore,
+				fitScore:          fitScore,
+				memBandwidthScore: memBandwidthScore,
+				distanceScore:     distanceScore,
+				gangScore:         gangScore,
+			}
 		}
 	}
-
-	if bestNUMAID == -1 {
-		// Pod doesn't fit in any NUMA node (only possible with best-effort policy)
-		// Return low score (but not zero - still schedulable)
-		klog.V(4).InfoS("NUMATopology: pod requires cross-NUMA placement (best-effort policy)",
-			"pod", klog.KObj(pod), "node", node.Name)
-		schedulermetrics.NumaPlacementDecisions.WithLabelValues(policy, "cross_numa", workload.ClassifyPod(pod).String()).Inc()
-		return MaxNodeScore / 4, framework.NewStatus(framework.Success, "")
-	}
-
-	klog.V(4).InfoS("NUMATopology: pod best fits in NUMA node",
-		"pod", klog.KObj(pod), "numaNode", bestNUMAID, "node", node.Name, "score", bestScore, "fitScore", fitScore, "memBandwidthScore", memBandwidthScore, "distanceScore", distanceScore, "gangScore", gangScore)
-
-	// Track successful single-NUMA placement
-	schedulermetrics.NumaPlacementDecisions.WithLabelValues(policy, "single_numa", workload.ClassifyPod(pod).String()).Inc()
-
-	// Track gang placement
-	n.recordGangPlacement(pod, bestNUMAID, node)
-
-	return int64(bestScore), framework.NewStatus(framework.Success, "")
-}
-
-// ScoreExtensions returns a ScoreExtensions interface if it implements one, or nil if not.
-func (n *NUMATopology) ScoreExtensions() framework.ScoreExtensions {
-	return nil
+	return best
 }
 
 // getNUMAPolicy determines the NUMA policy for a pod.
@@ -456,6 +510,7 @@ func (n *NUMATopology) getNUMAPolicy(pod *v1.Pod) string {
 //	numa.kubenexus.io/node-1-cpus: "16-31,48-63"
 //	numa.kubenexus.io/node-1-memory: "68719476736"
 //
+// Distances and memory bandwidth are optional, see applyNUMAAttributes.
 // These labels should be set by a node labeler DaemonSet or kubelet.
 func (n *NUMATopology) parseNUMATopology(node *v1.Node) ([]NUMANode, error) {
 	// Check if node has NUMA count label
@@ -500,36 +555,15 @@ func (n *NUMATopology) parseNUMATopology(node *v1.Node) ([]NUMANode, error) {
 			continue
 		}
 
-		// Parse memory bandwidth (optional)
-		memBandwidthLabel := fmt.Sprintf("numa.kubenexus.io/node-%d-bandwidth", i)
-		bandwidth := int64(0)
-		if bwStr, exists := node.Labels[memBandwidthLabel]; exists {
-			if bw, err := strconv.ParseInt(bwStr, 10, 64); err == nil {
-				bandwidth = bw
-			}
-		}
-
-		// Parse NUMA distances (optional)
-		distances := make(map[int]int)
-		for j := 0; j < count; j++ {
-			distLabel := fmt.Sprintf("numa.kubenexus.io/node-%d-distance-%d", i, j)
-			if distStr, exists := node.Labels[distLabel]; exists {
-				if dist, err := strconv.Atoi(distStr); err == nil {
-					distances[j] = dist
-				}
-			}
-		}
-
-		// Initialize NUMA node with full capacity
-		// Kubelet Topology Manager tracks actual allocations on the node
+		// Initialize NUMA node with full capacity; getNUMANodes subtracts pods on the node
 		numaNodes = append(numaNodes, NUMANode{
 			ID:              i,
 			CPUs:            cpus,
+			Socket:          i,
+			TotalCPUs:       len(cpus),
 			TotalMemory:     memory,
 			AvailableCPUs:   len(cpus),
 			AvailableMemory: memory,
-			Distance:        distances,
-			MemoryBandwidth: bandwidth,
 		})
 	}
 
@@ -537,6 +571,10 @@ func (n *NUMATopology) parseNUMATopology(node *v1.Node) ([]NUMANode, error) {
 		return nil, fmt.Errorf("no valid NUMA nodes found on %s", node.Name)
 	}
 
+	// Distances, memory bandwidth, hugepages and devices (optional)
+	applyNUMAAttributes(node, numaNodes)
+	applyNUMAResources(node, numaNodes)
+
 	return numaNodes, nil
 }
 
@@ -576,34 +614,42 @@ func parseCPUList(cpuList string) ([]int, error) {
 	return cpus, nil
 }
 
-// getPodResourceRequests calculates the total CPU and memory requests for a pod.
-// Returns (cpu in millicores, memory in bytes)
+// getPodResourceRequests calculates the effective CPU and memory requests for a pod.
+// Returns (cpu in whole cores, memory in bytes)
 func (n *NUMATopology) getPodResourceRequests(pod *v1.Pod) (int64, int64) {
-	var cpu int64
-	var memory int64
+	req := podNUMARequest(pod)
+	return req.cpus(), req.memory
+}
 
-	for _, container := range pod.Spec.Containers {
-		if q := container.Resources.Requests.Cpu(); q != nil {
-			cpu += q.MilliValue()
-		}
-		if q := container.Resources.Requests.Memory(); q != nil {
-			memory += q.Value()
+// New initializes a new NUMATopology plugin and returns it.
+func New(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	var nrt *nrtLister
+	if handle.ClientSet() != nil && handle.KubeConfig() != nil {
+		dynamicClient, err := dynamic.NewForConfig(handle.KubeConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 		}
+		nrt = newNRTLister(ctx, handle.ClientSet().Discovery(), dynamicClient)
 	}
 
-	// Convert millicores to cores
-	cpuCores := (cpu + 999) / 1000 // Round up
+	n := &NUMATopology{
+		handle:      handle,
+		nrt:         nrt,
+		gangState:   make(map[string]*GangNUMAState),
+		assignments: make(map[string]podAssignment),
+		nrtVersions: make(map[string]string),
+	}
 
-	return cpuCores, memory
-}
+	// Rebuild and maintain gang NUMA state from bound pods
+	if informerFactory := handle.SharedInformerFactory(); informerFactory != nil {
+		if _, err := informerFactory.Core().V1().Pods().Informer().AddEventHandler(n.gangPodEventHandler()); err != nil {
+			return nil, fmt.Errorf("failed to register pod event handler: %w", err)
+		}
+	}
 
-// New initializes a new NUMATopology plugin and returns it.
-func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	klog.V(3).InfoS("NUMATopology plugin initialized with advanced features: gang scheduling, affinity/anti-affinity, memory bandwidth optimization")
-	return &NUMATopology{
-		handle:    handle,
-		gangState: make(map[string]*GangNUMAState),
-	}, nil
+	klog.V(3).InfoS("NUMATopology plugin initialized with advanced features: gang scheduling, affinity/anti-affinity, memory bandwidth optimization",
+		"nodeResourceTopology", nrt != nil)
+	return n, nil
 }
 
 // isMemoryIntensive checks if a pod is memory-intensive based on annotation or heuristics.
@@ -723,10 +769,10 @@ func (n *NUMATopology) calculateGangAffinityScore(pod *v1.Pod, numa NUMANode, no
 		return 50.0 // Neutral score, not a gang member
 	}
 
-	// Get gang state (with lock)
+	// Get gang state (with lock held while reading members)
 	n.mu.RLock()
+	defer n.mu.RUnlock()
 	gangState, exists := n.gangState[gangGroup]
-	n.mu.RUnlock()
 
 	if !exists || len(gangState.AssignedMembers) == 0 {
 		return 50.0 // First gang member, neutral score
@@ -755,16 +801,16 @@ func (n *NUMATopology) calculateGangAffinityScore(pod *v1.Pod, numa NUMANode, no
 		return 20.0
 
 	case GangNUMASpreadBalanced:
-		// Prefer balanced distribution across NUMAs
-		// Count gang members on this NUMA
-		countOnNUMA := 0
-		for _, assignedNUMA := range gangState.AssignedMembers {
-			if assignedNUMA == numa.ID {
-				countOnNUMA++
+		// Prefer balanced distribution across sockets
+		// Count gang members on this node's socket
+		countOnSocket := 0
+		for member, assignedNUMA := range gangState.AssignedMembers {
+			if sameSocket(gangState.MemberNodes[member], assignedNUMA, node, numa) {
+				countOnSocket++
 			}
 		}
 		// Lower count = higher score (balance)
-		score := 100.0 - (float64(countOnNUMA) * 20.0)
+		score := 100.0 - (float64(countOnSocket) * 20.0)
 		if score < 0 {
 			score = 0
 		}
@@ -772,8 +818,9 @@ func (n *NUMATopology) calculateGangAffinityScore(pod *v1.Pod, numa NUMANode, no
 
 	case GangNUMASpreadIsolated:
 		// Prefer NUMA with no gang members
-		for _, assignedNUMA := range gangState.AssignedMembers {
-			if assignedNUMA == numa.ID {
+		for member, assignedNUMA := range gangState.AssignedMembers {
+			memberNode := gangState.MemberNodes[member]
+			if assignedNUMA == numa.ID && (memberNode == "" || memberNode == node.Name) {
 				return 0.0 // Already has gang member, avoid
 			}
 		}
@@ -784,47 +831,6 @@ func (n *NUMATopology) calculateGangAffinityScore(pod *v1.Pod, numa NUMANode, no
 	}
 }
 
-// recordGangPlacement records the NUMA placement decision for a gang member.
-func (n *NUMATopology) recordGangPlacement(pod *v1.Pod, numaID int, node *v1.Node) {
-	gangGroup, exists := pod.Annotations[AnnotationGangGroup]
-	if !exists || gangGroup == "" {
-		return
-	}
-
-	// Lock for gang state access
-	n.mu.Lock()
-	defer n.mu.Unlock()
-
-	// Initialize gang state if needed
-	if n.gangState == nil {
-		n.gangState = make(map[string]*GangNUMAState)
-	}
-
-	gangState, exists := n.gangState[gangGroup]
-	if !exists {
-		gangState = &GangNUMAState{
-			GangGroup:       gangGroup,
-			AssignedMembers: make(map[string]int),
-		}
-
-		// Get spread policy
-		if policy, exists := pod.Annotations[AnnotationGangNUMASpread]; exists {
-			gangState.SpreadPolicy = policy
-		} else {
-			gangState.SpreadPolicy = GangNUMASpreadPacked
-		}
-
-		n.gangState[gangGroup] = gangState
-	}
-
-	// Record assignment
-	podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
-	gangState.AssignedMembers[podKey] = numaID
-
-	klog.V(4).InfoS("Recorded gang placement",
-		"podKey", podKey, "numaNode", numaID, "node", node.Name, "policy", gangState.SpreadPolicy, "totalMembers", len(gangState.AssignedMembers))
-}
-
 // categorizePressure converts a bandwidth utilization percentage into a bounded label
 func categorizePressure(utilization float64) string {
 	switch {
@@ -840,7 +846,7 @@ func categorizePressure(utilization float64) string {
 // getGPUNUMAMapping extracts GPU-to-NUMA node mapping from node labels
 // Returns map[gpuIndex]numaNodeID
 // Supports labels like: gpu.kubenexus.io/numa-node-0=0, gpu.kubenexus.io/numa-node-1=1
-func (n *NUMATopology) getGPUNUMAMapping(node *v1.Node, pod *v1.Pod) map[int]int {
+func getGPUNUMAMapping(node *v1.Node) map[int]int {
 	mapping := make(map[int]int)
 
 	if node.Labels == nil {
@@ -860,67 +866,18 @@ func (n *NUMATopology) getGPUNUMAMapping(node *v1.Node, pod *v1.Pod) map[int]int
 	return mapping
 }
 
-// canFitGPUsInNUMA checks if requested GPUs can be placed on the target NUMA node
-func (n *NUMATopology) canFitGPUsInNUMA(pod *v1.Pod, numa *NUMANode, gpuNUMAMapping map[int]int) bool {
-	// Get GPU request count
-	gpusRequested := 0
-	for _, container := range pod.Spec.Containers {
-		if gpu, ok := container.Resources.Requests[v1.ResourceName("nvidia.com/gpu")]; ok {
-			gpusRequested += int(gpu.Value())
-		}
-	}
-
-	if gpusRequested == 0 {
-		return true // No GPU request, allow
-	}
-
-	if len(gpuNUMAMapping) == 0 {
-		// No GPU-NUMA mapping info, allow (assume kubelet will handle)
-		return true
-	}
-
-	// Count how many requested GPUs can fit on target NUMA node
-	gpusOnNUMA := 0
-	for _, numaID := range gpuNUMAMapping {
-		if numaID == numa.ID {
-			gpusOnNUMA++
-		}
-	}
-
-	// Check if we have enough GPUs on this NUMA node
-	return gpusOnNUMA >= gpusRequested
-}
-
-// calculateGPUNUMABonus calculates bonus/penalty for GPU-NUMA alignment
-func (n *NUMATopology) calculateGPUNUMABonus(pod *v1.Pod, numa *NUMANode, gpuNUMAMapping map[int]int) int64 {
-	gpusRequested := 0
-	for _, container := range pod.Spec.Containers {
-		if gpu, ok := container.Resources.Requests[v1.ResourceName("nvidia.com/gpu")]; ok {
-			gpusRequested += int(gpu.Value())
-		}
-	}
-
-	if gpusRequested == 0 {
-		return 0 // No GPU request, no bonus
-	}
-
-	if len(gpuNUMAMapping) == 0 {
-		return 0 // No GPU-NUMA mapping info, no bonus
-	}
-
-	// Count GPUs on target NUMA node
-	gpusOnNUMA := 0
-	for _, numaID := range gpuNUMAMapping {
-		if numaID == numa.ID {
-			gpusOnNUMA++
-		}
+// calculateDeviceNUMABonus calculates bonus/penalty for GPU and NIC NUMA alignment
+func calculateDeviceNUMABonus(req numaRequest, numa *NUMANode, tracked map[v1.ResourceName]bool) int64 {
+	requested, aligned := devicesOnNUMANode(req, numa, tracked)
+	if !requested {
+		return 0 // No per-NUMA device request, no bonus
 	}
 
-	// If all requested GPUs are on same NUMA as CPUs, give bonus
-	if gpusOnNUMA >= gpusRequested {
+	// If all requested devices are on same NUMA as CPUs, give bonus
+	if aligned {
 		return BonusGPUNUMACoLocation
 	}
 
-	// If some GPUs are on different NUMA, apply penalty
+	// If some devices are on different NUMA, apply penalty
 	return -PenaltyGPUNUMAMismatch
 }
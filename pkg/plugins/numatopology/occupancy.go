@@ -0,0 +1,349 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+)
+
+const (
+	// AnnotationNUMANode is set by the scheduler: the NUMA node chosen for a
+	// single-numa-node pod or gang member, used to account for its CPU and memory per
+	// NUMA node and to rebuild gang NUMA state after a restart
+	AnnotationNUMANode = "scheduling.kubenexus.io/numa-node"
+
+	numaAssignmentStateKey = Name + "/numa-assignment"
+)
+
+var (
+	_ framework.ReservePlugin  = &NUMATopology{}
+	_ framework.PreBindPlugin  = &NUMATopology{}
+	_ framework.PostBindPlugin = &NUMATopology{}
+)
+
+// podAssignment is a pod placed by this scheduler, charged to its node until the
+// accounting source reflects it: the pod's NUMA node annotation, or with
+// NodeResourceTopology a refresh of the node's object after the bind
+type podAssignment struct {
+	nodeName string
+	numaID   int // -1 for pods in the shared pool
+	// nrtVersion is the node's NodeResourceTopology resourceVersion at bind, empty
+	// until the pod is bound
+	nrtVersion string
+}
+
+// numaAssignment is the NUMA node chosen for the pod at Reserve
+type numaAssignment struct {
+	numaID int
+}
+
+// Clone implements framework.StateData
+func (a *numaAssignment) Clone() framework.StateData {
+	return a
+}
+
+// getNUMANodes returns the node's NUMA nodes with their free capacity.
+//
+// NodeResourceTopology is preferred: its available resources come from the node and
+// only pods placed here that the object does not reflect yet are subtracted: pods
+// reserved or bound since its last refresh. Otherwise capacity comes
+// from labels, minus every pod on the node: pods with a recorded NUMA node are charged
+// to it, and pods running in the shared pool are split evenly across NUMA nodes.
+func (n *NUMATopology) getNUMANodes(nodeInfo framework.NodeInfo) ([]NUMANode, error) {
+	node := nodeInfo.Node()
+	if numaNodes, version, ok := n.nrt.numaNodes(node.Name); ok {
+		applyNUMAAttributes(node, numaNodes)
+		n.forgetSeenByNRT(node.Name, version)
+		n.subtractPods(numaNodes, nodeInfo, true)
+		return numaNodes, nil
+	}
+
+	numaNodes, err := n.parseNUMATopology(node)
+	if err != nil {
+		return nil, err
+	}
+	n.subtractPods(numaNodes, nodeInfo, false)
+	return numaNodes, nil
+}
+
+// subtractPods charges pods on the node to its NUMA nodes. With pendingOnly, only
+// pods holding an in-memory assignment are charged.
+//
+// A pod with a recorded NUMA node is charged there for what the kubelet pins: exclusive
+// CPUs, memory and hugepages of guaranteed pods, and devices. The rest runs in the shared
+// pool and is split evenly; unrecorded devices go to the NUMA node with the most free.
+func (n *NUMATopology) subtractPods(numaNodes []NUMANode, nodeInfo framework.NodeInfo, pendingOnly bool) {
+	if len(numaNodes) == 0 {
+		return
+	}
+	byID := make(map[int]int, len(numaNodes))
+	for i := range numaNodes {
+		byID[numaNodes[i].ID] = i
+	}
+	shares := int64(len(numaNodes))
+	tracked := trackedResources(numaNodes)
+	for i := range numaNodes {
+		if numaNodes[i].AvailableResources == nil {
+			numaNodes[i].AvailableResources = make(map[v1.ResourceName]int64)
+		}
+	}
+
+	usedMilliCPU := make([]int64, len(numaNodes))
+	for _, podInfo := range nodeInfo.GetPods() {
+		pod := podInfo.GetPod()
+		numaID, pending := n.pendingNUMANode(pod)
+		if pendingOnly && !pending {
+			continue
+		}
+		if !pending {
+			numaID = recordedNUMANode(pod)
+		}
+		zone, recorded := byID[numaID]
+		req := podNUMARequest(pod)
+
+		if recorded && req.exclusiveCPUs {
+			usedMilliCPU[zone] += req.milliCPU
+		} else {
+			for i := range numaNodes {
+				usedMilliCPU[i] += req.milliCPU / shares
+			}
+		}
+
+		if recorded && req.guaranteed {
+			numaNodes[zone].AvailableMemory -= req.memory
+		} else {
+			for i := range numaNodes {
+				numaNodes[i].AvailableMemory -= req.memory / shares
+			}
+		}
+
+		for name, value := range req.resources {
+			if !tracked[name] {
+				continue
+			}
+			switch {
+			case recorded && (req.guaranteed || !isHugePages(name)):
+				numaNodes[zone].AvailableResources[name] -= value
+			case isHugePages(name):
+				for i := range numaNodes {
+					numaNodes[i].AvailableResources[name] -= value / shares
+				}
+			default:
+				chargeDevices(numaNodes, name, value)
+			}
+		}
+	}
+
+	for i := range numaNodes {
+		numa := &numaNodes[i]
+		numa.AvailableCPUs -= int((usedMilliCPU[i] + 999) / 1000)
+		if numa.AvailableCPUs < 0 {
+			numa.AvailableCPUs = 0
+		}
+		if numa.AvailableMemory < 0 {
+			numa.AvailableMemory = 0
+		}
+		for name, value := range numa.AvailableResources {
+			if value < 0 {
+				numa.AvailableResources[name] = 0
+			}
+		}
+	}
+}
+
+// chargeDevices takes devices of a pod without a recorded NUMA node one at a time from
+// the NUMA node with the most free, as the kubelet device manager would prefer
+func chargeDevices(numaNodes []NUMANode, name v1.ResourceName, count int64) {
+	for ; count > 0; count-- {
+		best := -1
+		for i := range numaNodes {
+			if numaNodes[i].AvailableResources[name] <= 0 {
+				continue
+			}
+			if best < 0 || numaNodes[i].AvailableResources[name] > numaNodes[best].AvailableResources[name] {
+				best = i
+			}
+		}
+		if best < 0 {
+			return
+		}
+		numaNodes[best].AvailableResources[name]--
+	}
+}
+
+// pendingNUMANode returns the NUMA node assigned to the pod at Reserve while the pod
+// is still tracked in memory, -1 for a tracked pod in the shared pool
+func (n *NUMATopology) pendingNUMANode(pod *v1.Pod) (int, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	assignment, ok := n.assignments[podKey(pod)]
+	return assignment.numaID, ok
+}
+
+// forgetSeenByNRT drops pods bound to the node before its NodeResourceTopology object
+// last changed: the exporter has refreshed since, so available already reflects them
+func (n *NUMATopology) forgetSeenByNRT(nodeName, version string) {
+	n.mu.RLock()
+	unchanged := n.nrtVersions[nodeName] == version
+	n.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for key, assignment := range n.assignments {
+		if assignment.nodeName == nodeName && assignment.nrtVersion != "" && assignment.nrtVersion != version {
+			delete(n.assignments, key)
+		}
+	}
+	n.nrtVersions[nodeName] = version
+}
+
+// recordedNUMANode returns the NUMA node recorded on a bound pod, or -1
+func recordedNUMANode(pod *v1.Pod) int {
+	value, ok := pod.Annotations[AnnotationNUMANode]
+	if !ok {
+		return -1
+	}
+	numaID, err := strconv.Atoi(value)
+	if err != nil {
+		klog.V(5).InfoS("Ignoring invalid NUMA node annotation", "pod", klog.KObj(pod), "value", value)
+		return -1
+	}
+	return numaID
+}
+
+// Reserve picks the NUMA node for a single-numa-node pod or gang member on the chosen
+// node, holds its capacity until the node's accounting reflects the pod, and records
+// gang members' placement. Other pods are held in the shared pool, since a node's
+// NodeResourceTopology object does not count them until its next refresh either.
+func (n *NUMATopology) Reserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	policy := n.getNUMAPolicy(pod)
+	gangMember := pod.Annotations[AnnotationGangGroup] != ""
+	if policy == NUMAPolicyNone || (policy != NUMAPolicySingleNode && !gangMember) {
+		n.assign(pod, nodeName, -1)
+		return nil
+	}
+
+	nodeInfo, err := n.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("getting node %q from snapshot: %w", nodeName, err))
+	}
+	numaNodes, err := n.getNUMANodes(nodeInfo)
+	if err != nil || len(numaNodes) <= 1 {
+		n.assign(pod, nodeName, -1)
+		return nil
+	}
+
+	choice := n.evaluateNUMANodes(pod, nodeInfo.Node(), numaNodes, n.blockedNUMANodes(pod, nodeInfo))
+	if choice.numaID < 0 {
+		klog.V(4).InfoS("Reserve: no NUMA node recorded", "pod", klog.KObj(pod), "node", nodeName)
+		n.assign(pod, nodeName, -1)
+		return nil
+	}
+
+	n.assign(pod, nodeName, choice.numaID)
+	state.Write(numaAssignmentStateKey, &numaAssignment{numaID: choice.numaID})
+	n.recordGangPlacement(pod, choice.numaID, nodeName)
+
+	klog.V(4).InfoS("Reserve: assigned NUMA node", "pod", klog.KObj(pod), "node", nodeName, "numaNode", choice.numaID)
+	return nil
+}
+
+// Unreserve releases the NUMA node held for the pod and its gang placement
+func (n *NUMATopology) Unreserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	n.releaseAssignment(pod)
+	n.removeGangMember(pod)
+}
+
+// PreBindPreFlight skips PreBind for pods without a NUMA assignment
+func (n *NUMATopology) PreBindPreFlight(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if _, err := state.Read(numaAssignmentStateKey); err != nil {
+		return framework.NewStatus(framework.Skip)
+	}
+	return nil
+}
+
+// PreBind records the NUMA node on the pod for later accounting. The kubelet topology
+// manager does the actual pinning, so a failed patch is only logged.
+func (n *NUMATopology) PreBind(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	data, err := state.Read(numaAssignmentStateKey)
+	if err != nil {
+		return nil
+	}
+	assignment, ok := data.(*numaAssignment)
+	if !ok {
+		return framework.AsStatus(fmt.Errorf("%+v convert to *numaAssignment error", data))
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationNUMANode: strconv.Itoa(assignment.numaID)},
+		},
+	})
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	if _, err := n.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.V(2).InfoS("PreBind: failed to record NUMA node", "pod", klog.KObj(pod), "node", nodeName, "error", err)
+	}
+	return nil
+}
+
+// PostBind drops the in-memory assignment once the pod carries the annotation. On a
+// node with NodeResourceTopology the assignment is kept, stamped with the object's
+// current resourceVersion, until the exporter refreshes the object.
+func (n *NUMATopology) PostBind(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	_, version, ok := n.nrt.numaNodes(nodeName)
+	if !ok {
+		n.releaseAssignment(pod)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if assignment, ok := n.assignments[podKey(pod)]; ok {
+		assignment.nrtVersion = version
+		n.assignments[podKey(pod)] = assignment
+	}
+}
+
+func (n *NUMATopology) assign(pod *v1.Pod, nodeName string, numaID int) {
+	n.mu.Lock()
+	n.assignments[podKey(pod)] = podAssignment{nodeName: nodeName, numaID: numaID}
+	n.mu.Unlock()
+}
+
+func (n *NUMATopology) releaseAssignment(pod *v1.Pod) {
+	n.mu.Lock()
+	delete(n.assignments, podKey(pod))
+	n.mu.Unlock()
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// numaNode builds a node with two NUMA nodes of 16 CPUs and 64Gi each
+func numaNode(name string) *v1.Node {
+	return testutil.MakeNode(name, map[string]string{
+		LabelNUMANodeCount:                "2",
+		"numa.kubenexus.io/node-0-cpus":   "0-15",
+		"numa.kubenexus.io/node-0-memory": "68719476736",
+		"numa.kubenexus.io/node-1-cpus":   "16-31",
+		"numa.kubenexus.io/node-1-memory": "68719476736",
+	}, nil)
+}
+
+// numaPod builds a guaranteed single-numa-node pod, recorded on numaID when it is set
+func numaPod(name, nodeName, cpu, memory, numaID string) *v1.Pod {
+	annotations := map[string]string{AnnotationNUMAPolicy: NUMAPolicySingleNode}
+	if numaID != "" {
+		annotations[AnnotationNUMANode] = numaID
+	}
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+	pod := testutil.MakePod(name, "default", nodeName, requests, nil, annotations)
+	pod.Spec.Containers[0].Resources.Limits = requests
+	return pod
+}
+
+func newTestPlugin(t *testing.T, pods []*v1.Pod, nodes []*v1.Node) *NUMATopology {
+	t.Helper()
+	fh, err := testutil.NewTestFrameworkWithPods(pods, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	return p.(*NUMATopology)
+}
+
+func TestParseNRTZones(t *testing.T) {
+	nrt := &unstructured.Unstructured{Object: map[string]interface{}{
+		"zones": []interface{}{
+			map[string]interface{}{
+				"name": "node-1",
+				"type": "Node",
+				"resources": []interface{}{
+					map[string]interface{}{"name": "cpu", "capacity": "32", "allocatable": "30", "available": "12"},
+					map[string]interface{}{"name": "memory", "capacity": "128Gi", "allocatable": "120Gi", "available": "40Gi"},
+				},
+				"costs": []interface{}{
+					map[string]interface{}{"name": "node-0", "value": int64(21)},
+					map[string]interface{}{"name": "node-1", "value": int64(10)},
+				},
+				"attributes": []interface{}{
+					map[string]interface{}{"name": NRTAttributeMemoryBandwidth, "value": "204800"},
+				},
+			},
+			map[string]interface{}{
+				"name": "node-0",
+				"type": "Node",
+				"resources": []interface{}{
+					map[string]interface{}{"name": "cpu", "allocatable": int64(30), "available": int64(30)},
+				},
+			},
+			map[string]interface{}{"name": "socket-0", "type": "Socket"},
+		},
+	}}
+
+	numaNodes, err := parseNRTZones(nrt)
+	if err != nil {
+		t.Fatalf("parseNRTZones failed: %v", err)
+	}
+	if len(numaNodes) != 2 {
+		t.Fatalf("got %d NUMA nodes, want 2", len(numaNodes))
+	}
+	if numaNodes[0].ID != 0 || numaNodes[1].ID != 1 {
+		t.Errorf("NUMA nodes not sorted by ID: %d, %d", numaNodes[0].ID, numaNodes[1].ID)
+	}
+	got := numaNodes[1]
+	if got.TotalCPUs != 30 || got.AvailableCPUs != 12 {
+		t.Errorf("node-1 CPUs = %d/%d, want 12/30", got.AvailableCPUs, got.TotalCPUs)
+	}
+	if got.TotalMemory != 120*gib || got.AvailableMemory != 40*gib {
+		t.Errorf("node-1 memory = %d/%d, want %d/%d", got.AvailableMemory, got.TotalMemory, int64(40*gib), int64(120*gib))
+	}
+	if got.Distance[0] != 21 || got.Distance[1] != 10 {
+		t.Errorf("node-1 distances = %v, want map[0:21 1:10]", got.Distance)
+	}
+	if got.MemoryBandwidth != 204800 {
+		t.Errorf("node-1 memory bandwidth = %d, want 204800", got.MemoryBandwidth)
+	}
+}
+
+func TestGetNUMANodesSubtractsPods(t *testing.T) {
+	node := numaNode("node-1")
+	pods := []*v1.Pod{
+		numaPod("pinned", node.Name, "10", "40Gi", "0"),
+		numaPod("shared", node.Name, "4", "8Gi", ""),
+	}
+	plugin := newTestPlugin(t, pods, []*v1.Node{node})
+
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetNode(node)
+	numaNodes, err := plugin.getNUMANodes(nodeInfo)
+	if err != nil {
+		t.Fatalf("getNUMANodes failed: %v", err)
+	}
+
+	// The pinned pod uses NUMA 0; the shared pod is split across both
+	if got := numaNodes[0].AvailableCPUs; got != 4 {
+		t.Errorf("NUMA 0 available CPUs = %d, want 4", got)
+	}
+	if got := numaNodes[1].AvailableCPUs; got != 14 {
+		t.Errorf("NUMA 1 available CPUs = %d, want 14", got)
+	}
+	if got := numaNodes[0].AvailableMemory; got != 20*gib {
+		t.Errorf("NUMA 0 available memory = %d, want %d", got, int64(20*gib))
+	}
+
+	pod := numaPod("new", "", "8", "16Gi", "")
+	state := framework.NewCycleState()
+	if status := plugin.Filter(context.Background(), state, pod, nodeInfo); !status.IsSuccess() {
+		t.Errorf("Filter rejected pod that fits NUMA 1: %v", status.Message())
+	}
+	big := numaPod("big", "", "15", "16Gi", "")
+	if status := plugin.Filter(context.Background(), state, big, nodeInfo); status.IsSuccess() {
+		t.Error("Filter admitted pod larger than the free capacity of any NUMA node")
+	}
+}
+
+func TestReserveRecordsNUMANode(t *testing.T) {
+	node := numaNode("node-1")
+	existing := numaPod("existing", node.Name, "12", "8Gi", "1")
+	pod := numaPod("new", "", "8", "16Gi", "")
+	plugin := newTestPlugin(t, []*v1.Pod{existing, pod}, []*v1.Node{node})
+
+	state := framework.NewCycleState()
+	if status := plugin.Reserve(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("Reserve failed: %v", status.Message())
+	}
+	if numaID, ok := plugin.pendingNUMANode(pod); !ok || numaID != 0 {
+		t.Fatalf("Reserve assigned NUMA %d (recorded %v), want 0", numaID, ok)
+	}
+
+	if status := plugin.PreBindPreFlight(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("PreBindPreFlight = %v, want Success", status.Code())
+	}
+	if status := plugin.PreBind(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("PreBind failed: %v", status.Message())
+	}
+	updated, err := plugin.handle.ClientSet().CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	if got := updated.Annotations[AnnotationNUMANode]; got != "0" {
+		t.Errorf("%s = %q, want %q", AnnotationNUMANode, got, "0")
+	}
+
+	plugin.PostBind(context.Background(), state, pod, node.Name)
+	if _, ok := plugin.pendingNUMANode(pod); ok {
+		t.Error("assignment not released after PostBind")
+	}
+}
+
+// nrtObject builds a NodeResourceTopology with two NUMA zones of 16 CPUs and 64Gi,
+// with the given CPUs available on each
+func nrtObject(nodeName, resourceVersion string, available0, available1 int64) *unstructured.Unstructured {
+	zone := func(name string, available int64) interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"type": "Node",
+			"resources": []interface{}{
+				map[string]interface{}{"name": "cpu", "allocatable": int64(16), "available": available},
+				map[string]interface{}{"name": "memory", "allocatable": "64Gi", "available": "64Gi"},
+			},
+		}
+	}
+	nrt := &unstructured.Unstructured{Object: map[string]interface{}{
+		"zones": []interface{}{zone("node-0", available0), zone("node-1", available1)},
+	}}
+	nrt.SetName(nodeName)
+	nrt.SetResourceVersion(resourceVersion)
+	return nrt
+}
+
+func TestNRTChargesBoundPodsUntilRefresh(t *testing.T) {
+	node := numaNode("node-1")
+	pinned := numaPod("pinned", "", "12", "8Gi", "")
+	shared := testutil.MakePod("shared", "default", "", v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}, nil, nil)
+	plugin := newTestPlugin(t, []*v1.Pod{pinned, shared}, []*v1.Node{node})
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(nrtObject(node.Name, "1", 16, 16)); err != nil {
+		t.Fatalf("Failed to add NRT: %v", err)
+	}
+	plugin.nrt = &nrtLister{lister: cache.NewGenericLister(indexer, nrtGVR.GroupResource()), hasSynced: func() bool { return true }}
+
+	for _, pod := range []*v1.Pod{pinned, shared} {
+		state := framework.NewCycleState()
+		if status := plugin.Reserve(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+			t.Fatalf("Reserve %s failed: %v", pod.Name, status.Message())
+		}
+		plugin.PostBind(context.Background(), state, pod, node.Name)
+		pod.Spec.NodeName = node.Name
+	}
+	nodeInfo := framework.NewNodeInfo(pinned, shared)
+	nodeInfo.SetNode(node)
+
+	// NRT has not refreshed since the binds: both pods must still be charged
+	numaNodes, err := plugin.getNUMANodes(nodeInfo)
+	if err != nil {
+		t.Fatalf("getNUMANodes failed: %v", err)
+	}
+	if got := numaNodes[0].AvailableCPUs; got != 2 {
+		t.Errorf("NUMA 0 available CPUs with stale NRT = %d, want 2", got)
+	}
+	if got := numaNodes[1].AvailableCPUs; got != 14 {
+		t.Errorf("NUMA 1 available CPUs with stale NRT = %d, want 14", got)
+	}
+	big := numaPod("big", "", "15", "8Gi", "")
+	if status := plugin.Filter(context.Background(), framework.NewCycleState(), big, nodeInfo); status.IsSuccess() {
+		t.Error("Filter admitted pod that only fits capacity NRT has not yet seen used")
+	}
+
+	// Once the exporter refreshes, available reflects the pods and they are not charged twice
+	if err := indexer.Update(nrtObject(node.Name, "2", 2, 14)); err != nil {
+		t.Fatalf("Failed to update NRT: %v", err)
+	}
+	numaNodes, err = plugin.getNUMANodes(nodeInfo)
+	if err != nil {
+		t.Fatalf("getNUMANodes failed: %v", err)
+	}
+	if numaNodes[0].AvailableCPUs != 2 || numaNodes[1].AvailableCPUs != 14 {
+		t.Errorf("available CPUs after NRT refresh = %d/%d, want 2/14", numaNodes[0].AvailableCPUs, numaNodes[1].AvailableCPUs)
+	}
+	if _, ok := plugin.pendingNUMANode(pinned); ok {
+		t.Error("assignment kept after NRT refreshed")
+	}
+}
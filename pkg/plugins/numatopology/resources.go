@@ -0,0 +1,209 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/component-helpers/resource"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+)
+
+const (
+	// AnnotationNUMADevicesFormat is the node annotation listing device plugin resources
+	// attached to a NUMA node, e.g. numa.kubenexus.io/node-0-devices:
+	// "intel.com/sriov_netdevice=8,nvidia.com/gpu=4"
+	AnnotationNUMADevicesFormat = "numa.kubenexus.io/node-%d-devices"
+
+	// LabelNUMAHugepagesFormat is the node label with a NUMA node's hugepages in bytes,
+	// e.g. numa.kubenexus.io/node-0-hugepages-1Gi: "17179869184"
+	LabelNUMAHugepagesFormat = "numa.kubenexus.io/node-%d-%s"
+
+	// ResourceNVIDIAGPU is the device plugin resource gpu.kubenexus.io/numa-node-N labels describe
+	ResourceNVIDIAGPU = v1.ResourceName("nvidia.com/gpu")
+)
+
+// hugepageResources are the hugepage sizes tracked per NUMA node from labels
+var hugepageResources = []v1.ResourceName{
+	v1.ResourceName(v1.ResourceHugePagesPrefix + "2Mi"),
+	v1.ResourceName(v1.ResourceHugePagesPrefix + "1Gi"),
+}
+
+// numaRequest is what a pod needs from the NUMA node it is aligned to: the effective
+// pod request (max of init containers and the sum of containers and sidecars, plus pod
+// overhead), as the kubelet admits it
+type numaRequest struct {
+	milliCPU int64
+	memory   int64
+	// resources holds hugepages and device plugin resources
+	resources map[v1.ResourceName]int64
+	// guaranteed pods get their memory and hugepages pinned by the kubelet memory manager
+	guaranteed bool
+	// exclusiveCPUs is set for guaranteed pods with integer CPU requests, which the
+	// kubelet static CPU manager pins to whole CPUs
+	exclusiveCPUs bool
+}
+
+// cpus returns the whole CPUs the pod occupies on a NUMA node
+func (r numaRequest) cpus() int64 {
+	return (r.milliCPU + 999) / 1000
+}
+
+// podNUMARequest computes the pod's effective request
+func podNUMARequest(pod *v1.Pod) numaRequest {
+	integerCPUs := true
+	requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{
+		ContainerFn: func(res v1.ResourceList, _ resourcehelper.ContainerType) {
+			if cpu, ok := res[v1.ResourceCPU]; ok && cpu.MilliValue()%1000 != 0 {
+				integerCPUs = false
+			}
+		},
+	})
+
+	req := numaRequest{resources: make(map[v1.ResourceName]int64)}
+	for name, quantity := range requests {
+		switch {
+		case name == v1.ResourceCPU:
+			req.milliCPU = quantity.MilliValue()
+		case name == v1.ResourceMemory:
+			req.memory = quantity.Value()
+		case name == v1.ResourceEphemeralStorage || name == v1.ResourcePods:
+		default:
+			if value := quantity.Value(); value > 0 {
+				req.resources[name] = value
+			}
+		}
+	}
+	req.guaranteed = qos.GetPodQOS(pod) == v1.PodQOSGuaranteed
+	req.exclusiveCPUs = req.guaranteed && integerCPUs && req.milliCPU > 0
+	return req
+}
+
+// fitsNUMANode reports whether the request fits the NUMA node's free CPU, memory and
+// hugepages. Hugepage sizes the node does not track per NUMA node are not constrained.
+func fitsNUMANode(req numaRequest, numa *NUMANode, tracked map[v1.ResourceName]bool) bool {
+	if int64(numa.AvailableCPUs) < req.cpus() || numa.AvailableMemory < req.memory {
+		return false
+	}
+	for name, value := range req.resources {
+		if isHugePages(name) && tracked[name] && numa.AvailableResources[name] < value {
+			return false
+		}
+	}
+	return true
+}
+
+// devicesOnNUMANode reports whether the pod requests devices the node tracks per NUMA
+// node, and if so whether all of them are available on this NUMA node
+func devicesOnNUMANode(req numaRequest, numa *NUMANode, tracked map[v1.ResourceName]bool) (requested, aligned bool) {
+	aligned = true
+	for name, value := range req.resources {
+		if !tracked[name] || isHugePages(name) {
+			continue
+		}
+		requested = true
+		if numa.AvailableResources[name] < value {
+			aligned = false
+		}
+	}
+	return requested, aligned
+}
+
+// trackedResources returns the hugepages and devices the node reports per NUMA node
+func trackedResources(numaNodes []NUMANode) map[v1.ResourceName]bool {
+	tracked := make(map[v1.ResourceName]bool)
+	for _, numa := range numaNodes {
+		for name := range numa.Resources {
+			tracked[name] = true
+		}
+	}
+	return tracked
+}
+
+// applyNUMAResources reads hugepages and devices per NUMA node from node labels and
+// annotations:
+//
+//	numa.kubenexus.io/node-0-hugepages-1Gi: "17179869184"     # label, bytes
+//	gpu.kubenexus.io/numa-node-3: "1"                         # label, GPU 3 on NUMA 1
+//	numa.kubenexus.io/node-0-devices: "intel.com/sriov_netdevice=8"  # annotation
+func applyNUMAResources(node *v1.Node, numaNodes []NUMANode) {
+	gpusPerNUMA := make(map[int]int64)
+	for _, numaID := range getGPUNUMAMapping(node) {
+		gpusPerNUMA[numaID]++
+	}
+
+	for i := range numaNodes {
+		numa := &numaNodes[i]
+		resources := make(map[v1.ResourceName]int64)
+
+		for _, name := range hugepageResources {
+			label := fmt.Sprintf(LabelNUMAHugepagesFormat, numa.ID, name)
+			if value, ok := node.Labels[label]; ok {
+				if bytes, err := strconv.ParseInt(value, 10, 64); err == nil && bytes > 0 {
+					resources[name] = bytes
+				}
+			}
+		}
+		if gpus := gpusPerNUMA[numa.ID]; gpus > 0 {
+			resources[ResourceNVIDIAGPU] = gpus
+		}
+		if value, ok := node.Annotations[fmt.Sprintf(AnnotationNUMADevicesFormat, numa.ID)]; ok {
+			devices, err := parseDeviceList(value)
+			if err != nil {
+				klog.V(4).InfoS("Ignoring invalid NUMA devices annotation", "node", node.Name, "numaNode", numa.ID, "error", err)
+			}
+			for name, count := range devices {
+				resources[name] = count
+			}
+		}
+
+		if len(resources) == 0 {
+			continue
+		}
+		numa.Resources = resources
+		numa.AvailableResources = make(map[v1.ResourceName]int64, len(resources))
+		for name, value := range resources {
+			numa.AvailableResources[name] = value
+		}
+	}
+}
+
+// parseDeviceList parses "intel.com/sriov_netdevice=8,nvidia.com/gpu=4"
+func parseDeviceList(value string) (map[v1.ResourceName]int64, error) {
+	devices := make(map[v1.ResourceName]int64)
+	for _, entry := range strings.Split(value, ",") {
+		name, count, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return devices, fmt.Errorf("entry %q must be <resource>=<count>", entry)
+		}
+		q, err := resource.ParseQuantity(count)
+		if err != nil {
+			return devices, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		devices[v1.ResourceName(name)] = q.Value()
+	}
+	return devices, nil
+}
+
+func isHugePages(name v1.ResourceName) bool {
+	return strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numatopology
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const resourceSRIOV = v1.ResourceName("intel.com/sriov_netdevice")
+
+func TestPodNUMARequest(t *testing.T) {
+	hugepages1Gi := v1.ResourceName(v1.ResourceHugePagesPrefix + "1Gi")
+
+	t.Run("init containers and overhead", func(t *testing.T) {
+		pod := numaPod("p", "", "2", "4Gi", "")
+		pod.Spec.InitContainers = []v1.Container{{
+			Name: "init",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}}
+		pod.Spec.Overhead = v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("250m"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+
+		req := podNUMARequest(pod)
+		if req.milliCPU != 6250 {
+			t.Errorf("milliCPU = %d, want 6250 (init container plus overhead)", req.milliCPU)
+		}
+		if req.memory != 5*gib {
+			t.Errorf("memory = %d, want %d", req.memory, int64(5*gib))
+		}
+	})
+
+	t.Run("guaranteed with hugepages and devices", func(t *testing.T) {
+		pod := numaPod("p", "", "4", "8Gi", "")
+		resources := &pod.Spec.Containers[0].Resources
+		resources.Requests = resources.Requests.DeepCopy()
+		resources.Requests[hugepages1Gi] = resource.MustParse("4Gi")
+		resources.Requests[resourceSRIOV] = resource.MustParse("2")
+		resources.Limits = resources.Requests
+
+		req := podNUMARequest(pod)
+		if !req.guaranteed || !req.exclusiveCPUs {
+			t.Errorf("guaranteed = %v, exclusiveCPUs = %v, want both true", req.guaranteed, req.exclusiveCPUs)
+		}
+		if req.resources[hugepages1Gi] != 4*gib || req.resources[resourceSRIOV] != 2 {
+			t.Errorf("resources = %v, want 4Gi hugepages and 2 VFs", req.resources)
+		}
+	})
+
+	t.Run("fractional CPUs are shared", func(t *testing.T) {
+		req := podNUMARequest(numaPod("p", "", "1500m", "1Gi", ""))
+		if !req.guaranteed || req.exclusiveCPUs {
+			t.Errorf("guaranteed = %v, exclusiveCPUs = %v, want true, false", req.guaranteed, req.exclusiveCPUs)
+		}
+		if req.cpus() != 2 {
+			t.Errorf("cpus() = %d, want 2", req.cpus())
+		}
+	})
+}
+
+func TestFilterAlignsDevicesAndHugepages(t *testing.T) {
+	node := numaNode("node-1")
+	// GPUs 0-1 on NUMA 0, GPUs 2-3 on NUMA 1; all VFs on NUMA 1; 1Gi hugepages only on NUMA 0
+	node.Labels["gpu.kubenexus.io/numa-node-0"] = "0"
+	node.Labels["gpu.kubenexus.io/numa-node-1"] = "0"
+	node.Labels["gpu.kubenexus.io/numa-node-2"] = "1"
+	node.Labels["gpu.kubenexus.io/numa-node-3"] = "1"
+	node.Labels["numa.kubenexus.io/node-0-hugepages-1Gi"] = "8589934592"
+	node.Annotations = map[string]string{"numa.kubenexus.io/node-1-devices": "intel.com/sriov_netdevice=4"}
+	plugin := newTestPlugin(t, nil, []*v1.Node{node})
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+
+	tests := []struct {
+		name     string
+		extra    v1.ResourceList
+		wantFits bool
+	}{
+		{
+			name:     "GPUs and VFs on NUMA 1",
+			extra:    v1.ResourceList{ResourceNVIDIAGPU: resource.MustParse("2"), resourceSRIOV: resource.MustParse("1")},
+			wantFits: true,
+		},
+		{
+			name:     "three GPUs span NUMA nodes",
+			extra:    v1.ResourceList{ResourceNVIDIAGPU: resource.MustParse("3")},
+			wantFits: false,
+		},
+		{
+			name:     "VFs on NUMA 1 but hugepages only on NUMA 0",
+			extra:    v1.ResourceList{resourceSRIOV: resource.MustParse("1"), v1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("2Gi")},
+			wantFits: false,
+		},
+		{
+			name:     "untracked device",
+			extra:    v1.ResourceList{"example.com/fpga": resource.MustParse("1")},
+			wantFits: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := numaPod("p", "", "4", "8Gi", "")
+			requests := pod.Spec.Containers[0].Resources.Requests.DeepCopy()
+			for name, q := range tt.extra {
+				requests[name] = q
+			}
+			pod.Spec.Containers[0].Resources.Requests = requests
+			pod.Spec.Containers[0].Resources.Limits = requests
+
+			status := plugin.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.IsSuccess() != tt.wantFits {
+				t.Errorf("Filter success = %v, want %v: %v", status.IsSuccess(), tt.wantFits, status.Message())
+			}
+		})
+	}
+}
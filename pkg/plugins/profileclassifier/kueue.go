@@ -0,0 +1,123 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// KueueQueueNameLabel is the pod label Kueue uses to select a LocalQueue
+	KueueQueueNameLabel = "kueue.x-k8s.io/queue-name"
+
+	kueueGroupVersion = "kueue.x-k8s.io/v1beta1"
+)
+
+var (
+	localQueueGVR   = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "localqueues"}
+	clusterQueueGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "clusterqueues"}
+)
+
+// kueueQueueInfo is what a pod's LocalQueue resolves to
+type kueueQueueInfo struct {
+	ClusterQueue string
+	Cohort       string
+	// Labels are the ClusterQueue's labels, which may carry tenant.kubenexus.io/* overrides
+	Labels map[string]string
+}
+
+// kueueResolver resolves LocalQueue → ClusterQueue → cohort from informer caches
+type kueueResolver struct {
+	localQueues   cache.GenericLister
+	clusterQueues cache.GenericLister
+	hasSynced     func() bool
+}
+
+// newKueueResolver starts LocalQueue and ClusterQueue informers. It returns nil when
+// Kueue is not installed, so clusters without Kueue don't get endless watch errors.
+func newKueueResolver(ctx context.Context, disc discovery.DiscoveryInterface, client dynamic.Interface) *kueueResolver {
+	if disc == nil || client == nil {
+		return nil
+	}
+	if _, err := disc.ServerResourcesForGroupVersion(kueueGroupVersion); err != nil {
+		klog.V(3).InfoS("Kueue API not available, queue-based tenant resolution disabled", "groupVersion", kueueGroupVersion, "error", err)
+		return nil
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	lq := factory.ForResource(localQueueGVR)
+	cq := factory.ForResource(clusterQueueGVR)
+	factory.Start(ctx.Done())
+
+	return &kueueResolver{
+		localQueues:   lq.Lister(),
+		clusterQueues: cq.Lister(),
+		hasSynced: func() bool {
+			return lq.Informer().HasSynced() && cq.Informer().HasSynced()
+		},
+	}
+}
+
+// resolve looks up the ClusterQueue and cohort behind a namespace's LocalQueue
+func (k *kueueResolver) resolve(namespace, queueName string) (*kueueQueueInfo, bool) {
+	if k == nil || !k.hasSynced() {
+		return nil, false
+	}
+
+	lqObj, err := k.localQueues.ByNamespace(namespace).Get(queueName)
+	if err != nil {
+		klog.V(5).InfoS("LocalQueue not found", "namespace", namespace, "queue", queueName, "error", err)
+		return nil, false
+	}
+	lq, ok := lqObj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false
+	}
+	clusterQueueName, _, _ := unstructured.NestedString(lq.Object, "spec", "clusterQueue")
+	if clusterQueueName == "" {
+		return nil, false
+	}
+
+	info := &kueueQueueInfo{ClusterQueue: clusterQueueName}
+	cqObj, err := k.clusterQueues.Get(clusterQueueName)
+	if err != nil {
+		klog.V(5).InfoS("ClusterQueue not found", "clusterQueue", clusterQueueName, "error", err)
+		return info, true
+	}
+	if cq, ok := cqObj.(*unstructured.Unstructured); ok {
+		info.Cohort, _, _ = unstructured.NestedString(cq.Object, "spec", "cohort")
+		info.Labels = cq.GetLabels()
+	}
+	return info, true
+}
+
+// tenant returns the most specific tenant identity for the queue: its cohort,
+// which groups the ClusterQueues of one organization, or else the ClusterQueue
+func (i *kueueQueueInfo) tenant() string {
+	if i.Cohort != "" {
+		return i.Cohort
+	}
+	return i.ClusterQueue
+}
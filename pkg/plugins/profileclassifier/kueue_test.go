@@ -0,0 +1,151 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func newTestKueueResolver(t *testing.T, objs ...*unstructured.Unstructured) *kueueResolver {
+	t.Helper()
+	lqIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	cqIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objs {
+		indexer := cqIndexer
+		if obj.GetKind() == "LocalQueue" {
+			indexer = lqIndexer
+		}
+		if err := indexer.Add(obj); err != nil {
+			t.Fatalf("Failed to add %s: %v", obj.GetName(), err)
+		}
+	}
+	return &kueueResolver{
+		localQueues:   cache.NewGenericLister(lqIndexer, localQueueGVR.GroupResource()),
+		clusterQueues: cache.NewGenericLister(cqIndexer, clusterQueueGVR.GroupResource()),
+		hasSynced:     func() bool { return true },
+	}
+}
+
+func localQueue(namespace, name, clusterQueue string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kueueGroupVersion,
+		"kind":       "LocalQueue",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"clusterQueue": clusterQueue},
+	}}
+	return obj
+}
+
+func clusterQueue(name, cohort string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kueueGroupVersion,
+		"kind":       "ClusterQueue",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       map[string]interface{}{},
+	}}
+	if cohort != "" {
+		obj.Object["spec"] = map[string]interface{}{"cohort": cohort}
+	}
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestGetTenantFromKueue(t *testing.T) {
+	resolver := newTestKueueResolver(t,
+		localQueue("team-a", "training", "cq-research"),
+		localQueue("team-b", "serving", "cq-prod"),
+		localQueue("team-c", "orphan", "cq-missing"),
+		clusterQueue("cq-research", "vision-org", nil),
+		clusterQueue("cq-prod", "", map[string]string{TenantTierLabel: "gold"}),
+	)
+
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{TenantTierLabel: "silver"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-c", Labels: map[string]string{TenantNameLabel: "staging-team"}}},
+	} {
+		if err := nsIndexer.Add(ns); err != nil {
+			t.Fatalf("Failed to add namespace: %v", err)
+		}
+	}
+
+	pl := &ProfileClassifier{
+		namespaceLister: corelisters.NewNamespaceLister(nsIndexer),
+		kueue:           resolver,
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		expectedTier TenantTier
+		expectedName string
+	}{
+		{
+			name:         "cohort is the tenant, namespace supplies tier",
+			pod:          st.MakePod().Namespace("team-a").Label(KueueQueueNameLabel, "training").Obj(),
+			expectedTier: TierSilver,
+			expectedName: "vision-org",
+		},
+		{
+			name:         "ClusterQueue tier label wins, ClusterQueue is tenant without cohort",
+			pod:          st.MakePod().Namespace("team-b").Label(KueueQueueNameLabel, "serving").Obj(),
+			expectedTier: TierGold,
+			expectedName: "cq-prod",
+		},
+		{
+			name:         "unresolved ClusterQueue falls back to namespace tenant name",
+			pod:          st.MakePod().Namespace("team-c").Label(KueueQueueNameLabel, "orphan").Obj(),
+			expectedTier: TierSilver,
+			expectedName: "staging-team",
+		},
+		{
+			name:         "no queue label",
+			pod:          st.MakePod().Namespace("team-a").Obj(),
+			expectedTier: TierUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, name := pl.getTenantFromKueue(tt.pod, pl.getNamespace(tt.pod.Namespace))
+			if tier != tt.expectedTier || name != tt.expectedName {
+				t.Errorf("Expected (%s, %q), got (%s, %q)", tt.expectedTier, tt.expectedName, tier, name)
+			}
+		})
+	}
+}
+
+func TestKueueResolverNotSynced(t *testing.T) {
+	resolver := newTestKueueResolver(t, localQueue("ns", "q", "cq"))
+	resolver.hasSynced = func() bool { return false }
+	if _, ok := resolver.resolve("ns", "q"); ok {
+		t.Error("Expected no resolution before informers sync")
+	}
+
+	var nilResolver *kueueResolver
+	if _, ok := nilResolver.resolve("ns", "q"); ok {
+		t.Error("Expected no resolution without Kueue")
+	}
+}
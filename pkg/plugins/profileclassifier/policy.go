@@ -0,0 +1,273 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PolicyConfigMapName is the ConfigMap holding the declarative classification policy
+	PolicyConfigMapName = "kubenexus-classification-policy"
+
+	// PolicyConfigMapKey is the ConfigMap data key containing the policy document
+	PolicyConfigMapKey = "policy.yaml"
+
+	// celCostLimit bounds the runtime cost of a single rule expression so a bad
+	// rule cannot stall the scheduling cycle
+	celCostLimit = 100000
+)
+
+// ClassificationPolicy is an ordered list of classification rules.
+//
+// Rules are evaluated top to bottom. Each profile field (tier, tenant, workload
+// type, preemptibility) is taken from the first matching rule that sets it, so a
+// broad rule near the bottom can supply defaults for more specific rules above it.
+// Fields no rule sets fall back to the built-in heuristics.
+type ClassificationPolicy struct {
+	Rules []ClassificationRule `json:"rules"`
+}
+
+// ClassificationRule matches pods with a CEL expression and assigns profile fields.
+//
+// The expression sees three variables:
+//   - pod: the Pod object (e.g. pod.metadata.labels, pod.spec.priorityClassName)
+//   - namespaceObject: the pod's Namespace object (e.g. namespaceObject.metadata.labels)
+//   - owners: the pod's owner references (e.g. owners.exists(o, o.kind == "PyTorchJob"))
+type ClassificationRule struct {
+	// Name identifies the rule in logs
+	Name string `json:"name"`
+
+	// Match is a CEL expression that must evaluate to a bool
+	Match string `json:"match"`
+
+	// Tier is the tenant tier assigned on match
+	Tier TenantTier `json:"tier,omitempty"`
+
+	// Tenant is a literal tenant name assigned on match
+	Tenant string `json:"tenant,omitempty"`
+
+	// TenantFrom is a CEL expression evaluating to the tenant name, used when Tenant is empty
+	TenantFrom string `json:"tenantFrom,omitempty"`
+
+	// WorkloadType is the workload type assigned on match
+	WorkloadType WorkloadType `json:"workloadType,omitempty"`
+
+	// Preemptible overrides preemptibility on match
+	Preemptible *bool `json:"preemptible,omitempty"`
+}
+
+// compiledRule is a ClassificationRule with its CEL programs ready to evaluate
+type compiledRule struct {
+	rule       ClassificationRule
+	match      cel.Program
+	tenantFrom cel.Program
+}
+
+// compiledPolicy is an immutable, ready-to-evaluate policy
+type compiledPolicy struct {
+	rules []compiledRule
+}
+
+// policyResult holds the fields resolved by the policy; empty values mean unset
+type policyResult struct {
+	tier         TenantTier
+	tenant       string
+	workloadType WorkloadType
+	preemptible  *bool
+}
+
+func newCELEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("pod", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.Variable("owners", cel.ListType(cel.DynType)),
+	)
+}
+
+// parsePolicy decodes a YAML or JSON policy document and compiles its rules.
+// Any invalid rule fails the whole policy so a typo never silently drops a rule.
+func parsePolicy(data []byte) (*compiledPolicy, error) {
+	policy := &ClassificationPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode classification policy: %w", err)
+	}
+	return compilePolicy(policy)
+}
+
+// compilePolicy validates and compiles every rule in the policy
+func compilePolicy(policy *ClassificationPolicy) (*compiledPolicy, error) {
+	env, err := newCELEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	compiled := &compiledPolicy{rules: make([]compiledRule, 0, len(policy.Rules))}
+	for i, rule := range policy.Rules {
+		if rule.Match == "" {
+			return nil, fmt.Errorf("rule %d (%q): match expression is required", i, rule.Name)
+		}
+		if rule.Tier != "" && parseTenantTier(string(rule.Tier)) == TierUnknown {
+			return nil, fmt.Errorf("rule %d (%q): unknown tier %q", i, rule.Name, rule.Tier)
+		}
+		if rule.WorkloadType != "" && parseWorkloadType(string(rule.WorkloadType)) == WorkloadUnknown {
+			return nil, fmt.Errorf("rule %d (%q): unknown workload type %q", i, rule.Name, rule.WorkloadType)
+		}
+
+		match, err := compileExpression(env, rule.Match, cel.BoolType)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%q): match: %w", i, rule.Name, err)
+		}
+		cr := compiledRule{rule: rule, match: match}
+
+		if rule.Tenant == "" && rule.TenantFrom != "" {
+			cr.tenantFrom, err = compileExpression(env, rule.TenantFrom, cel.StringType)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%q): tenantFrom: %w", i, rule.Name, err)
+			}
+		}
+		compiled.rules = append(compiled.rules, cr)
+	}
+
+	return compiled, nil
+}
+
+func compileExpression(env *cel.Env, expr string, want *cel.Type) (cel.Program, error) {
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if out := ast.OutputType(); !out.IsExactType(want) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must return %s, got %s", want, out)
+	}
+	return env.Program(ast, cel.CostLimit(celCostLimit))
+}
+
+// evaluate runs the rules against the pod and returns the fields they resolve
+func (p *compiledPolicy) evaluate(pod *v1.Pod, ns *v1.Namespace) policyResult {
+	result := policyResult{}
+	if p == nil || len(p.rules) == 0 {
+		return result
+	}
+
+	vars, err := policyVariables(pod, ns)
+	if err != nil {
+		klog.V(3).InfoS("Failed to build classification policy input", "pod", klog.KObj(pod), "error", err)
+		return result
+	}
+
+	for _, r := range p.rules {
+		out, _, err := r.match.Eval(vars)
+		if err != nil {
+			klog.V(4).InfoS("Classification rule evaluation failed", "rule", r.rule.Name, "pod", klog.KObj(pod), "error", err)
+			continue
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			continue
+		}
+
+		klog.V(5).InfoS("Classification rule matched", "rule", r.rule.Name, "pod", klog.KObj(pod))
+
+		if result.tier == "" && r.rule.Tier != "" {
+			result.tier = parseTenantTier(string(r.rule.Tier))
+		}
+		if result.tenant == "" {
+			result.tenant = r.tenantName(vars)
+		}
+		if result.workloadType == "" && r.rule.WorkloadType != "" {
+			result.workloadType = parseWorkloadType(string(r.rule.WorkloadType))
+		}
+		if result.preemptible == nil && r.rule.Preemptible != nil {
+			preemptible := *r.rule.Preemptible
+			result.preemptible = &preemptible
+		}
+	}
+
+	return result
+}
+
+// tenantName resolves the rule's tenant, preferring the literal over the expression
+func (r *compiledRule) tenantName(vars map[string]interface{}) string {
+	if r.rule.Tenant != "" {
+		return r.rule.Tenant
+	}
+	if r.tenantFrom == nil {
+		return ""
+	}
+	out, _, err := r.tenantFrom.Eval(vars)
+	if err != nil {
+		klog.V(4).InfoS("Classification rule tenantFrom evaluation failed", "rule", r.rule.Name, "error", err)
+		return ""
+	}
+	name, _ := out.Value().(string)
+	return name
+}
+
+// policyVariables converts the pod and namespace into the CEL activation
+func policyVariables(pod *v1.Pod, ns *v1.Namespace) (map[string]interface{}, error) {
+	podObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	nsObj := map[string]interface{}{}
+	if ns != nil {
+		nsObj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Guarantee labels and annotations exist so rules can index them without has() guards
+	ensureMetadataMaps(podObj, pod.Name)
+	ensureMetadataMaps(nsObj, pod.Namespace)
+
+	owners := make([]interface{}, 0, len(pod.OwnerReferences))
+	for _, ref := range pod.OwnerReferences {
+		owners = append(owners, map[string]interface{}{
+			"apiVersion": ref.APIVersion,
+			"kind":       ref.Kind,
+			"name":       ref.Name,
+		})
+	}
+
+	return map[string]interface{}{
+		"pod":             podObj,
+		"namespaceObject": nsObj,
+		"owners":          owners,
+	}, nil
+}
+
+// ensureMetadataMaps fills in metadata.name, metadata.labels and metadata.annotations
+// when the converter omitted them because they were empty
+func ensureMetadataMaps(obj map[string]interface{}, name string) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{"name": name}
+		obj["metadata"] = metadata
+	}
+	for _, key := range []string{"labels", "annotations"} {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = map[string]interface{}{}
+		}
+	}
+}
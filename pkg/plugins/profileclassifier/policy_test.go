@@ -0,0 +1,161 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+const testPolicy = `
+rules:
+- name: fine-tune-jobs
+  match: 'pod.metadata.labels["app"] == "finetune"'
+  workloadType: training
+  preemptible: true
+- name: research-namespaces
+  match: 'namespaceObject.metadata.labels["org"] == "research"'
+  tier: silver
+  tenantFrom: 'namespaceObject.metadata.labels["team"]'
+- name: pytorch-owners
+  match: 'owners.exists(o, o.kind == "PyTorchJob")'
+  tier: gold
+  tenant: ml-platform
+  workloadType: batch
+`
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		rules   int
+		wantErr bool
+	}{
+		{name: "valid policy", policy: testPolicy, rules: 3},
+		{name: "empty policy", policy: "", rules: 0},
+		{name: "missing match", policy: "rules:\n- name: bad\n  tier: gold\n", wantErr: true},
+		{name: "unknown tier", policy: "rules:\n- name: bad\n  match: 'true'\n  tier: platinum\n", wantErr: true},
+		{name: "unknown workload type", policy: "rules:\n- name: bad\n  match: 'true'\n  workloadType: mining\n", wantErr: true},
+		{name: "syntax error", policy: "rules:\n- name: bad\n  match: 'pod.metadata.labels[\"a\"] =='\n", wantErr: true},
+		{name: "non-bool match", policy: "rules:\n- name: bad\n  match: '\"gold\"'\n", wantErr: true},
+		{name: "unknown field", policy: "rules:\n- name: bad\n  match: 'true'\n  teir: gold\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := parsePolicy([]byte(tt.policy))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(policy.rules) != tt.rules {
+				t.Errorf("Expected %d rules, got %d", tt.rules, len(policy.rules))
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy, err := parsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("parsePolicy() error = %v", err)
+	}
+
+	researchNS := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "research",
+		Labels: map[string]string{"org": "research", "team": "vision"},
+	}}
+
+	pytorchPod := st.MakePod().Name("worker-0").Namespace("research").Obj()
+	pytorchPod.OwnerReferences = []metav1.OwnerReference{{Kind: "PyTorchJob", Name: "resnet"}}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		ns       *v1.Namespace
+		expected policyResult
+	}{
+		{
+			name:     "no rule matches",
+			pod:      st.MakePod().Name("web").Namespace("default").Obj(),
+			expected: policyResult{},
+		},
+		{
+			name:     "first rule sets workload type and preemptibility",
+			pod:      st.MakePod().Name("ft").Namespace("default").Label("app", "finetune").Obj(),
+			expected: policyResult{workloadType: WorkloadTraining, preemptible: boolPtr(true)},
+		},
+		{
+			name:     "earlier rule wins per field",
+			pod:      pytorchPod,
+			ns:       researchNS,
+			expected: policyResult{tier: TierSilver, tenant: "vision", workloadType: WorkloadBatch},
+		},
+		{
+			name:     "nil namespace does not fail evaluation",
+			pod:      pytorchPod,
+			expected: policyResult{tier: TierGold, tenant: "ml-platform", workloadType: WorkloadBatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.evaluate(tt.pod, tt.ns)
+			if got.tier != tt.expected.tier || got.tenant != tt.expected.tenant || got.workloadType != tt.expected.workloadType {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+			if (got.preemptible == nil) != (tt.expected.preemptible == nil) ||
+				(got.preemptible != nil && *got.preemptible != *tt.expected.preemptible) {
+				t.Errorf("Expected preemptible %v, got %v", tt.expected.preemptible, got.preemptible)
+			}
+		})
+	}
+}
+
+func TestPolicyHotReload(t *testing.T) {
+	handle, err := testutil.NewTestFramework(nil)
+	if err != nil {
+		t.Fatalf("Failed to create test framework: %v", err)
+	}
+	pl := &ProfileClassifier{handle: handle}
+	pod := st.MakePod().Name("ft").Namespace("default").Label("app", "finetune").Obj()
+
+	pl.onPolicyChange(&v1.ConfigMap{Data: map[string]string{PolicyConfigMapKey: testPolicy}})
+	if got := pl.classifyPod(pod); got.WorkloadType != WorkloadTraining || !got.IsPreemptible {
+		t.Errorf("Expected policy classification, got %+v", got)
+	}
+
+	// An invalid update keeps the previous policy
+	pl.onPolicyChange(&v1.ConfigMap{Data: map[string]string{PolicyConfigMapKey: "rules: [{name: bad}]"}})
+	if got := pl.classifyPod(pod); got.WorkloadType != WorkloadTraining {
+		t.Errorf("Expected previous policy to remain in effect, got %s", got.WorkloadType)
+	}
+
+	// Deleting the ConfigMap restores the built-in heuristics
+	pl.onPolicyChange(nil)
+	if got := pl.classifyPod(pod); got.IsPreemptible {
+		t.Errorf("Expected built-in preemptibility after policy removal, got %+v", got)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -20,13 +20,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/workload"
 )
 
@@ -34,6 +37,18 @@ const (
 	// Name is the name of the plugin used in the plugin registry and configurations.
 	Name = "ProfileClassifier"
 
+	// TenantTierLabel is the namespace (or ClusterQueue) label carrying the tenant tier
+	TenantTierLabel = "tenant.kubenexus.io/tier"
+
+	// TenantNameLabel is the namespace label carrying the tenant name
+	TenantNameLabel = "tenant.kubenexus.io/name"
+
+	// LeaderWorkerSetNameLabel is set by LeaderWorkerSet on every leader and worker pod
+	LeaderWorkerSetNameLabel = "leaderworkerset.sigs.k8s.io/name"
+
+	// LeaderWorkerSetGroupIndexLabel identifies the leader/worker group within a LeaderWorkerSet
+	LeaderWorkerSetGroupIndexLabel = "leaderworkerset.sigs.k8s.io/group-index"
+
 	// stateKey is the key in CycleState where SchedulingProfile is stored
 	stateKey = "ProfileClassifier"
 )
@@ -58,6 +73,15 @@ const (
 	WorkloadService     WorkloadType = "service"
 	WorkloadInteractive WorkloadType = "interactive"
 	WorkloadUnknown     WorkloadType = "unknown"
+
+	// WorkloadFineTuning is LLM fine-tuning: smaller than pre-training and checkpointable
+	WorkloadFineTuning WorkloadType = "fine-tuning"
+	// WorkloadDistributedInference is multi-node model serving (e.g. LeaderWorkerSet)
+	WorkloadDistributedInference WorkloadType = "distributed-inference"
+	// WorkloadDataPrep is CPU-heavy, NUMA-insensitive data preprocessing
+	WorkloadDataPrep WorkloadType = "data-prep"
+	// WorkloadSandbox is a short-lived, isolated sandbox (e.g. for AI agents)
+	WorkloadSandbox WorkloadType = "sandbox"
 )
 
 // SchedulingProfile contains the classification result for a pod
@@ -73,7 +97,12 @@ type SchedulingProfile struct {
 
 // ProfileClassifier classifies pods into tenant tiers and workload types
 type ProfileClassifier struct {
-	handle framework.Handle
+	handle          framework.Handle
+	namespaceLister corelisters.NamespaceLister
+	// kueue is nil when Kueue is not installed
+	kueue *kueueResolver
+	// policy is the compiled classification policy, swapped atomically on ConfigMap changes
+	policy atomic.Pointer[compiledPolicy]
 }
 
 var _ framework.PreFilterPlugin = &ProfileClassifier{}
@@ -84,15 +113,58 @@ func (pl *ProfileClassifier) Name() string {
 }
 
 // New initializes a new plugin and returns it.
-func New(_ context.Context, _ runtime.Object, h framework.Handle) (framework.Plugin, error) {
-	return &ProfileClassifier{
+func New(ctx context.Context, _ runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	pl := &ProfileClassifier{
 		handle: h,
-	}, nil
+	}
+
+	if h != nil && h.SharedInformerFactory() != nil {
+		// Registering the informer here lets the scheduler start and sync it with the others
+		pl.namespaceLister = h.SharedInformerFactory().Core().V1().Namespaces().Lister()
+	}
+
+	if h != nil && h.ClientSet() != nil && h.KubeConfig() != nil {
+		dynamicClient, err := dynamic.NewForConfig(h.KubeConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+		pl.kueue = newKueueResolver(ctx, h.ClientSet().Discovery(), dynamicClient)
+	}
+
+	// Hot-reload the classification policy; without the ConfigMap the built-in heuristics apply
+	if h != nil && h.ClientSet() != nil {
+		if err := utils.WatchConfigMap(ctx, h.ClientSet(), utils.DefaultConfigNamespace, PolicyConfigMapName, pl.onPolicyChange); err != nil {
+			return nil, fmt.Errorf("failed to watch classification policy: %w", err)
+		}
+	}
+
+	return pl, nil
+}
+
+// onPolicyChange compiles and installs the policy from the ConfigMap. An invalid
+// policy is rejected and the previous one stays in effect.
+func (pl *ProfileClassifier) onPolicyChange(cm *v1.ConfigMap) {
+	if cm == nil {
+		pl.policy.Store(nil)
+		klog.InfoS("Classification policy removed, using built-in heuristics")
+		return
+	}
+
+	policy, err := parsePolicy([]byte(cm.Data[PolicyConfigMapKey]))
+	if err != nil {
+		klog.ErrorS(err, "Rejected classification policy, keeping previous policy",
+			"configMap", klog.KObj(cm), "resourceVersion", cm.ResourceVersion)
+		return
+	}
+
+	pl.policy.Store(policy)
+	klog.InfoS("Loaded classification policy",
+		"configMap", klog.KObj(cm), "resourceVersion", cm.ResourceVersion, "rules", len(policy.rules))
 }
 
 // PreFilter classifies the pod and stores the profile in CycleState
 func (pl *ProfileClassifier) PreFilter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo []framework.NodeInfo) (*framework.PreFilterResult, *framework.Status) {
-	profile := pl.classifyPod(ctx, pod)
+	profile := pl.classifyPod(pod)
 
 	state.Write(stateKey, profile)
 
@@ -113,28 +185,68 @@ func (pl *ProfileClassifier) PreFilterExtensions() framework.PreFilterExtensions
 	return nil
 }
 
-// classifyPod performs the actual classification logic
-func (pl *ProfileClassifier) classifyPod(ctx context.Context, pod *v1.Pod) *SchedulingProfile {
+// classifyPod performs the actual classification logic. It reads only from
+// informer caches, so it makes no API calls on the scheduling hot path.
+func (pl *ProfileClassifier) classifyPod(pod *v1.Pod) *SchedulingProfile {
 	profile := &SchedulingProfile{
 		Priority: getPodPriority(pod),
 		QoSClass: pod.Status.QOSClass,
 	}
 
-	profile.TenantTier, profile.TenantName = pl.classifyTenant(ctx, pod)
-	profile.WorkloadType = pl.classifyWorkload(pod)
+	var result policyResult
+	if policy := pl.policy.Load(); policy != nil && len(policy.rules) > 0 {
+		result = policy.evaluate(pod, pl.getNamespace(pod.Namespace))
+	}
+
+	profile.TenantTier, profile.TenantName = result.tier, result.tenant
+	if profile.TenantTier == "" {
+		tier, name := pl.classifyTenant(pod)
+		profile.TenantTier = tier
+		if profile.TenantName == "" {
+			profile.TenantName = name
+		}
+	} else if profile.TenantName == "" {
+		profile.TenantName = pod.Namespace
+	}
+
+	profile.WorkloadType = result.workloadType
+	if profile.WorkloadType == "" {
+		profile.WorkloadType = pl.classifyWorkload(pod)
+	}
+
 	profile.IsGang = isGangPod(pod)
-	profile.IsPreemptible = isPreemptible(pod)
+
+	if result.preemptible != nil {
+		profile.IsPreemptible = *result.preemptible
+	} else {
+		profile.IsPreemptible = isPreemptible(pod)
+	}
 
 	return profile
 }
 
+// getNamespace returns the namespace from the informer cache, or nil if it is unknown
+func (pl *ProfileClassifier) getNamespace(name string) *v1.Namespace {
+	if pl.namespaceLister == nil {
+		return nil
+	}
+	ns, err := pl.namespaceLister.Get(name)
+	if err != nil {
+		klog.V(5).InfoS("Failed to get namespace", "namespace", name, "error", err)
+		return nil
+	}
+	return ns
+}
+
 // classifyTenant determines tenant tier and name
-func (pl *ProfileClassifier) classifyTenant(ctx context.Context, pod *v1.Pod) (TenantTier, string) {
-	if tier, name := pl.getTenantFromKueue(ctx, pod); tier != TierUnknown {
+func (pl *ProfileClassifier) classifyTenant(pod *v1.Pod) (TenantTier, string) {
+	ns := pl.getNamespace(pod.Namespace)
+
+	if tier, name := pl.getTenantFromKueue(pod, ns); tier != TierUnknown {
 		return tier, name
 	}
 
-	if tier, name := pl.getTenantFromNamespace(ctx, pod); tier != TierUnknown {
+	if tier, name := getTenantFromNamespace(pod, ns); tier != TierUnknown {
 		return tier, name
 	}
 
@@ -149,44 +261,62 @@ func (pl *ProfileClassifier) classifyTenant(ctx context.Context, pod *v1.Pod) (T
 	return TierBronze, pod.Namespace
 }
 
-// getTenantFromKueue reads tenant info from Kueue labels
-func (pl *ProfileClassifier) getTenantFromKueue(ctx context.Context, pod *v1.Pod) (TenantTier, string) {
-	queueName, hasQueue := pod.Labels["kueue.x-k8s.io/queue-name"]
+// getTenantFromKueue reads tenant info from the pod's Kueue queue. When the
+// LocalQueue resolves, the tenant is the ClusterQueue's cohort (or the ClusterQueue
+// itself), and a tier label on the ClusterQueue takes precedence over the namespace.
+func (pl *ProfileClassifier) getTenantFromKueue(pod *v1.Pod, ns *v1.Namespace) (TenantTier, string) {
+	queueName, hasQueue := pod.Labels[KueueQueueNameLabel]
 	if !hasQueue {
 		return TierUnknown, ""
 	}
 
-	ns, err := pl.handle.ClientSet().CoreV1().Namespaces().Get(ctx, pod.Namespace, metav1.GetOptions{})
-	if err != nil {
-		klog.V(4).InfoS("Failed to get namespace for Kueue classification", "namespace", pod.Namespace, "error", err)
+	info, resolved := pl.kueue.resolve(pod.Namespace, queueName)
+	if resolved {
+		tenantName := info.tenant()
+		if tier := parseTenantTier(info.Labels[TenantTierLabel]); tier != TierUnknown {
+			return tier, tenantName
+		}
+		if ns != nil {
+			if tier := parseTenantTier(ns.Labels[TenantTierLabel]); tier != TierUnknown {
+				return tier, tenantName
+			}
+		}
+		if tier := inferTierFromName(tenantName); tier != TierUnknown {
+			return tier, tenantName
+		}
+	}
+
+	if ns == nil {
 		return TierUnknown, ""
 	}
 
-	if tier := parseTenantTier(ns.Labels["tenant.kubenexus.io/tier"]); tier != TierUnknown {
+	if tier := parseTenantTier(ns.Labels[TenantTierLabel]); tier != TierUnknown {
 		return tier, queueName
 	}
 
-	if tenantName, ok := ns.Labels["tenant.kubenexus.io/name"]; ok {
+	if tenantName, ok := ns.Labels[TenantNameLabel]; ok {
 		if tier := inferTierFromName(tenantName); tier != TierUnknown {
 			return tier, tenantName
 		}
 		return TierBronze, tenantName
 	}
 
+	if resolved {
+		return TierBronze, info.tenant()
+	}
+
 	return TierUnknown, ""
 }
 
 // getTenantFromNamespace reads tenant info from namespace labels
-func (pl *ProfileClassifier) getTenantFromNamespace(ctx context.Context, pod *v1.Pod) (TenantTier, string) {
-	ns, err := pl.handle.ClientSet().CoreV1().Namespaces().Get(ctx, pod.Namespace, metav1.GetOptions{})
-	if err != nil {
-		klog.V(5).InfoS("Failed to get namespace", "namespace", pod.Namespace, "error", err)
+func getTenantFromNamespace(pod *v1.Pod, ns *v1.Namespace) (TenantTier, string) {
+	if ns == nil {
 		return TierUnknown, ""
 	}
 
-	if tierStr, ok := ns.Labels["tenant.kubenexus.io/tier"]; ok {
+	if tierStr, ok := ns.Labels[TenantTierLabel]; ok {
 		tier := parseTenantTier(tierStr)
-		tenantName := ns.Labels["tenant.kubenexus.io/name"]
+		tenantName := ns.Labels[TenantNameLabel]
 		if tenantName == "" {
 			tenantName = pod.Namespace
 		}
@@ -216,7 +346,7 @@ func (pl *ProfileClassifier) getTenantFromPriority(pod *v1.Pod) TenantTier {
 
 // getTenantFromAnnotations reads tenant tier from pod annotations
 func getTenantFromAnnotations(pod *v1.Pod) TenantTier {
-	if tierStr, ok := pod.Annotations["tenant.kubenexus.io/tier"]; ok {
+	if tierStr, ok := pod.Annotations[TenantTierLabel]; ok {
 		return parseTenantTier(tierStr)
 	}
 	return TierUnknown
@@ -237,6 +367,21 @@ func (pl *ProfileClassifier) classifyWorkload(pod *v1.Pod) WorkloadType {
 		return WorkloadInteractive
 	}
 
+	// Operator-specific classes are checked before the generic batch/service split,
+	// since e.g. LeaderWorkerSet pods look like services and Spark pods like training
+	if isSandboxWorkload(pod) {
+		return WorkloadSandbox
+	}
+	if isDistributedInferenceWorkload(pod) {
+		return WorkloadDistributedInference
+	}
+	if isFineTuningWorkload(pod) {
+		return WorkloadFineTuning
+	}
+	if isDataPrepWorkload(pod) {
+		return WorkloadDataPrep
+	}
+
 	basicType := workload.ClassifyPod(pod)
 
 	switch basicType {
@@ -294,6 +439,72 @@ func isInferenceWorkload(pod *v1.Pod) bool {
 	return false
 }
 
+// isFineTuningWorkload detects LLM fine-tuning jobs
+func isFineTuningWorkload(pod *v1.Pod) bool {
+	// Kubeflow Trainer runtimes for fine-tuning (e.g. torchtune-llama3.2-1b)
+	if runtime, ok := pod.Labels["trainer.kubeflow.org/runtime"]; ok {
+		runtime = strings.ToLower(runtime)
+		if strings.Contains(runtime, "torchtune") || strings.Contains(runtime, "fine-tun") || strings.Contains(runtime, "finetun") {
+			return true
+		}
+	}
+
+	// Fine-tuning frameworks
+	for _, container := range pod.Spec.Containers {
+		image := strings.ToLower(container.Image)
+		if strings.Contains(image, "torchtune") || strings.Contains(image, "axolotl") ||
+			strings.Contains(image, "llama-factory") || strings.Contains(image, "unsloth") {
+			return true
+		}
+	}
+	return false
+}
+
+// isDistributedInferenceWorkload detects multi-node inference (LeaderWorkerSet)
+func isDistributedInferenceWorkload(pod *v1.Pod) bool {
+	if _, ok := pod.Labels[LeaderWorkerSetNameLabel]; ok {
+		return true
+	}
+	return false
+}
+
+// isDataPrepWorkload detects CPU-only data preprocessing (Spark, Dask, Ray Data)
+func isDataPrepWorkload(pod *v1.Pod) bool {
+	// GPU-accelerated pipelines (e.g. Spark RAPIDS) are scheduled like batch, and GPU
+	// Ray workers are usually Ray Train or Serve rather than Ray Data
+	if getGPURequest(pod) > 0 {
+		return false
+	}
+	if _, ok := pod.Labels["spark-role"]; ok {
+		return true
+	}
+	if _, ok := pod.Labels["dask.org/cluster-name"]; ok {
+		return true
+	}
+	if _, ok := pod.Labels["ray.io/node-type"]; ok {
+		return true
+	}
+	return false
+}
+
+// isSandboxWorkload detects short-lived sandboxes (agent-sandbox, gVisor/Kata runtimes).
+// Kata also isolates GPU training and inference, so the runtime class only marks a
+// sandbox for pods without GPUs.
+func isSandboxWorkload(pod *v1.Pod) bool {
+	for key := range pod.Labels {
+		if strings.HasPrefix(key, "agents.x-k8s.io/") {
+			return true
+		}
+	}
+	if pod.Spec.RuntimeClassName != nil && getGPURequest(pod) == 0 {
+		runtimeClass := strings.ToLower(*pod.Spec.RuntimeClassName)
+		if strings.Contains(runtimeClass, "gvisor") || strings.Contains(runtimeClass, "kata") {
+			return true
+		}
+	}
+	return false
+}
+
 // isInteractiveWorkload detects interactive workloads (Jupyter, RStudio, etc.)
 func isInteractiveWorkload(pod *v1.Pod) bool {
 	// Check for explicit interactive annotation
@@ -409,6 +620,14 @@ func parseWorkloadType(s string) WorkloadType {
 		return WorkloadService
 	case "interactive":
 		return WorkloadInteractive
+	case "fine-tuning", "finetuning":
+		return WorkloadFineTuning
+	case "distributed-inference":
+		return WorkloadDistributedInference
+	case "data-prep", "dataprep":
+		return WorkloadDataPrep
+	case "sandbox":
+		return WorkloadSandbox
 	default:
 		return WorkloadUnknown
 	}
@@ -61,6 +61,10 @@ func TestWorkloadTypeParsing(t *testing.T) {
 		{"batch", WorkloadBatch},
 		{"service", WorkloadService},
 		{"interactive", WorkloadInteractive},
+		{"fine-tuning", WorkloadFineTuning},
+		{"distributed-inference", WorkloadDistributedInference},
+		{"data-prep", WorkloadDataPrep},
+		{"sandbox", WorkloadSandbox},
 		{"unknown", WorkloadUnknown},
 	}
 
@@ -73,6 +77,93 @@ func TestWorkloadTypeParsing(t *testing.T) {
 	}
 }
 
+func TestClassifyWorkloadClasses(t *testing.T) {
+	gvisor := "gvisor"
+	sandboxPod := st.MakePod().Obj()
+	sandboxPod.Spec.RuntimeClassName = &gvisor
+	kata := "kata-qemu-nvidia-gpu"
+	kataTrainingPod := st.MakePod().Label("training.kubeflow.org/job-name", "llm").
+		Label("pytorch-replica-type", "worker").
+		Req(map[v1.ResourceName]string{"nvidia.com/gpu": "8"}).Obj()
+	kataTrainingPod.Spec.RuntimeClassName = &kata
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected WorkloadType
+	}{
+		{
+			name: "LeaderWorkerSet pod",
+			pod: st.MakePod().Label(LeaderWorkerSetNameLabel, "llama-405b").
+				Label(LeaderWorkerSetGroupIndexLabel, "0").
+				Req(map[v1.ResourceName]string{"nvidia.com/gpu": "8"}).Obj(),
+			expected: WorkloadDistributedInference,
+		},
+		{
+			name: "torchtune runtime",
+			pod: st.MakePod().Label("trainer.kubeflow.org/runtime", "torchtune-llama3.2-1b").
+				Req(map[v1.ResourceName]string{"nvidia.com/gpu": "2"}).Obj(),
+			expected: WorkloadFineTuning,
+		},
+		{
+			name:     "axolotl image",
+			pod:      st.MakePod().Container("winglian/axolotl:main-latest").Obj(),
+			expected: WorkloadFineTuning,
+		},
+		{
+			name:     "CPU-only Spark executor",
+			pod:      st.MakePod().Label("spark-role", "executor").Obj(),
+			expected: WorkloadDataPrep,
+		},
+		{
+			name: "GPU Spark executor stays batch",
+			pod: st.MakePod().Label("spark-role", "executor").
+				Req(map[v1.ResourceName]string{"nvidia.com/gpu": "1"}).Obj(),
+			expected: WorkloadBatch,
+		},
+		{
+			name:     "agent sandbox",
+			pod:      st.MakePod().Label("agents.x-k8s.io/sandbox-name-hash", "abc123").Obj(),
+			expected: WorkloadSandbox,
+		},
+		{
+			name:     "gVisor runtime class",
+			pod:      sandboxPod,
+			expected: WorkloadSandbox,
+		},
+		{
+			name:     "GPU training under Kata stays training",
+			pod:      kataTrainingPod,
+			expected: WorkloadTraining,
+		},
+		{
+			name:     "CPU-only Ray worker",
+			pod:      st.MakePod().Label("ray.io/node-type", "worker").Obj(),
+			expected: WorkloadDataPrep,
+		},
+		{
+			name: "GPU Ray worker is not data prep",
+			pod: st.MakePod().Label("ray.io/node-type", "worker").
+				Req(map[v1.ResourceName]string{"nvidia.com/gpu": "4"}).Obj(),
+			expected: WorkloadTraining,
+		},
+		{
+			name: "explicit label wins",
+			pod: st.MakePod().Label("workload.kubenexus.io/type", "data-prep").
+				Label(LeaderWorkerSetNameLabel, "lws").Obj(),
+			expected: WorkloadDataPrep,
+		},
+	}
+
+	pl := &ProfileClassifier{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pl.classifyWorkload(tt.pod); got != tt.expected {
+				t.Errorf("classifyWorkload() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGangDetection(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+)
+
+// Annotations published on bound pods so cost tools, descheduler policies and
+// dashboards see the same classification the scheduler used.
+const (
+	AnnotationProfileTier         = "profile.kubenexus.io/tier"
+	AnnotationProfileTenant       = "profile.kubenexus.io/tenant"
+	AnnotationProfileWorkloadType = "profile.kubenexus.io/workload-type"
+	AnnotationProfileGang         = "profile.kubenexus.io/gang"
+	AnnotationProfilePreemptible  = "profile.kubenexus.io/preemptible"
+)
+
+var _ framework.PostBindPlugin = &ProfileClassifier{}
+
+// PostBind publishes the pod's SchedulingProfile as annotations. Publishing is
+// best effort: the pod is already bound, so a failed patch is only logged.
+func (pl *ProfileClassifier) PostBind(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	profile, err := GetProfile(state)
+	if err != nil {
+		klog.V(4).InfoS("PostBind: no profile to publish", "pod", klog.KObj(pod), "error", err)
+		return
+	}
+
+	annotations := ProfileAnnotations(profile)
+	if !annotationsChanged(pod, annotations) {
+		return
+	}
+
+	if pl.handle == nil || pl.handle.ClientSet() == nil {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		klog.ErrorS(err, "PostBind: failed to build profile patch", "pod", klog.KObj(pod))
+		return
+	}
+
+	if _, err := pl.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.V(2).InfoS("PostBind: failed to publish scheduling profile", "pod", klog.KObj(pod), "node", nodeName, "error", err)
+		return
+	}
+
+	klog.V(4).InfoS("PostBind: published scheduling profile", "pod", klog.KObj(pod), "node", nodeName,
+		"tenantTier", profile.TenantTier, "workloadType", profile.WorkloadType)
+}
+
+// ProfileAnnotations renders a SchedulingProfile as pod annotations
+func ProfileAnnotations(profile *SchedulingProfile) map[string]string {
+	return map[string]string{
+		AnnotationProfileTier:         string(profile.TenantTier),
+		AnnotationProfileTenant:       profile.TenantName,
+		AnnotationProfileWorkloadType: string(profile.WorkloadType),
+		AnnotationProfileGang:         strconv.FormatBool(profile.IsGang),
+		AnnotationProfilePreemptible:  strconv.FormatBool(profile.IsPreemptible),
+	}
+}
+
+// annotationsChanged reports whether any published annotation differs from the pod's current value
+func annotationsChanged(pod *v1.Pod, annotations map[string]string) bool {
+	for key, value := range annotations {
+		if current, ok := pod.Annotations[key]; !ok || current != value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPodPreemptible returns the preemptibility published on a bound pod, falling back
+// to the label and priority classification for pods bound without a published profile
+func IsPodPreemptible(pod *v1.Pod) bool {
+	if published, ok := pod.Annotations[AnnotationProfilePreemptible]; ok {
+		return published == "true"
+	}
+	return isPreemptible(pod)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profileclassifier
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+func TestPostBindPublishesProfile(t *testing.T) {
+	pod := st.MakePod().Name("trainer-0").Namespace("ml").Obj()
+	handle, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{pod}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create test framework: %v", err)
+	}
+	pl := &ProfileClassifier{handle: handle}
+
+	state := framework.NewCycleState()
+	state.Write(stateKey, &SchedulingProfile{
+		TenantTier:    TierGold,
+		TenantName:    "vision",
+		WorkloadType:  WorkloadTraining,
+		IsGang:        true,
+		IsPreemptible: false,
+	})
+
+	pl.PostBind(context.Background(), state, pod, "node-1")
+
+	got, err := handle.ClientSet().CoreV1().Pods("ml").Get(context.Background(), "trainer-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	expected := map[string]string{
+		AnnotationProfileTier:         "gold",
+		AnnotationProfileTenant:       "vision",
+		AnnotationProfileWorkloadType: "training",
+		AnnotationProfileGang:         "true",
+		AnnotationProfilePreemptible:  "false",
+	}
+	for key, value := range expected {
+		if got.Annotations[key] != value {
+			t.Errorf("Annotation %s = %q, want %q", key, got.Annotations[key], value)
+		}
+	}
+}
+
+func TestPostBindWithoutProfile(t *testing.T) {
+	pod := st.MakePod().Name("web").Namespace("default").Obj()
+	handle, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{pod}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create test framework: %v", err)
+	}
+	pl := &ProfileClassifier{handle: handle}
+
+	pl.PostBind(context.Background(), framework.NewCycleState(), pod, "node-1")
+
+	got, err := handle.ClientSet().CoreV1().Pods("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	if _, ok := got.Annotations[AnnotationProfileTier]; ok {
+		t.Error("Expected no profile annotations without a profile in CycleState")
+	}
+}
+
+func TestAnnotationsChanged(t *testing.T) {
+	profile := &SchedulingProfile{TenantTier: TierSilver, TenantName: "ns", WorkloadType: WorkloadBatch}
+	annotations := ProfileAnnotations(profile)
+
+	pod := st.MakePod().Obj()
+	if !annotationsChanged(pod, annotations) {
+		t.Error("Expected change for pod without annotations")
+	}
+
+	pod.Annotations = annotations
+	if annotationsChanged(pod, annotations) {
+		t.Error("Expected no change when annotations already match")
+	}
+}
+
+func TestIsPodPreemptible(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{name: "published preemptible", pod: st.MakePod().Annotation(AnnotationProfilePreemptible, "true").Obj(), want: true},
+		{name: "published overrides priority", pod: st.MakePod().Annotation(AnnotationProfilePreemptible, "false").Priority(10).Obj(), want: false},
+		{name: "unpublished low priority", pod: st.MakePod().Priority(10).Obj(), want: true},
+		{name: "unpublished default", pod: st.MakePod().Obj(), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPodPreemptible(tt.pod); got != tt.want {
+				t.Errorf("IsPodPreemptible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcefragmentation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+
+	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+)
+
+// Cluster-wide fragmentation:
+//   - Every ReportInterval the GPU islands of all schedulable nodes are analyzed and
+//     exported as gauges: gangs of 1, 2, 4, 8 and 16+ GPUs that free capacity could
+//     hold, stranded GPUs per island topology, pristine islands per GPU model, and the
+//     fragmentation index
+//   - The same report is served as JSON at /debug/fragmentation by DebugHandler, which
+//     the scheduler binary mounts next to /metrics
+//   - Gangs larger than a node are built from whole free islands, one member per node
+
+const (
+	// DefaultReportInterval is how often the fragmentation gauges are refreshed
+	DefaultReportInterval = 30 * time.Second
+
+	// DebugFragmentationPath serves the cluster fragmentation report
+	DebugFragmentationPath = "/debug/fragmentation"
+)
+
+// GangSizes are the gang GPU counts reported; the last one stands for that size and larger
+var GangSizes = []int{1, 2, 4, 8, 16}
+
+var (
+	// reporterOnce starts one reporter however many profiles enable the plugin
+	reporterOnce sync.Once
+
+	// activeReporter is the running reporter served by DebugHandler
+	activeReporter atomic.Pointer[fragmentationReporter]
+)
+
+// ResourceFragmentationScoreArgs configures the plugin
+type ResourceFragmentationScoreArgs struct {
+	// ReportIntervalSeconds is how often the fragmentation gauges are refreshed
+	ReportIntervalSeconds int `json:"reportIntervalSeconds,omitempty"`
+}
+
+// FragmentationReport is the cluster-wide GPU fragmentation at a point in time
+type FragmentationReport struct {
+	Time      time.Time `json:"time"`
+	GPUNodes  int       `json:"gpuNodes"`
+	TotalGPUs int       `json:"totalGPUs"`
+	FreeGPUs  int       `json:"freeGPUs"`
+
+	// GangPlacements counts the gangs of each size free capacity could hold, keyed by
+	// gang size ("16+" for the largest)
+	GangPlacements map[string]int `json:"gangPlacements"`
+	// StrandedGPUs counts free GPUs on partially used islands, keyed by island topology
+	StrandedGPUs map[string]int `json:"strandedGPUs"`
+	// PristineIslands counts fully free islands, keyed by GPU model
+	PristineIslands map[string]int `json:"pristineIslands"`
+	// FragmentationIndex is the share of free GPUs that are stranded: 0 when all free
+	// GPUs are in pristine islands, 1 when none are
+	FragmentationIndex float64 `json:"fragmentationIndex"`
+}
+
+// gangSizeLabel returns the report key of a gang size
+func gangSizeLabel(size int) string {
+	if size == GangSizes[len(GangSizes)-1] {
+		return strconv.Itoa(size) + "+"
+	}
+	return strconv.Itoa(size)
+}
+
+// AnalyzeFragmentation builds the cluster fragmentation report of the islands
+func AnalyzeFragmentation(islands []*GPUIsland) *FragmentationReport {
+	report := &FragmentationReport{
+		Time:            time.Now(),
+		GPUNodes:        len(islands),
+		GangPlacements:  make(map[string]int, len(GangSizes)),
+		StrandedGPUs:    make(map[string]int),
+		PristineIslands: make(map[string]int),
+	}
+
+	stranded := 0
+	for _, island := range islands {
+		free := max(island.AvailableGPUs, 0)
+		report.TotalGPUs += island.TotalGPUs
+		report.FreeGPUs += free
+		if island.IsPristine {
+			report.PristineIslands[island.GPUModel]++
+		} else if free > 0 {
+			report.StrandedGPUs[island.Topology] += free
+			stranded += free
+		}
+	}
+
+	for _, size := range GangSizes {
+		placements, wholeFree := 0, 0
+		for _, island := range islands {
+			free := max(island.AvailableGPUs, 0)
+			switch {
+			case size <= island.TotalGPUs:
+				placements += free / size
+			case island.AllocatedGPUs == 0:
+				// Larger gangs take whole free islands
+				wholeFree += free
+			}
+		}
+		report.GangPlacements[gangSizeLabel(size)] = placements + wholeFree/size
+	}
+
+	if report.FreeGPUs > 0 {
+		report.FragmentationIndex = float64(stranded) / float64(report.FreeGPUs)
+	}
+	return report
+}
+
+// fragmentationReporter analyzes the cluster from the informer caches
+type fragmentationReporter struct {
+	nodeLister corelisters.NodeLister
+	podLister  corelisters.PodLister
+	synced     []cache.InformerSynced
+}
+
+// analyze builds the fragmentation report of schedulable GPU nodes
+func (r *fragmentationReporter) analyze() (*FragmentationReport, error) {
+	nodes, err := r.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pods, err := r.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzeFragmentation(BuildIslands(nodes, pods)), nil
+}
+
+// BuildIslands returns the GPU islands of the schedulable GPU nodes, with the GPUs
+// requested by the pods bound to them and not yet finished
+func BuildIslands(nodes []*v1.Node, pods []*v1.Pod) []*GPUIsland {
+	allocated := make(map[string]int)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		allocated[pod.Spec.NodeName] += GetGPURequest(pod)
+	}
+
+	var islands []*GPUIsland
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !hasGPUs(node) {
+			continue
+		}
+		islands = append(islands, newGPUIsland(node, allocated[node.Name]))
+	}
+	return islands
+}
+
+// export refreshes the fragmentation gauges
+func (r *fragmentationReporter) export() {
+	report, err := r.analyze()
+	if err != nil {
+		klog.V(4).InfoS("ResourceFragmentationScore: failed to analyze cluster fragmentation", "err", err)
+		return
+	}
+
+	for size, placements := range report.GangPlacements {
+		schedulermetrics.GangPlacementsAvailable.WithLabelValues(size).Set(float64(placements))
+	}
+	schedulermetrics.StrandedGPUs.Reset()
+	for topology, gpus := range report.StrandedGPUs {
+		schedulermetrics.StrandedGPUs.WithLabelValues(topology).Set(float64(gpus))
+	}
+	schedulermetrics.PristineIslands.Reset()
+	for model, islands := range report.PristineIslands {
+		schedulermetrics.PristineIslands.WithLabelValues(model).Set(float64(islands))
+	}
+	schedulermetrics.GPUFragmentationIndex.Set(report.FragmentationIndex)
+
+	klog.V(5).InfoS("ResourceFragmentationScore: cluster fragmentation", "gpuNodes", report.GPUNodes,
+		"freeGPUs", report.FreeGPUs, "fragmentationIndex", report.FragmentationIndex)
+}
+
+// run refreshes the gauges once the informer caches have synced, then every interval
+// until the context is done
+func (r *fragmentationReporter) run(ctx context.Context, interval time.Duration) {
+	if !cache.WaitForCacheSync(ctx.Done(), r.synced...) {
+		return
+	}
+	r.export()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.export()
+		}
+	}
+}
+
+// ServeHTTP writes the current fragmentation report as JSON
+func (r *fragmentationReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	report, err := r.analyze()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		klog.V(4).InfoS("ResourceFragmentationScore: failed to write fragmentation report", "err", err)
+	}
+}
+
+// DebugHandler serves the cluster fragmentation report as JSON. It answers 503 until
+// a profile enables the plugin.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reporter := activeReporter.Load()
+		if reporter == nil {
+			http.Error(w, Name+" is not enabled", http.StatusServiceUnavailable)
+			return
+		}
+		reporter.ServeHTTP(w, req)
+	})
+}
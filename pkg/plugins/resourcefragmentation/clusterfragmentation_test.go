@@ -0,0 +1,171 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcefragmentation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+// gpuNode builds a node with the GPU count, topology and model
+func gpuNode(name, gpus, topology, model string) *v1.Node {
+	return testutil.MakeNode(name, map[string]string{
+		LabelGPUTopology: topology,
+		LabelGPUModel:    model,
+	}, v1.ResourceList{ResourceGPU: resource.MustParse(gpus)})
+}
+
+// gpuPod builds a pod bound to the node requesting the GPUs
+func gpuPod(name, nodeName, gpus string) *v1.Pod {
+	return testutil.MakePod(name, "default", nodeName, v1.ResourceList{ResourceGPU: resource.MustParse(gpus)}, nil, nil)
+}
+
+func TestAnalyzeFragmentation(t *testing.T) {
+	island := func(name string, total, allocated int, topology, model string) *GPUIsland {
+		return newGPUIsland(gpuNode(name, strconv.Itoa(total), topology, model), allocated)
+	}
+	islands := []*GPUIsland{
+		island("h100-a", 8, 0, TopologyNVSwitch, "H100"),
+		island("h100-b", 8, 0, TopologyNVSwitch, "H100"),
+		island("h100-c", 8, 5, TopologyNVSwitch, "H100"), // 3 stranded
+		island("a100-a", 4, 1, TopologyNVLink, "A100"),   // 3 stranded
+	}
+
+	report := AnalyzeFragmentation(islands)
+	if report.TotalGPUs != 28 || report.FreeGPUs != 22 {
+		t.Errorf("TotalGPUs, FreeGPUs = %d, %d, want 28, 22", report.TotalGPUs, report.FreeGPUs)
+	}
+	wantGangs := map[string]int{"1": 22, "2": 10, "4": 4, "8": 2, "16+": 1}
+	for size, want := range wantGangs {
+		if got := report.GangPlacements[size]; got != want {
+			t.Errorf("GangPlacements[%s] = %d, want %d", size, got, want)
+		}
+	}
+	if report.StrandedGPUs[TopologyNVSwitch] != 3 || report.StrandedGPUs[TopologyNVLink] != 3 {
+		t.Errorf("StrandedGPUs = %v, want 3 nvswitch and 3 nvlink", report.StrandedGPUs)
+	}
+	if report.PristineIslands["H100"] != 2 || len(report.PristineIslands) != 1 {
+		t.Errorf("PristineIslands = %v, want 2 H100", report.PristineIslands)
+	}
+	if want := 6.0 / 22.0; report.FragmentationIndex != want {
+		t.Errorf("FragmentationIndex = %v, want %v", report.FragmentationIndex, want)
+	}
+
+	if empty := AnalyzeFragmentation(nil); empty.FragmentationIndex != 0 || empty.GangPlacements["8"] != 0 {
+		t.Errorf("AnalyzeFragmentation(nil) = %+v, want an empty report", empty)
+	}
+}
+
+func TestFragmentationReporter(t *testing.T) {
+	cordoned := gpuNode("cordoned", "8", TopologyNVSwitch, "H100")
+	cordoned.Spec.Unschedulable = true
+	nodes := []*v1.Node{
+		gpuNode("h100-a", "8", TopologyNVSwitch, "H100"),
+		gpuNode("h100-b", "8", TopologyNVSwitch, "H100"),
+		testutil.MakeNode("cpu-only", nil, nil),
+		cordoned,
+	}
+	finished := gpuPod("finished", "h100-a", "8")
+	finished.Status.Phase = v1.PodSucceeded
+	pods := []*v1.Pod{
+		gpuPod("small", "h100-b", "2"),
+		gpuPod("pending", "", "8"),
+		finished,
+	}
+	reporter := &fragmentationReporter{
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+		podLister:  testutil.NewFakePodLister(pods),
+	}
+
+	recorder := httptest.NewRecorder()
+	reporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DebugFragmentationPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() status = %d, want 200", recorder.Code)
+	}
+	var report FragmentationReport
+	if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+
+	// Only schedulable GPU nodes count; pending and finished pods hold no GPUs
+	if report.GPUNodes != 2 || report.FreeGPUs != 14 {
+		t.Errorf("GPUNodes, FreeGPUs = %d, %d, want 2, 14", report.GPUNodes, report.FreeGPUs)
+	}
+	if report.PristineIslands["H100"] != 1 || report.StrandedGPUs[TopologyNVSwitch] != 6 {
+		t.Errorf("PristineIslands = %v, StrandedGPUs = %v, want 1 H100 and 6 nvswitch", report.PristineIslands, report.StrandedGPUs)
+	}
+	if report.GangPlacements["8"] != 1 {
+		t.Errorf("GangPlacements[8] = %d, want 1", report.GangPlacements["8"])
+	}
+}
+
+func TestReporterExportsAtStartup(t *testing.T) {
+	reporter := &fragmentationReporter{
+		nodeLister: testutil.NewFakeNodeLister([]*v1.Node{
+			gpuNode("h100-a", "8", TopologyNVSwitch, "H100"),
+			gpuNode("h100-b", "8", TopologyNVSwitch, "H100"),
+		}),
+		podLister: testutil.NewFakePodLister([]*v1.Pod{gpuPod("small", "h100-b", "2")}),
+	}
+	schedulermetrics.GPUFragmentationIndex.Set(0)
+
+	// The gauges are set before the first tick of a long interval
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reporter.run(ctx, time.Hour)
+
+	want := 6.0 / 14.0
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return promtestutil.ToFloat64(schedulermetrics.GPUFragmentationIndex) == want, nil
+	}); err != nil {
+		t.Errorf("fragmentation index = %v, want %v", promtestutil.ToFloat64(schedulermetrics.GPUFragmentationIndex), want)
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	defer activeReporter.Store(activeReporter.Load())
+
+	activeReporter.Store(nil)
+	recorder := httptest.NewRecorder()
+	DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DebugFragmentationPath, nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("DebugHandler() without the plugin status = %d, want 503", recorder.Code)
+	}
+
+	activeReporter.Store(&fragmentationReporter{
+		nodeLister: testutil.NewFakeNodeLister([]*v1.Node{gpuNode("h100-a", "8", TopologyNVSwitch, "H100")}),
+		podLister:  testutil.NewFakePodLister(nil),
+	})
+	recorder = httptest.NewRecorder()
+	DebugHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DebugFragmentationPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("DebugHandler() status = %d, want 200", recorder.Code)
+	}
+}
@@ -23,13 +23,14 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
 	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
@@ -63,8 +64,7 @@ const (
 )
 
 type ResourceFragmentationScore struct {
-	handle    framework.Handle
-	podLister corelisters.PodLister
+	handle framework.Handle
 }
 
 var _ framework.ScorePlugin = &ResourceFragmentationScore{}
@@ -88,7 +88,7 @@ func (rf *ResourceFragmentationScore) Name() string {
 
 // Filter filters out nodes that don't have sufficient GPUs or violate tenant restrictions
 func (rf *ResourceFragmentationScore) Filter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) *framework.Status {
-	requestedGPUs := getGPURequest(pod)
+	requestedGPUs := GetGPURequest(pod)
 	if requestedGPUs == 0 {
 		// No GPU request, allow node
 		return framework.NewStatus(framework.Success)
@@ -135,7 +135,7 @@ func (rf *ResourceFragmentationScore) Score(ctx context.Context, state framework
 		return rf.scoreCPUMemoryFragmentation(pod, nodeInfo), framework.NewStatus(framework.Success)
 	}
 
-	requestedGPUs := getGPURequest(pod)
+	requestedGPUs := GetGPURequest(pod)
 	if requestedGPUs == 0 {
 		return rf.scoreCPUMemoryFragmentation(pod, nodeInfo), framework.NewStatus(framework.Success)
 	}
@@ -254,30 +254,22 @@ func (rf *ResourceFragmentationScore) detectGPUIsland(nodeInfo framework.NodeInf
 		return nil
 	}
 
-	totalGPUs, hasGPU := node.Status.Capacity[ResourceGPU]
-	if !hasGPU || totalGPUs.IsZero() {
+	if !hasGPUs(node) {
 		return nil
 	}
+	return newGPUIsland(node, int(requestedGPUs(nodeInfo)))
+}
 
-	allocatedGPUs := int64(0)
-	// podLister might be nil in test scenarios
-	if rf.podLister != nil {
-		allPods, err := rf.podLister.List(labels.Everything())
-		if err == nil {
-			for _, pod := range allPods {
-				if pod.Spec.NodeName == node.Name {
-					for _, container := range pod.Spec.Containers {
-						if gpuReq, ok := container.Resources.Requests[ResourceGPU]; ok {
-							allocatedGPUs += gpuReq.Value()
-						}
-					}
-				}
-			}
-		}
-	}
+// hasGPUs reports whether the node has GPU capacity
+func hasGPUs(node *v1.Node) bool {
+	totalGPUs, hasGPU := node.Status.Capacity[ResourceGPU]
+	return hasGPU && !totalGPUs.IsZero()
+}
 
+// newGPUIsland describes the GPU island of a node with allocatedGPUCount GPUs in use
+func newGPUIsland(node *v1.Node, allocatedGPUCount int) *GPUIsland {
+	totalGPUs := node.Status.Capacity[ResourceGPU]
 	totalGPUCount := int(totalGPUs.Value())
-	allocatedGPUCount := int(allocatedGPUs)
 	availableGPUCount := totalGPUCount - allocatedGPUCount
 
 	topology := "unknown"
@@ -325,6 +317,13 @@ func (rf *ResourceFragmentationScore) detectGPUIsland(nodeInfo framework.NodeInf
 	}
 }
 
+// requestedGPUs returns the GPUs requested on the node. The cycle's NodeInfo holds
+// assumed pods and, during preemption, the simulated removals and nominated pods,
+// none of which the listers or the snapshot index reflect.
+func requestedGPUs(nodeInfo framework.NodeInfo) int64 {
+	return nodeInfo.GetRequested().GetScalarResources()[ResourceGPU]
+}
+
 func (rf *ResourceFragmentationScore) scoreCPUMemoryFragmentation(pod *v1.Pod, nodeInfo framework.NodeInfo) int64 {
 	node := nodeInfo.Node()
 	if node == nil {
@@ -332,21 +331,7 @@ func (rf *ResourceFragmentationScore) scoreCPUMemoryFragmentation(pod *v1.Pod, n
 	}
 
 	allocatableCPU := float64(node.Status.Allocatable.Cpu().MilliValue())
-	requestedCPU := float64(0)
-
-	// podLister might be nil in test scenarios
-	if rf.podLister != nil {
-		allPods, err := rf.podLister.List(labels.Everything())
-		if err == nil {
-			for _, p := range allPods {
-				if p.Spec.NodeName == node.Name {
-					for _, container := range p.Spec.Containers {
-						requestedCPU += float64(container.Resources.Requests.Cpu().MilliValue())
-					}
-				}
-			}
-		}
-	}
+	requestedCPU := float64(nodeInfo.GetRequested().GetMilliCPU())
 
 	cpuUtilization := 0.0
 	if allocatableCPU > 0 {
@@ -356,7 +341,8 @@ func (rf *ResourceFragmentationScore) scoreCPUMemoryFragmentation(pod *v1.Pod, n
 	return int64(cpuUtilization)
 }
 
-func getGPURequest(pod *v1.Pod) int {
+// GetGPURequest returns the GPUs requested by the pod's containers
+func GetGPURequest(pod *v1.Pod) int {
 	totalGPUs := 0
 	for _, container := range pod.Spec.Containers {
 		if gpuReq, ok := container.Resources.Requests[ResourceGPU]; ok {
@@ -377,13 +363,15 @@ func (rf *ResourceFragmentationScore) getPodWorkloadType(state framework.CycleSt
 }
 
 // calculateWorkloadFragmentationPenalty computes penalty based on workload type mismatch
-// Training jobs should use pristine islands, inference/batch can use fragmented nodes
+// Training jobs should use pristine islands, inference/batch can use fragmented nodes,
+// fine-tuning fills partial islands and CPU-oriented workloads stay off premium islands
 func (rf *ResourceFragmentationScore) calculateWorkloadFragmentationPenalty(pod *v1.Pod, island *GPUIsland, workloadType string) int64 {
-	requestedGPUs := getGPURequest(pod)
+	requestedGPUs := GetGPURequest(pod)
 
 	// Training workloads: prefer large pristine islands (8+ GPUs)
 	// Prevent fragmentation of training-ready islands
-	if workloadType == "training" || workloadType == "batch" {
+	// Distributed inference shards a model across whole islands like training does
+	if workloadType == "training" || workloadType == "batch" || workloadType == "distributed-inference" {
 		// If this is a high-quality island (NVSwitch/NVLink) and pod is small, penalize
 		// to preserve the island for training jobs
 		if island.Quality >= IslandQualityNVLink && island.TotalGPUs >= 8 {
@@ -401,6 +389,25 @@ func (rf *ResourceFragmentationScore) calculateWorkloadFragmentationPenalty(pod
 		return 0
 	}
 
+	// Fine-tuning workloads: small and checkpointable, so they should fill
+	// partially used islands instead of opening a pristine training island
+	if workloadType == "fine-tuning" {
+		if island.IsPristine && island.Quality >= IslandQualityNVLink && island.TotalGPUs >= 8 &&
+			requestedGPUs < island.TotalGPUs/2 {
+			return PenaltyFragmentLargeIsland
+		}
+		return 0
+	}
+
+	// Data-prep and sandbox workloads rarely need GPUs; when they do, keep them
+	// off high-quality islands entirely
+	if workloadType == "data-prep" || workloadType == "sandbox" {
+		if island.Quality >= IslandQualityNVLink {
+			return PenaltyFragmentLargeIsland + 10
+		}
+		return 0
+	}
+
 	// Interactive workloads: prefer isolated nodes to avoid interference
 	if workloadType == "interactive" {
 		// If island is nearly full, avoid adding interactive workload
@@ -413,11 +420,31 @@ func (rf *ResourceFragmentationScore) calculateWorkloadFragmentationPenalty(pod
 	return 0
 }
 
-func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	podLister := handle.SharedInformerFactory().Core().V1().Pods().Lister()
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := ResourceFragmentationScoreArgs{}
+	if err := frameworkruntime.DecodeInto(obj, &args); err != nil {
+		return nil, fmt.Errorf("failed to decode %s args: %w", Name, err)
+	}
+	interval := DefaultReportInterval
+	if args.ReportIntervalSeconds > 0 {
+		interval = time.Duration(args.ReportIntervalSeconds) * time.Second
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods()
+	nodeInformer := handle.SharedInformerFactory().Core().V1().Nodes()
+
+	// Export cluster-wide fragmentation, once for all profiles
+	reporterOnce.Do(func() {
+		reporter := &fragmentationReporter{
+			nodeLister: nodeInformer.Lister(),
+			podLister:  podInformer.Lister(),
+			synced:     []cache.InformerSynced{nodeInformer.Informer().HasSynced, podInformer.Informer().HasSynced},
+		}
+		activeReporter.Store(reporter)
+		go reporter.run(ctx, interval)
+	})
 
 	return &ResourceFragmentationScore{
-		handle:    handle,
-		podLister: podLister,
+		handle: handle,
 	}, nil
 }
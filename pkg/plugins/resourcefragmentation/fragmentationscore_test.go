@@ -138,7 +138,7 @@ func TestGetGPURequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gpuCount := getGPURequest(tt.pod)
+			gpuCount := GetGPURequest(tt.pod)
 			if gpuCount != tt.expectedGPU {
 				t.Errorf("Expected %d GPUs, got %d", tt.expectedGPU, gpuCount)
 			}
@@ -245,11 +245,8 @@ func TestScoreWithFramework(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create plugin directly with fake pod lister
-			plugin := &ResourceFragmentationScore{
-				handle:    nil,
-				podLister: testutil.NewFakePodLister(existingPods),
-			}
+			// Create plugin directly; GPU usage comes from the NodeInfo
+			plugin := &ResourceFragmentationScore{}
 
 			// Create shared lister for getting NodeInfo
 			snapshot := testutil.NewFakeSharedLister(existingPods, nodes)
@@ -333,10 +330,7 @@ func TestDetectGPUIsland(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create plugin with fake pod lister
-			plugin := &ResourceFragmentationScore{
-				podLister: testutil.NewFakePodLister(nil),
-			}
+			plugin := &ResourceFragmentationScore{}
 
 			// Create NodeInfo from node
 			nodeInfo := framework.NewNodeInfo()
@@ -382,11 +376,7 @@ func TestScoreTopologyPreference(t *testing.T) {
 
 	snapshot := testutil.NewFakeSharedLister(nil, nodes)
 
-	// Create plugin directly with fake pod lister
-	plugin := &ResourceFragmentationScore{
-		handle:    nil,
-		podLister: testutil.NewFakePodLister(nil),
-	}
+	plugin := &ResourceFragmentationScore{}
 
 	state := framework.NewCycleState()
 
@@ -422,3 +412,59 @@ func TestScoreTopologyPreference(t *testing.T) {
 		t.Errorf("PCIe node should score 0 (too small), got %d", pcieScore)
 	}
 }
+
+func TestWorkloadFragmentationPenalty(t *testing.T) {
+	pristineNVSwitch := &GPUIsland{TotalGPUs: 8, AvailableGPUs: 8, Quality: IslandQualityNVSwitch, IsPristine: true}
+	usedNVSwitch := &GPUIsland{TotalGPUs: 8, AvailableGPUs: 6, AllocatedGPUs: 2, Quality: IslandQualityNVSwitch}
+	pcie := &GPUIsland{TotalGPUs: 4, AvailableGPUs: 4, Quality: IslandQualityPCIe, IsPristine: true}
+
+	twoGPUs := testutil.MakePod("p", "default", "", v1.ResourceList{ResourceGPU: resource.MustParse("2")}, nil, nil)
+
+	tests := []struct {
+		name         string
+		workloadType string
+		island       *GPUIsland
+		expected     int64
+	}{
+		{"training fragments used island", "training", usedNVSwitch, PenaltyFragmentLargeIsland + 10},
+		{"distributed inference fragments used island", "distributed-inference", usedNVSwitch, PenaltyFragmentLargeIsland + 10},
+		{"fine-tuning opens pristine island", "fine-tuning", pristineNVSwitch, PenaltyFragmentLargeIsland},
+		{"fine-tuning fills used island", "fine-tuning", usedNVSwitch, 0},
+		{"data-prep on NVSwitch", "data-prep", usedNVSwitch, PenaltyFragmentLargeIsland + 10},
+		{"data-prep on PCIe", "data-prep", pcie, 0},
+		{"sandbox on NVSwitch", "sandbox", pristineNVSwitch, PenaltyFragmentLargeIsland + 10},
+		{"inference anywhere", "inference", usedNVSwitch, 0},
+	}
+
+	rf := &ResourceFragmentationScore{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rf.calculateWorkloadFragmentationPenalty(twoGPUs, tt.island, tt.workloadType); got != tt.expected {
+				t.Errorf("calculateWorkloadFragmentationPenalty() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFilterCountsPodsOnlyInNodeInfo tests that pods the cycle's NodeInfo holds but
+// no lister knows about, such as assumed pods, use up the node's GPUs
+func TestFilterCountsPodsOnlyInNodeInfo(t *testing.T) {
+	plugin := &ResourceFragmentationScore{}
+	node := testutil.MakeNode("gpu-node", map[string]string{LabelGPUTopology: TopologyNVLink},
+		v1.ResourceList{ResourceGPU: resource.MustParse("4")})
+	assumed := testutil.MakePod("assumed", "default", "gpu-node",
+		v1.ResourceList{ResourceGPU: resource.MustParse("3")}, nil, nil)
+	pod := testutil.MakePod("pending", "default", "",
+		v1.ResourceList{ResourceGPU: resource.MustParse("2")}, nil, nil)
+
+	nodeInfo := framework.NewNodeInfo(assumed)
+	nodeInfo.SetNode(node)
+
+	if island := plugin.detectGPUIsland(nodeInfo); island.AvailableGPUs != 1 {
+		t.Errorf("Expected AvailableGPUs=1, got %d", island.AvailableGPUs)
+	}
+	status := plugin.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+	if status.IsSuccess() {
+		t.Error("Expected Filter to reject a node whose GPUs are held by an assumed pod")
+	}
+}
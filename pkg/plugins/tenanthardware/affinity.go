@@ -0,0 +1,292 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"fmt"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/gpucatalog"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+)
+
+// Affinity matrix:
+//   - Hardware tiers are named freely; a node's tier is its hardware.kubenexus.io/tier
+//     label, else the tier listing its GPU model, else the tier of the model in the GPU
+//     catalog. A matrix not naming the catalog tiers (premium, standard, economy) does
+//     not score those, so unlisted models get the default score; compile logs this.
+//   - Tenant tiers are named freely too; a pod's tier is its
+//     scheduling.kubenexus.io/priority-tier annotation, else the tier listing its tenant
+//     (ProfileClassifier's tenant name, else the namespace), else the tier its
+//     ProfileClassifier tier maps to, else the first tier with a fragment of its
+//     priorityClassName, else the default
+//   - Each tenant tier scores each hardware tier; unlisted pairs get the default score
+//
+// The built-in matrix is three tenant tiers over premium, standard and economy.
+
+// HardwareTierConfig names a hardware tier and the GPU models in it
+type HardwareTierConfig struct {
+	// Name is the tier, matched against hardware.kubenexus.io/tier node labels
+	Name string `json:"name"`
+
+	// GPUModels are catalog model names or gpu.kubenexus.io/model values in the tier;
+	// models not listed anywhere keep their catalog tier
+	GPUModels []string `json:"gpuModels,omitempty"`
+}
+
+// TenantTierConfig names a tenant tier, how pods are assigned to it and how it scores
+// each hardware tier
+type TenantTierConfig struct {
+	// Name is the tier, matched against scheduling.kubenexus.io/priority-tier annotations
+	Name string `json:"name"`
+
+	// Tenants are tenant names mapped here, ahead of their profile tier
+	Tenants []string `json:"tenants,omitempty"`
+
+	// ProfileTiers are the ProfileClassifier tenant tiers (gold, silver, bronze) mapped here
+	ProfileTiers []string `json:"profileTiers,omitempty"`
+
+	// PriorityClasses are fragments of priorityClassName, case-insensitive, mapped here
+	PriorityClasses []string `json:"priorityClasses,omitempty"`
+
+	// Scores are 0-100 by hardware tier name
+	Scores map[string]int64 `json:"scores,omitempty"`
+}
+
+// AffinityMatrix configures tenant tiers, hardware tiers and the scores between them
+type AffinityMatrix struct {
+	HardwareTiers []HardwareTierConfig `json:"hardwareTiers"`
+	TenantTiers   []TenantTierConfig   `json:"tenantTiers"`
+
+	// DefaultTenantTier is assigned to pods no tenant tier matches; the first by default
+	DefaultTenantTier string `json:"defaultTenantTier,omitempty"`
+
+	// DefaultScore is the score of pairs a tenant tier does not list
+	DefaultScore *int64 `json:"defaultScore,omitempty"`
+}
+
+// DefaultAffinityMatrix returns the built-in matrix: high, medium and low priority
+// tenants over premium, standard and economy hardware
+func DefaultAffinityMatrix() *AffinityMatrix {
+	defaultScore := int64(ScoreAcceptableMatch)
+	return &AffinityMatrix{
+		HardwareTiers: []HardwareTierConfig{{Name: TierPremium}, {Name: TierStandard}, {Name: TierEconomy}},
+		TenantTiers: []TenantTierConfig{
+			{
+				Name:            PriorityHigh,
+				ProfileTiers:    []string{string(profileclassifier.TierGold)},
+				PriorityClasses: []string{"high", "critical"},
+				// High-priority can use any tier, but prefers premium
+				Scores: map[string]int64{TierPremium: ScorePerfectMatch, TierStandard: ScoreAcceptableMatch, TierEconomy: ScoreAcceptableMatch - 10},
+			},
+			{
+				Name:            PriorityMedium,
+				ProfileTiers:    []string{string(profileclassifier.TierSilver)},
+				PriorityClasses: []string{"medium", "normal"},
+				// Don't waste premium on medium-priority
+				Scores: map[string]int64{TierPremium: ScoreMismatchPenalty, TierStandard: ScorePerfectMatch, TierEconomy: ScoreAcceptableMatch},
+			},
+			{
+				Name:            PriorityLow,
+				ProfileTiers:    []string{string(profileclassifier.TierBronze)},
+				PriorityClasses: []string{"low", "best-effort"},
+				// Preserve better hardware for higher-priority work
+				Scores: map[string]int64{TierPremium: ScoreMismatchPenalty, TierStandard: ScoreMismatchPenalty, TierEconomy: ScorePerfectMatch},
+			},
+		},
+		DefaultTenantTier: PriorityMedium,
+		DefaultScore:      &defaultScore,
+	}
+}
+
+// affinityMatrix is a validated AffinityMatrix indexed for lookups
+type affinityMatrix struct {
+	tenantTiers       []TenantTierConfig
+	tenantByName      map[string]*TenantTierConfig
+	tenantByProfile   map[string]string
+	tierByTenant      map[string]string
+	hardwareTiers     map[string]bool
+	modelTiers        map[string]string // upper-cased model name to hardware tier
+	defaultTenantTier string
+	defaultScore      int64
+
+	// unscoredCatalogTiers are the catalog tiers unlisted models fall back to that the
+	// matrix does not name
+	unscoredCatalogTiers []string
+}
+
+var defaultAffinity = mustCompileAffinity(DefaultAffinityMatrix())
+
+func mustCompileAffinity(m *AffinityMatrix) *affinityMatrix {
+	compiled, err := m.compile()
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in affinity matrix: %v", err))
+	}
+	return compiled
+}
+
+// compile validates the matrix and indexes it
+func (m *AffinityMatrix) compile() (*affinityMatrix, error) {
+	if len(m.HardwareTiers) == 0 || len(m.TenantTiers) == 0 {
+		return nil, fmt.Errorf("at least one hardware tier and one tenant tier are required")
+	}
+	c := &affinityMatrix{
+		tenantTiers:     m.TenantTiers,
+		tenantByName:    make(map[string]*TenantTierConfig, len(m.TenantTiers)),
+		tenantByProfile: make(map[string]string),
+		tierByTenant:    make(map[string]string),
+		hardwareTiers:   make(map[string]bool, len(m.HardwareTiers)),
+		modelTiers:      make(map[string]string),
+		defaultScore:    ScoreAcceptableMatch,
+	}
+
+	for i, tier := range m.HardwareTiers {
+		if tier.Name == "" {
+			return nil, fmt.Errorf("hardware tier %d: name is required", i)
+		}
+		if c.hardwareTiers[tier.Name] {
+			return nil, fmt.Errorf("hardware tier %d: duplicate name %q", i, tier.Name)
+		}
+		c.hardwareTiers[tier.Name] = true
+		for _, model := range tier.GPUModels {
+			key := strings.ToUpper(model)
+			if other, ok := c.modelTiers[key]; ok {
+				return nil, fmt.Errorf("hardware tier %q: GPU model %q is already in tier %q", tier.Name, model, other)
+			}
+			c.modelTiers[key] = tier.Name
+		}
+	}
+
+	for i := range m.TenantTiers {
+		tier := &m.TenantTiers[i]
+		if tier.Name == "" {
+			return nil, fmt.Errorf("tenant tier %d: name is required", i)
+		}
+		if _, ok := c.tenantByName[tier.Name]; ok {
+			return nil, fmt.Errorf("tenant tier %d: duplicate name %q", i, tier.Name)
+		}
+		c.tenantByName[tier.Name] = tier
+		for _, tenant := range tier.Tenants {
+			if other, ok := c.tierByTenant[tenant]; ok {
+				return nil, fmt.Errorf("tenant tier %q: tenant %q is already mapped to %q", tier.Name, tenant, other)
+			}
+			c.tierByTenant[tenant] = tier.Name
+		}
+		for _, profileTier := range tier.ProfileTiers {
+			switch profileclassifier.TenantTier(profileTier) {
+			case profileclassifier.TierGold, profileclassifier.TierSilver, profileclassifier.TierBronze:
+			default:
+				return nil, fmt.Errorf("tenant tier %q: unknown profile tier %q", tier.Name, profileTier)
+			}
+			if other, ok := c.tenantByProfile[profileTier]; ok {
+				return nil, fmt.Errorf("tenant tier %q: profile tier %q is already mapped to %q", tier.Name, profileTier, other)
+			}
+			c.tenantByProfile[profileTier] = tier.Name
+		}
+		for hardwareTier, score := range tier.Scores {
+			if !c.hardwareTiers[hardwareTier] {
+				return nil, fmt.Errorf("tenant tier %q: score for unknown hardware tier %q", tier.Name, hardwareTier)
+			}
+			if score < 0 || score > 100 {
+				return nil, fmt.Errorf("tenant tier %q: score %d for %q is outside 0-100", tier.Name, score, hardwareTier)
+			}
+		}
+	}
+
+	c.defaultTenantTier = m.DefaultTenantTier
+	if c.defaultTenantTier == "" {
+		c.defaultTenantTier = m.TenantTiers[0].Name
+	} else if _, ok := c.tenantByName[c.defaultTenantTier]; !ok {
+		return nil, fmt.Errorf("default tenant tier %q is not a tenant tier", c.defaultTenantTier)
+	}
+	if m.DefaultScore != nil {
+		if *m.DefaultScore < 0 || *m.DefaultScore > 100 {
+			return nil, fmt.Errorf("default score %d is outside 0-100", *m.DefaultScore)
+		}
+		c.defaultScore = *m.DefaultScore
+	}
+
+	for _, tier := range []string{gpucatalog.TierPremium, gpucatalog.TierStandard, gpucatalog.TierEconomy} {
+		if !c.hardwareTiers[tier] {
+			c.unscoredCatalogTiers = append(c.unscoredCatalogTiers, tier)
+		}
+	}
+	if len(c.unscoredCatalogTiers) > 0 {
+		klog.InfoS("Affinity matrix does not name some GPU catalog tiers: GPU models it does not list fall back to them and get the default score",
+			"catalogTiers", c.unscoredCatalogTiers, "defaultScore", c.defaultScore)
+	}
+	return c, nil
+}
+
+// tenantTierForProfile maps a ProfileClassifier tier to a tenant tier
+func (c *affinityMatrix) tenantTierForProfile(tier profileclassifier.TenantTier) string {
+	if name, ok := c.tenantByProfile[string(tier)]; ok {
+		return name
+	}
+	return c.defaultTenantTier
+}
+
+// tenantTierForTenant returns the tenant tier listing the tenant, if any
+func (c *affinityMatrix) tenantTierForTenant(tenant string) (string, bool) {
+	tier, ok := c.tierByTenant[tenant]
+	return tier, ok
+}
+
+// tenantTierForPriorityClass maps a priority class name to the first tenant tier with a
+// fragment of it
+func (c *affinityMatrix) tenantTierForPriorityClass(priorityClassName string) (string, bool) {
+	priorityClass := strings.ToLower(priorityClassName)
+	for _, tier := range c.tenantTiers {
+		for _, fragment := range tier.PriorityClasses {
+			if strings.Contains(priorityClass, strings.ToLower(fragment)) {
+				return tier.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hardwareTierForModel returns the tier listing the GPU model, by its label value or
+// its catalog name and aliases, else the model's catalog tier
+func (c *affinityMatrix) hardwareTierForModel(gpuModel string) string {
+	if tier, ok := c.modelTiers[strings.ToUpper(gpuModel)]; ok {
+		return tier
+	}
+	model, ok := gpucatalog.Get().LookupModel(gpuModel)
+	if !ok {
+		return ""
+	}
+	names := append([]string{model.Name}, model.Aliases...)
+	for _, name := range names {
+		if tier, ok := c.modelTiers[strings.ToUpper(name)]; ok {
+			return tier
+		}
+	}
+	return model.Tier
+}
+
+// score returns the score of the tenant tier on the hardware tier
+func (c *affinityMatrix) score(tenantTier, hardwareTier string) (int64, bool) {
+	if tier, ok := c.tenantByName[tenantTier]; ok {
+		if score, ok := tier.Scores[hardwareTier]; ok {
+			return score, true
+		}
+	}
+	return c.defaultScore, false
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+// generationsArgs configures five hardware generations and four tenant tiers
+const generationsArgs = `{
+  "affinity": {
+    "hardwareTiers": [
+      {"name": "blackwell", "gpuModels": ["B200", "GB200"]},
+      {"name": "hopper", "gpuModels": ["H100", "H200"]},
+      {"name": "ampere", "gpuModels": ["A100", "A100-80GB"]},
+      {"name": "ada", "gpuModels": ["L40S", "L4"]},
+      {"name": "turing", "gpuModels": ["T4"]}
+    ],
+    "tenantTiers": [
+      {"name": "platinum", "tenants": ["frontier-lab"], "priorityClasses": ["critical"],
+       "scores": {"blackwell": 100, "hopper": 90, "ampere": 60, "ada": 30, "turing": 10}},
+      {"name": "gold", "profileTiers": ["gold"], "priorityClasses": ["high"],
+       "scores": {"blackwell": 40, "hopper": 100, "ampere": 80, "ada": 40, "turing": 20}},
+      {"name": "silver", "profileTiers": ["silver"],
+       "scores": {"blackwell": 10, "hopper": 30, "ampere": 100, "ada": 80, "turing": 50}},
+      {"name": "bronze", "profileTiers": ["bronze"], "priorityClasses": ["low"],
+       "scores": {"ada": 90, "turing": 100}}
+    ],
+    "defaultTenantTier": "silver",
+    "defaultScore": 0
+  }
+}`
+
+func newAffinityPlugin(t *testing.T, args string) *TenantHardwareAffinity {
+	t.Helper()
+	plugin, err := New(context.Background(), &runtime.Unknown{Raw: []byte(args)}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return plugin.(*TenantHardwareAffinity)
+}
+
+func TestConfiguredAffinityMatrix(t *testing.T) {
+	plugin := newAffinityPlugin(t, generationsArgs)
+
+	// Hardware tiers come from the label, the configured models (by catalog alias too),
+	// then the catalog
+	hardware := map[string]map[string]string{
+		"b200":      {LabelGPUModel: "B200"},
+		"h100-sxm":  {LabelGPUModel: "NVIDIA-H100-80GB-HBM3"},
+		"labelled":  {LabelGPUModel: "T4", LabelHardwareTier: "ampere"},
+		"a10":       {LabelGPUModel: "A10"},
+		"no-labels": {},
+	}
+	wantHardware := map[string]string{"b200": "blackwell", "h100-sxm": "hopper", "labelled": "ampere", "a10": TierEconomy, "no-labels": ""}
+	for name, labels := range hardware {
+		if got := plugin.getHardwareTier(testutil.MakeNode(name, labels, nil)); got != wantHardware[name] {
+			t.Errorf("getHardwareTier(%s) = %q, want %q", name, got, wantHardware[name])
+		}
+	}
+
+	// The catalog tiers unlisted models like the A10 fall back to are not scored
+	if got := plugin.affinity.unscoredCatalogTiers; !slices.Equal(got, []string{TierPremium, TierStandard, TierEconomy}) {
+		t.Errorf("unscoredCatalogTiers = %v, want all catalog tiers", got)
+	}
+
+	tenants := []struct {
+		namespace     string
+		priorityClass string
+		annotation    string
+		want          string
+	}{
+		{priorityClass: "system-cluster-critical", want: "platinum"},
+		{priorityClass: "High-Priority", want: "gold"},
+		{priorityClass: "low", want: "bronze"},
+		{priorityClass: "normal", want: "silver"},
+		{annotation: "bronze", want: "bronze"},
+		{want: "silver"},
+		{namespace: "frontier-lab", priorityClass: "low", want: "platinum"},
+	}
+	for _, tt := range tenants {
+		namespace := "default"
+		if tt.namespace != "" {
+			namespace = tt.namespace
+		}
+		pod := testutil.MakePod("p", namespace, "", nil, nil, nil)
+		pod.Spec.PriorityClassName = tt.priorityClass
+		if tt.annotation != "" {
+			pod.Annotations = map[string]string{AnnotationPriorityTier: tt.annotation}
+		}
+		if got := plugin.getTenantPriority(pod); got != tt.want {
+			t.Errorf("getTenantPriority(%q, %q) = %q, want %q", tt.priorityClass, tt.annotation, got, tt.want)
+		}
+	}
+	if got := plugin.mapTenantTierToPriority(profileclassifier.TierGold); got != "gold" {
+		t.Errorf("mapTenantTierToPriority(gold) = %q, want gold", got)
+	}
+
+	node := testutil.MakeNode("n", nil, nil)
+	scores := []struct {
+		tenant, hardware string
+		want             int64
+	}{
+		{"platinum", "blackwell", 100},
+		{"gold", "hopper", 100},
+		{"silver", "ampere", 100},
+		{"bronze", "turing", 100},
+		{"bronze", "blackwell", 0}, // unlisted: defaultScore
+		{"unknown", "hopper", 0},
+		{"gold", "", ScoreNoHardwareInfo},
+	}
+	for _, tt := range scores {
+		if got := plugin.calculateAffinityScore(tt.tenant, tt.hardware, node); got != tt.want {
+			t.Errorf("calculateAffinityScore(%s, %s) = %d, want %d", tt.tenant, tt.hardware, got, tt.want)
+		}
+	}
+}
+
+func TestPricePerformanceWithConfiguredTiers(t *testing.T) {
+	// Price-performance follows the catalog tier whatever the matrix calls it
+	node := testutil.MakeNode("n", map[string]string{LabelGPUModel: "H100", LabelHardwareTier: "hopper"}, nil)
+	if got := performanceTier(node); got != TierPremium {
+		t.Errorf("performanceTier() = %q, want %q", got, TierPremium)
+	}
+}
+
+func TestInvalidAffinityMatrix(t *testing.T) {
+	invalid := map[string]string{
+		"no tiers":              `{"affinity": {"hardwareTiers": [], "tenantTiers": []}}`,
+		"duplicate hardware":    `{"affinity": {"hardwareTiers": [{"name": "a"}, {"name": "a"}], "tenantTiers": [{"name": "t"}]}}`,
+		"model in two tiers":    `{"affinity": {"hardwareTiers": [{"name": "a", "gpuModels": ["H100"]}, {"name": "b", "gpuModels": ["h100"]}], "tenantTiers": [{"name": "t"}]}}`,
+		"duplicate tenant":      `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t"}, {"name": "t"}]}}`,
+		"unknown profile tier":  `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t", "profileTiers": ["diamond"]}]}}`,
+		"profile tier twice":    `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t", "profileTiers": ["gold"]}, {"name": "u", "profileTiers": ["gold"]}]}}`,
+		"unknown hardware tier": `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t", "scores": {"b": 50}}]}}`,
+		"score out of range":    `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t", "scores": {"a": 150}}]}}`,
+		"tenant in two tiers":   `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t", "tenants": ["x"]}, {"name": "u", "tenants": ["x"]}]}}`,
+		"unknown default tier":  `{"affinity": {"hardwareTiers": [{"name": "a"}], "tenantTiers": [{"name": "t"}], "defaultTenantTier": "u"}}`,
+	}
+	for name, args := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(context.Background(), &runtime.Unknown{Raw: []byte(args)}, nil)
+			if err == nil || !strings.Contains(err.Error(), "affinity matrix") {
+				t.Errorf("New() error = %v, want an invalid affinity matrix", err)
+			}
+		})
+	}
+}
+
+func TestDefaultAffinityMatrix(t *testing.T) {
+	// The built-in matrix scores the way the fixed matrix did
+	plugin := &TenantHardwareAffinity{}
+	pod := testutil.MakePod("p", "default", "", nil, nil, nil)
+	pod.Spec.PriorityClassName = "best-effort"
+	if got := plugin.getTenantPriority(pod); got != PriorityLow {
+		t.Errorf("getTenantPriority(best-effort) = %q, want %q", got, PriorityLow)
+	}
+	if got := plugin.calculateAffinityScore(PriorityHigh, "custom-tier", &v1.Node{}); got != ScoreAcceptableMatch {
+		t.Errorf("calculateAffinityScore(unlisted tier) = %d, want %d", got, ScoreAcceptableMatch)
+	}
+	if got := defaultAffinity.unscoredCatalogTiers; len(got) != 0 {
+		t.Errorf("built-in matrix unscoredCatalogTiers = %v, want none", got)
+	}
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/resourcefragmentation"
+	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+)
+
+// Tenant budgets:
+//   - Budgets per GPU-hour are read from the kubenexus-tenant-budgets ConfigMap and
+//     hot-reloaded; tenants without a budget are unlimited
+//   - Running spend is the GPUs of each tenant's bound, unfinished pods times their
+//     node's price per GPU-hour, refreshed every SpendInterval and exported per tenant
+//   - Pods reserved on a node count from Reserve until a refresh sees them bound, so
+//     a burst of pods between refreshes cannot overshoot the budget
+//   - A tenant whose spend has reached its budget is steered to the cheapest hardware
+//     that fits, or with overBudget: hold, its GPU pods wait in the queue until spend
+//     drops below the budget; they are activated once a refresh or a budget change
+//     finds the tenant no longer held
+
+const (
+	// BudgetConfigMapName is the ConfigMap holding tenant budgets
+	BudgetConfigMapName = "kubenexus-tenant-budgets"
+
+	// BudgetConfigMapKey is the ConfigMap data key containing the budget document
+	BudgetConfigMapKey = "budgets.yaml"
+
+	// DefaultSpendInterval is how often tenant spend is recomputed from bound pods
+	DefaultSpendInterval = 30 * time.Second
+)
+
+// OverBudgetAction is what happens to a tenant's pods once its spend reaches its budget
+type OverBudgetAction string
+
+const (
+	// OverBudgetSteer scores the cheapest hardware highest
+	OverBudgetSteer OverBudgetAction = "steer"
+	// OverBudgetHold keeps GPU pods in the queue
+	OverBudgetHold OverBudgetAction = "hold"
+)
+
+// TenantBudget is one tenant's GPU budget
+type TenantBudget struct {
+	// Tenant is the tenant name ProfileClassifier assigns, by default the namespace
+	Tenant string `json:"tenant"`
+
+	// PerHour is the budget in currency per hour, in the units of the GPU prices
+	PerHour float64 `json:"perHour"`
+
+	// OverBudget is steer (the default) or hold
+	OverBudget OverBudgetAction `json:"overBudget,omitempty"`
+}
+
+// BudgetDocument is the budget file format
+type BudgetDocument struct {
+	Budgets []TenantBudget `json:"budgets"`
+}
+
+// ParseBudgets decodes a YAML or JSON budget document into budgets by tenant.
+// Any invalid entry fails the whole document.
+func ParseBudgets(data []byte) (map[string]TenantBudget, error) {
+	doc := &BudgetDocument{}
+	if err := yaml.UnmarshalStrict(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant budgets: %w", err)
+	}
+
+	budgets := make(map[string]TenantBudget, len(doc.Budgets))
+	for i, budget := range doc.Budgets {
+		switch {
+		case budget.Tenant == "":
+			return nil, fmt.Errorf("budget %d: tenant is required", i)
+		case budget.PerHour < 0:
+			return nil, fmt.Errorf("budget %d (%q): perHour must not be negative", i, budget.Tenant)
+		case budget.OverBudget != "" && budget.OverBudget != OverBudgetSteer && budget.OverBudget != OverBudgetHold:
+			return nil, fmt.Errorf("budget %d (%q): unknown overBudget action %q", i, budget.Tenant, budget.OverBudget)
+		}
+		if _, ok := budgets[budget.Tenant]; ok {
+			return nil, fmt.Errorf("budget %d: duplicate tenant %q", i, budget.Tenant)
+		}
+		if budget.OverBudget == "" {
+			budget.OverBudget = OverBudgetSteer
+		}
+		budgets[budget.Tenant] = budget
+	}
+	return budgets, nil
+}
+
+// budgetTracker holds the tenant budgets, the running spend of bound pods and the
+// spend of pods reserved but not yet seen bound
+type budgetTracker struct {
+	podLister  corelisters.PodLister
+	nodeLister corelisters.NodeLister
+	synced     []cache.InformerSynced
+
+	budgets atomic.Pointer[map[string]TenantBudget]
+	spend   atomic.Pointer[map[string]float64]
+
+	// activate moves held pods back to the active queue
+	activate func(pods map[string]*v1.Pod)
+
+	lock     sync.Mutex
+	inFlight map[types.UID]podSpend           // reserved pod -> spend
+	held     map[string]map[types.UID]*v1.Pod // tenant -> held pods
+}
+
+// podSpend is the spend per hour of one pod
+type podSpend struct {
+	tenant  string
+	perHour float64
+}
+
+// onBudgetChange installs the budgets of the ConfigMap. An invalid document keeps the
+// budgets in effect.
+func (t *budgetTracker) onBudgetChange(cm *v1.ConfigMap) {
+	budgets := map[string]TenantBudget{}
+	if cm != nil {
+		parsed, err := ParseBudgets([]byte(cm.Data[BudgetConfigMapKey]))
+		if err != nil {
+			klog.ErrorS(err, "Invalid tenant budgets, keeping previous budgets", "configMap", klog.KObj(cm))
+			return
+		}
+		budgets = parsed
+	}
+	t.budgets.Store(&budgets)
+
+	schedulermetrics.TenantGPUBudget.Reset()
+	for tenant, budget := range budgets {
+		schedulermetrics.TenantGPUBudget.WithLabelValues(tenant).Set(budget.PerHour)
+	}
+	klog.InfoS("Loaded tenant budgets", "tenants", len(budgets))
+	t.releaseHeld()
+}
+
+// refresh recomputes the spend of every tenant and exports it
+func (t *budgetTracker) refresh() {
+	nodes, err := t.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.V(4).InfoS("TenantHardwareAffinity: failed to list nodes for tenant spend", "err", err)
+		return
+	}
+	pods, err := t.podLister.List(labels.Everything())
+	if err != nil {
+		klog.V(4).InfoS("TenantHardwareAffinity: failed to list pods for tenant spend", "err", err)
+		return
+	}
+
+	spend := TenantSpend(nodes, pods)
+	t.spend.Store(&spend)
+	t.forgetSettled(pods)
+
+	total := t.inFlightSpend()
+	for tenant, perHour := range spend {
+		total[tenant] += perHour
+	}
+	schedulermetrics.TenantGPUSpend.Reset()
+	for tenant, perHour := range total {
+		schedulermetrics.TenantGPUSpend.WithLabelValues(tenant).Set(perHour)
+	}
+	t.releaseHeld()
+}
+
+// reserve counts the spend of a GPU pod reserved on the node until a refresh sees it
+// bound or it is unreserved
+func (t *budgetTracker) reserve(pod *v1.Pod, tenant, nodeName string) {
+	if t == nil {
+		return
+	}
+	gpus := resourcefragmentation.GetGPURequest(pod)
+	if gpus == 0 {
+		return
+	}
+	node, err := t.nodeLister.Get(nodeName)
+	if err != nil {
+		klog.V(4).InfoS("TenantHardwareAffinity: failed to get node for tenant spend", "node", nodeName, "err", err)
+		return
+	}
+	cost, ok := nodeCostPerGPUHour(node)
+	if !ok {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.inFlight == nil {
+		t.inFlight = make(map[types.UID]podSpend)
+	}
+	t.inFlight[pod.UID] = podSpend{tenant: tenant, perHour: float64(gpus) * cost}
+}
+
+// unreserve stops counting the spend of a pod whose reservation failed
+func (t *budgetTracker) unreserve(pod *v1.Pod) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.inFlight, pod.UID)
+}
+
+// forgetSettled stops tracking reserved and held pods that are bound, and so in the
+// refreshed spend, or gone
+func (t *budgetTracker) forgetSettled(pods []*v1.Pod) {
+	pending := make(map[types.UID]bool)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			pending[pod.UID] = true
+		}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for uid := range t.inFlight {
+		if !pending[uid] {
+			delete(t.inFlight, uid)
+		}
+	}
+	for tenant, pods := range t.held {
+		for uid := range pods {
+			if !pending[uid] {
+				delete(pods, uid)
+			}
+		}
+		if len(pods) == 0 {
+			delete(t.held, tenant)
+		}
+	}
+}
+
+// hold remembers a pod held in the queue for its tenant's budget
+func (t *budgetTracker) hold(pod *v1.Pod, tenant string) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.held == nil {
+		t.held = make(map[string]map[types.UID]*v1.Pod)
+	}
+	if t.held[tenant] == nil {
+		t.held[tenant] = make(map[types.UID]*v1.Pod)
+	}
+	t.held[tenant][pod.UID] = pod
+}
+
+// releaseHeld activates the held pods of tenants no longer held: back under budget, or
+// whose budget is gone or no longer holds
+func (t *budgetTracker) releaseHeld() {
+	t.lock.Lock()
+	tenants := make([]string, 0, len(t.held))
+	for tenant := range t.held {
+		tenants = append(tenants, tenant)
+	}
+	t.lock.Unlock()
+
+	released := make(map[string]*v1.Pod)
+	for _, tenant := range tenants {
+		if budget, _, over := t.overBudget(tenant); over && budget.OverBudget == OverBudgetHold {
+			continue
+		}
+		t.lock.Lock()
+		for _, pod := range t.held[tenant] {
+			released[pod.Namespace+"/"+pod.Name] = pod
+		}
+		delete(t.held, tenant)
+		t.lock.Unlock()
+	}
+	if len(released) == 0 || t.activate == nil {
+		return
+	}
+	klog.V(3).InfoS("Activating pods of tenants no longer held by their budget", "pods", len(released))
+	t.activate(released)
+}
+
+// inFlightSpend returns the spend of reserved pods not yet seen bound, by tenant
+func (t *budgetTracker) inFlightSpend() map[string]float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	spend := make(map[string]float64)
+	for _, pod := range t.inFlight {
+		spend[pod.tenant] += pod.perHour
+	}
+	return spend
+}
+
+// run refreshes tenant spend once the informer caches have synced, then every
+// interval until the context is done
+func (t *budgetTracker) run(ctx context.Context, interval time.Duration) {
+	if !cache.WaitForCacheSync(ctx.Done(), t.synced...) {
+		return
+	}
+	t.refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.refresh()
+		}
+	}
+}
+
+// overBudget returns the tenant's budget when its spend has reached it
+func (t *budgetTracker) overBudget(tenant string) (TenantBudget, float64, bool) {
+	if t == nil {
+		return TenantBudget{}, 0, false
+	}
+	budgets := t.budgets.Load()
+	if budgets == nil {
+		return TenantBudget{}, 0, false
+	}
+	budget, ok := (*budgets)[tenant]
+	if !ok {
+		return TenantBudget{}, 0, false
+	}
+	spent := t.inFlightSpend()[tenant]
+	if spend := t.spend.Load(); spend != nil {
+		spent += (*spend)[tenant]
+	}
+	return budget, spent, spent >= budget.PerHour
+}
+
+// TenantSpend returns the GPU spend per hour of each tenant's bound, unfinished pods
+func TenantSpend(nodes []*v1.Node, pods []*v1.Pod) map[string]float64 {
+	costs := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		if cost, ok := nodeCostPerGPUHour(node); ok {
+			costs[node.Name] = cost
+		}
+	}
+
+	spend := make(map[string]float64)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		gpus := resourcefragmentation.GetGPURequest(pod)
+		if gpus == 0 {
+			continue
+		}
+		spend[boundPodTenant(pod)] += float64(gpus) * costs[pod.Spec.NodeName]
+	}
+	return spend
+}
+
+// boundPodTenant returns the tenant ProfileClassifier published on a bound pod, else
+// its namespace
+func boundPodTenant(pod *v1.Pod) string {
+	if tenant := pod.Annotations[profileclassifier.AnnotationProfileTenant]; tenant != "" {
+		return tenant
+	}
+	return pod.Namespace
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/backend/queue"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+const testBudgets = `
+budgets:
+- tenant: team-a
+  perHour: 10
+- tenant: team-b
+  perHour: 5
+  overBudget: hold
+`
+
+func budgetConfigMap(data string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: BudgetConfigMapName},
+		Data:       map[string]string{BudgetConfigMapKey: data},
+	}
+}
+
+// gpuPodIn builds a pod in the namespace bound to the node requesting the GPUs
+func gpuPodIn(name, namespace, nodeName, gpus string) *v1.Pod {
+	return testutil.MakePod(name, namespace, nodeName, v1.ResourceList{ResourceGPU: resource.MustParse(gpus)}, nil, nil)
+}
+
+func TestParseBudgets(t *testing.T) {
+	budgets, err := ParseBudgets([]byte(testBudgets))
+	if err != nil {
+		t.Fatalf("ParseBudgets() error = %v", err)
+	}
+	if budgets["team-a"].OverBudget != OverBudgetSteer || budgets["team-b"].OverBudget != OverBudgetHold {
+		t.Errorf("ParseBudgets() = %+v, want team-a steered and team-b held", budgets)
+	}
+
+	invalid := []string{
+		"budgets:\n- perHour: 1\n",
+		"budgets:\n- tenant: a\n  perHour: -1\n",
+		"budgets:\n- tenant: a\n  perHour: 1\n  overBudget: evict\n",
+		"budgets:\n- tenant: a\n  perHour: 1\n- tenant: a\n  perHour: 2\n",
+		"budgets:\n- tenant: a\n  perHourr: 1\n",
+	}
+	for _, data := range invalid {
+		if _, err := ParseBudgets([]byte(data)); err == nil {
+			t.Errorf("ParseBudgets(%q) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestTenantSpend(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("h100", map[string]string{LabelGPUModel: "H100"}, nil),
+		testutil.MakeNode("priced", map[string]string{LabelGPUModel: "H100", LabelCostPerHour: "2.00"}, nil),
+	}
+	published := gpuPodIn("published", "shared", "h100", "1")
+	published.Annotations = map[string]string{profileclassifier.AnnotationProfileTenant: "team-a"}
+	finished := gpuPodIn("finished", "team-a", "h100", "8")
+	finished.Status.Phase = v1.PodSucceeded
+	pods := []*v1.Pod{
+		gpuPodIn("a", "team-a", "h100", "2"),
+		gpuPodIn("b", "team-a", "priced", "1"),
+		gpuPodIn("pending", "team-a", "", "8"),
+		gpuPodIn("c", "team-b", "priced", "4"),
+		published,
+		finished,
+	}
+
+	spend := TenantSpend(nodes, pods)
+	// team-a: 2 + 1 published H100 GPUs at the catalog price, 1 at the labelled price
+	if want := 3*3.50 + 2.00; spend["team-a"] != want {
+		t.Errorf("spend[team-a] = %v, want %v", spend["team-a"], want)
+	}
+	if spend["team-b"] != 8.00 || len(spend) != 2 {
+		t.Errorf("TenantSpend() = %v, want team-b 8.00 and no other tenants", spend)
+	}
+}
+
+func TestBudgetTracker(t *testing.T) {
+	nodes := []*v1.Node{testutil.MakeNode("h100", map[string]string{LabelGPUModel: "H100"}, nil)}
+	pods := []*v1.Pod{
+		gpuPodIn("a", "team-a", "h100", "2"), // 7.00/h of 10
+		gpuPodIn("b", "team-b", "h100", "2"), // 7.00/h of 5
+	}
+	tracker := &budgetTracker{
+		podLister:  testutil.NewFakePodLister(pods),
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+	}
+	if _, _, over := tracker.overBudget("team-b"); over {
+		t.Error("overBudget() before budgets are loaded = true, want false")
+	}
+
+	tracker.onBudgetChange(budgetConfigMap(testBudgets))
+	tracker.refresh()
+	if _, _, over := tracker.overBudget("team-a"); over {
+		t.Error("overBudget(team-a) = true, want false")
+	}
+	if budget, spent, over := tracker.overBudget("team-b"); !over || spent != 7.00 || budget.OverBudget != OverBudgetHold {
+		t.Errorf("overBudget(team-b) = %+v, %v, %v, want held at 7.00", budget, spent, over)
+	}
+	if _, _, over := tracker.overBudget("no-budget"); over {
+		t.Error("overBudget(no-budget) = true, want unlimited")
+	}
+
+	// An invalid document keeps the budgets; removing the ConfigMap clears them
+	tracker.onBudgetChange(budgetConfigMap("budgets: [{perHour: 1}]"))
+	if _, _, over := tracker.overBudget("team-b"); !over {
+		t.Error("invalid budgets replaced the previous ones")
+	}
+	tracker.onBudgetChange(nil)
+	if _, _, over := tracker.overBudget("team-b"); over {
+		t.Error("overBudget(team-b) after the ConfigMap is removed = true, want false")
+	}
+
+	var nilTracker *budgetTracker
+	if _, _, over := nilTracker.overBudget("team-b"); over {
+		t.Error("nil tracker reported a tenant over budget")
+	}
+}
+
+func TestBudgetTrackerCountsReservedPods(t *testing.T) {
+	nodes := []*v1.Node{testutil.MakeNode("h100", map[string]string{LabelGPUModel: "H100"}, nil)}
+	running := gpuPodIn("a", "team-a", "h100", "2") // 7.00/h of 10
+	pending := gpuPodIn("p", "team-a", "", "1")     // 3.50/h once reserved
+	pending.UID = "p-uid"
+	tracker := &budgetTracker{
+		podLister:  testutil.NewFakePodLister([]*v1.Pod{running, pending}),
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+	}
+	tracker.onBudgetChange(budgetConfigMap(testBudgets))
+	tracker.refresh()
+	plugin := &TenantHardwareAffinity{budgets: tracker}
+	state := framework.NewCycleState()
+
+	// The reserved pod counts before any refresh sees it bound
+	if status := plugin.Reserve(context.Background(), state, pending, "h100"); !status.IsSuccess() {
+		t.Fatalf("Reserve() = %v", status)
+	}
+	if _, spent, over := tracker.overBudget("team-a"); !over || spent != 10.50 {
+		t.Errorf("overBudget(team-a) after Reserve = %v, %v, want over at 10.50", spent, over)
+	}
+	tracker.refresh()
+	if _, spent, _ := tracker.overBudget("team-a"); spent != 10.50 {
+		t.Errorf("spend after a refresh of the still pending pod = %v, want 10.50", spent)
+	}
+	plugin.Unreserve(context.Background(), state, pending, "h100")
+	if _, spent, over := tracker.overBudget("team-a"); over || spent != 7.00 {
+		t.Errorf("overBudget(team-a) after Unreserve = %v, %v, want 7.00", spent, over)
+	}
+
+	// Once bound, the pod is counted once, by the refresh
+	plugin.Reserve(context.Background(), state, pending, "h100")
+	bound := pending.DeepCopy()
+	bound.Spec.NodeName = "h100"
+	tracker.podLister = testutil.NewFakePodLister([]*v1.Pod{running, bound})
+	tracker.refresh()
+	if _, spent, _ := tracker.overBudget("team-a"); spent != 10.50 {
+		t.Errorf("spend after the pod is bound = %v, want 10.50", spent)
+	}
+	if len(tracker.inFlight) != 0 {
+		t.Errorf("in-flight pods after the pod is bound = %v, want none", tracker.inFlight)
+	}
+}
+
+// TestHeldPodsActivatedUnderBudget tests that pods held for their tenant's budget are
+// moved back to the active queue once a refresh finds the tenant under budget, or a
+// budget change no longer holds it
+func TestHeldPodsActivatedUnderBudget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := klog.FromContext(ctx)
+
+	metrics.Register()
+	queue := internalqueue.NewTestQueue(ctx, nil)
+	fh, err := testutil.NewTestFramework(nil, frameworkruntime.WithPodActivator(queue))
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+
+	nodes := []*v1.Node{testutil.MakeNode("h100", map[string]string{LabelGPUModel: "H100"}, nil)}
+	running := gpuPodIn("b", "team-b", "h100", "2") // 7.00/h of 5: held
+	tracker := &budgetTracker{
+		podLister:  testutil.NewFakePodLister([]*v1.Pod{running}),
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+		activate: func(pods map[string]*v1.Pod) {
+			fh.Activate(logger, pods)
+		},
+	}
+	tracker.onBudgetChange(budgetConfigMap(testBudgets))
+	tracker.refresh()
+	plugin := &TenantHardwareAffinity{budgets: tracker}
+
+	// hold parks a pod of the over-budget tenant as unschedulable
+	hold := func(name string) *v1.Pod {
+		pod := gpuPodIn(name, "team-b", "", "1")
+		pod.UID = types.UID(name)
+		tracker.podLister = testutil.NewFakePodLister([]*v1.Pod{running, pod})
+		queue.Add(logger, pod)
+		queuedInfo, err := queue.Pop(logger)
+		if err != nil {
+			t.Fatalf("Pop() error = %v", err)
+		}
+		if _, status := plugin.PreFilter(ctx, framework.NewCycleState(), pod, nil); status.Code() != fwk.UnschedulableAndUnresolvable {
+			t.Fatalf("PreFilter(%s) = %v, want UnschedulableAndUnresolvable", name, status)
+		}
+		queuedInfo.UnschedulablePlugins = sets.New(Name)
+		if err := queue.AddUnschedulableIfNotPresent(logger, queuedInfo, queue.SchedulingCycle()); err != nil {
+			t.Fatalf("AddUnschedulableIfNotPresent() error = %v", err)
+		}
+		if _, summary := queue.PendingPods(); !strings.Contains(summary, "unschedulablePods:1") {
+			t.Fatalf("queue = %s, want %s unschedulable", summary, name)
+		}
+		return pod
+	}
+
+	// A refresh finding the tenant still over budget keeps the pod held
+	held := hold("held-1")
+	tracker.refresh()
+	if _, summary := queue.PendingPods(); !strings.Contains(summary, "unschedulablePods:1") {
+		t.Fatalf("queue = %s, want the pod still held", summary)
+	}
+
+	// The running pod finishes: the next refresh activates the held pod
+	tracker.podLister = testutil.NewFakePodLister([]*v1.Pod{held})
+	tracker.refresh()
+	if _, summary := queue.PendingPods(); !strings.Contains(summary, "activeQ:1") {
+		t.Fatalf("queue = %s, want the held pod active", summary)
+	}
+	if _, err := queue.Pop(logger); err != nil {
+		t.Fatalf("Pop() error = %v", err)
+	}
+
+	// Raising the budget activates the held pod without waiting for a refresh
+	tracker.podLister = testutil.NewFakePodLister([]*v1.Pod{running})
+	tracker.refresh()
+	hold("held-2")
+	tracker.onBudgetChange(budgetConfigMap("budgets: [{tenant: team-b, perHour: 20, overBudget: hold}]"))
+	if _, summary := queue.PendingPods(); !strings.Contains(summary, "activeQ:1") {
+		t.Fatalf("queue = %s, want the held pod active after the budget change", summary)
+	}
+}
+
+func TestOverBudgetTenants(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("h100", map[string]string{LabelHardwareTier: TierPremium, LabelGPUModel: "H100"}, nil),
+		testutil.MakeNode("t4", map[string]string{LabelHardwareTier: TierEconomy, LabelGPUModel: "T4"}, nil),
+		testutil.MakeNode("unpriced", map[string]string{LabelHardwareTier: TierPremium}, nil),
+	}
+	tracker := &budgetTracker{
+		podLister: testutil.NewFakePodLister([]*v1.Pod{
+			gpuPodIn("a", "team-a", "h100", "4"), // 14.00/h of 10: steered
+			gpuPodIn("b", "team-b", "h100", "2"), // 7.00/h of 5: held
+		}),
+		nodeLister: testutil.NewFakeNodeLister(nodes),
+	}
+	tracker.onBudgetChange(budgetConfigMap(testBudgets))
+	tracker.refresh()
+	plugin := &TenantHardwareAffinity{pricePerformanceWeight: DefaultPricePerformanceWeight, budgets: tracker}
+	state := framework.NewCycleState()
+
+	// Held tenants wait in the queue; steered ones and pods without GPUs are let through
+	if _, status := plugin.PreFilter(context.Background(), state, gpuPodIn("held", "team-b", "", "1"), nil); status.Code() != fwk.UnschedulableAndUnresolvable {
+		t.Errorf("PreFilter(team-b) = %v, want UnschedulableAndUnresolvable", status)
+	}
+	if _, status := plugin.PreFilter(context.Background(), state, gpuPodIn("steered", "team-a", "", "1"), nil); !status.IsSuccess() {
+		t.Errorf("PreFilter(team-a) = %v, want Success", status)
+	}
+	cpuPod := testutil.MakePod("cpu", "team-b", "", v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}, nil, nil)
+	if _, status := plugin.PreFilter(context.Background(), state, cpuPod, nil); status.Code() != fwk.Skip {
+		t.Errorf("PreFilter(cpu pod) = %v, want Skip", status)
+	}
+
+	// A high-priority pod of a steered tenant prefers the cheap T4 over the H100
+	pod := gpuPodIn("steered", "team-a", "", "1")
+	pod.Annotations = map[string]string{AnnotationPriorityTier: PriorityHigh}
+	scores := make(map[string]int64)
+	for _, node := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		score, status := plugin.Score(context.Background(), state, pod, nodeInfo)
+		if !status.IsSuccess() {
+			t.Fatalf("Score(%s) = %v", node.Name, status)
+		}
+		scores[node.Name] = score
+	}
+	if scores["t4"] <= scores["h100"] {
+		t.Errorf("over-budget scores = %v, want t4 above h100", scores)
+	}
+	// A node without a price doesn't keep its premium tier match
+	if scores["unpriced"] != ScoreNoHardwareInfo || scores["unpriced"] >= scores["t4"] {
+		t.Errorf("over-budget scores = %v, want unpriced at %d, below t4", scores, ScoreNoHardwareInfo)
+	}
+}
+
+func TestRunWaitsForCacheSync(t *testing.T) {
+	var synced atomic.Bool
+	tracker := &budgetTracker{
+		podLister:  testutil.NewFakePodLister([]*v1.Pod{gpuPodIn("a", "team-a", "h100", "2")}),
+		nodeLister: testutil.NewFakeNodeLister([]*v1.Node{testutil.MakeNode("h100", map[string]string{LabelGPUModel: "H100"}, nil)}),
+		synced:     []cache.InformerSynced{synced.Load},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracker.run(ctx, time.Hour)
+
+	time.Sleep(300 * time.Millisecond)
+	if tracker.spend.Load() != nil {
+		t.Fatal("spend refreshed before the informer caches synced")
+	}
+
+	synced.Store(true)
+	deadline := time.Now().Add(5 * time.Second)
+	for tracker.spend.Load() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("spend not refreshed after the informer caches synced")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := (*tracker.spend.Load())["team-a"]; got != 7.00 {
+		t.Errorf("team-a spend = %.2f, want 7.00", got)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/gpucatalog"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+)
+
+// Price-performance:
+//   - A node's price per GPU-hour is its hardware.kubenexus.io/cost-hour label, or the
+//     catalog costPerHour of its GPU model
+//   - Performance is relative per-GPU throughput by hardware tier, which differs by
+//     workload type: large training runs gain most from premium hardware, sandboxes and
+//     data preparation barely at all
+//   - A node's price-performance is scored against the best in the catalog for the
+//     workload type, so the most throughput per unit of cost scores 100
+
+// DefaultPricePerformanceWeight is the share, in percent, of the score taken from
+// price-performance when the pod's workload type is known
+const DefaultPricePerformanceWeight = 40
+
+// tierPerformance is relative per-GPU throughput by hardware tier, premium = 1.0
+type tierPerformance map[string]float64
+
+var (
+	// performanceCompute suits workloads bound by GPU compute and interconnect
+	performanceCompute = tierPerformance{TierPremium: 1.0, TierStandard: 0.45, TierEconomy: 0.2}
+	// performanceServing suits inference and batch, which run well on older GPUs
+	performanceServing = tierPerformance{TierPremium: 1.0, TierStandard: 0.6, TierEconomy: 0.45}
+	// performanceLight suits workloads that use a fraction of any GPU
+	performanceLight = tierPerformance{TierPremium: 1.0, TierStandard: 0.9, TierEconomy: 0.8}
+)
+
+// workloadPerformance returns the performance model of a workload type, or nil when
+// the type is unknown and price-performance does not apply
+func workloadPerformance(workload profileclassifier.WorkloadType) tierPerformance {
+	switch workload {
+	case profileclassifier.WorkloadTraining, profileclassifier.WorkloadFineTuning, profileclassifier.WorkloadDistributedInference:
+		return performanceCompute
+	case profileclassifier.WorkloadInference, profileclassifier.WorkloadService, profileclassifier.WorkloadBatch:
+		return performanceServing
+	case profileclassifier.WorkloadInteractive, profileclassifier.WorkloadDataPrep, profileclassifier.WorkloadSandbox:
+		return performanceLight
+	default:
+		return nil
+	}
+}
+
+// nodeCostPerGPUHour returns the node's price per GPU-hour: the cost-hour label, else
+// the catalog price of its GPU model
+func nodeCostPerGPUHour(node *v1.Node) (float64, bool) {
+	if value, ok := node.Labels[LabelCostPerHour]; ok {
+		if cost, err := strconv.ParseFloat(value, 64); err == nil && cost > 0 {
+			return cost, true
+		}
+	}
+	if gpuModel, ok := node.Labels[LabelGPUModel]; ok {
+		if model, ok := gpucatalog.Get().LookupModel(gpuModel); ok && model.CostPerHour > 0 {
+			return model.CostPerHour, true
+		}
+	}
+	return 0, false
+}
+
+// pricePerformanceScore scores throughput per unit of cost on a node of the tier and
+// price, relative to the best catalog model for the workload
+func pricePerformanceScore(perf tierPerformance, tier string, cost float64) (int64, bool) {
+	if perf == nil || perf[tier] == 0 || cost <= 0 {
+		return 0, false
+	}
+	best := 0.0
+	for _, model := range gpucatalog.Get().Models() {
+		if model.CostPerHour > 0 {
+			best = max(best, perf[model.Tier]/model.CostPerHour)
+		}
+	}
+	if best == 0 {
+		return 0, false
+	}
+	return min(int64(100*perf[tier]/cost/best), 100), true
+}
+
+// cheapnessScore scores a node's price per GPU-hour against the cheapest catalog model,
+// steering over-budget tenants to the cheapest hardware that fits
+func cheapnessScore(cost float64) int64 {
+	cheapest := 0.0
+	for _, model := range gpucatalog.Get().Models() {
+		if model.CostPerHour > 0 && (cheapest == 0 || model.CostPerHour < cheapest) {
+			cheapest = model.CostPerHour
+		}
+	}
+	if cheapest == 0 || cost <= 0 {
+		return ScoreNoHardwareInfo
+	}
+	return min(int64(100*cheapest/cost), 100)
+}
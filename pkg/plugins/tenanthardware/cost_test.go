@@ -0,0 +1,87 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenanthardware
+
+import (
+	"testing"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+func TestNodeCostPerGPUHour(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+		wantOK bool
+	}{
+		{name: "label", labels: map[string]string{LabelCostPerHour: "2.75", LabelGPUModel: "H100"}, want: 2.75, wantOK: true},
+		{name: "catalog", labels: map[string]string{LabelGPUModel: "H100"}, want: 3.50, wantOK: true},
+		{name: "invalid label falls back", labels: map[string]string{LabelCostPerHour: "cheap", LabelGPUModel: "T4"}, want: 0.35, wantOK: true},
+		{name: "unknown", labels: map[string]string{LabelGPUModel: "unknown-gpu"}},
+		{name: "no labels"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nodeCostPerGPUHour(testutil.MakeNode("n", tt.labels, nil))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("nodeCostPerGPUHour() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPricePerformanceScore(t *testing.T) {
+	score := func(workload profileclassifier.WorkloadType, tier string, cost float64) int64 {
+		t.Helper()
+		value, ok := pricePerformanceScore(workloadPerformance(workload), tier, cost)
+		if !ok || value < 0 || value > 100 {
+			t.Fatalf("pricePerformanceScore(%s, %s, %v) = %d, %v", workload, tier, cost, value, ok)
+		}
+		return value
+	}
+
+	// Training gets more per unit of cost from an H100 than an A10; a sandbox does not
+	h100, a10 := 3.50, 0.75
+	if score(profileclassifier.WorkloadTraining, TierPremium, h100) <= score(profileclassifier.WorkloadTraining, TierEconomy, a10) {
+		t.Error("training: want premium H100 above economy A10")
+	}
+	if score(profileclassifier.WorkloadSandbox, TierPremium, h100) >= score(profileclassifier.WorkloadSandbox, TierEconomy, a10) {
+		t.Error("sandbox: want economy A10 above premium H100")
+	}
+	// A cheaper price for the same tier always scores higher
+	if score(profileclassifier.WorkloadInference, TierStandard, 1.00) <= score(profileclassifier.WorkloadInference, TierStandard, 2.00) {
+		t.Error("inference: want the cheaper standard node higher")
+	}
+
+	if _, ok := pricePerformanceScore(workloadPerformance(profileclassifier.WorkloadUnknown), TierPremium, h100); ok {
+		t.Error("pricePerformanceScore() for an unknown workload type applied")
+	}
+	if _, ok := pricePerformanceScore(workloadPerformance(profileclassifier.WorkloadTraining), "", h100); ok {
+		t.Error("pricePerformanceScore() for an unknown tier applied")
+	}
+}
+
+func TestCheapnessScore(t *testing.T) {
+	if cheap, costly := cheapnessScore(0.25), cheapnessScore(4.50); cheap != 100 || costly >= cheap {
+		t.Errorf("cheapnessScore() = %d, %d, want 100 for the cheapest model and less for H200", cheap, costly)
+	}
+	if got := cheapnessScore(0); got != ScoreNoHardwareInfo {
+		t.Errorf("cheapnessScore(0) = %d, want %d", got, ScoreNoHardwareInfo)
+	}
+}
@@ -28,6 +28,12 @@ limitations under the License.
 //   - Medium-priority tenants → Standard hardware (A100, A100X)
 //   - Low-priority tenants → Economy hardware (L40, T4)
 //
+// COST AWARENESS:
+// Pods of a known workload type are also scored by price-performance: throughput per
+// unit of the node's price per GPU-hour. Tenants with a budget (kubenexus-tenant-budgets
+// ConfigMap) whose bound and reserved pods have reached it are steered to the cheapest
+// hardware or held in the queue. See budget.go and cost.go.
+//
 // INTEGRATION WITH KUEUE:
 // Can optionally watch Kueue LocalQueues to see which tenants are in which queues,
 // enabling even smarter placement based on upcoming demand.
@@ -36,14 +42,21 @@ package tenanthardware
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
 	framework "k8s.io/kube-scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/gpucatalog"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/resourcefragmentation"
+	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
 )
 
 const (
@@ -53,7 +66,10 @@ const (
 	LabelHardwareTier  = "hardware.kubenexus.io/tier"      // "premium", "standard", "economy"
 	LabelGPUModel      = "gpu.kubenexus.io/model"          // "H100", "A100", "L40"
 	LabelGPUGeneration = "hardware.kubenexus.io/gpu-gen"   // "hopper", "ampere", "ada"
-	LabelCostPerHour   = "hardware.kubenexus.io/cost-hour" // "10.00", "5.00", "2.00"
+	LabelCostPerHour   = "hardware.kubenexus.io/cost-hour" // price per GPU-hour: "4.50", "1.60", "0.70"
+
+	// AnnotationPriorityTier assigns a pod's tenant tier explicitly
+	AnnotationPriorityTier = "scheduling.kubenexus.io/priority-tier"
 
 	// Priority class tiers (standard K8s)
 	PriorityHigh   = "high-priority"
@@ -74,6 +90,24 @@ const (
 
 type TenantHardwareAffinity struct {
 	handle framework.Handle
+
+	// pricePerformanceWeight is the percent of the score from price-performance
+	pricePerformanceWeight int64
+	budgets                *budgetTracker
+	affinity               *affinityMatrix
+}
+
+// TenantHardwareAffinityArgs configures TenantHardwareAffinityScore
+type TenantHardwareAffinityArgs struct {
+	// PricePerformanceWeight is the percent (0-100) of the score taken from
+	// price-performance for pods of a known workload type; 0 disables it
+	PricePerformanceWeight *int64 `json:"pricePerformanceWeight,omitempty"`
+
+	// SpendIntervalSeconds is how often tenant spend is recomputed
+	SpendIntervalSeconds int `json:"spendIntervalSeconds,omitempty"`
+
+	// Affinity replaces the built-in tenant-to-hardware matrix
+	Affinity *AffinityMatrix `json:"affinity,omitempty"`
 }
 
 // HardwareTier represents a classification of hardware
@@ -84,6 +118,8 @@ type HardwareTier struct {
 }
 
 var _ framework.ScorePlugin = &TenantHardwareAffinity{}
+var _ framework.PreFilterPlugin = &TenantHardwareAffinity{}
+var _ framework.ReservePlugin = &TenantHardwareAffinity{}
 
 func (tha *TenantHardwareAffinity) Name() string {
 	return Name
@@ -105,10 +141,31 @@ func (tha *TenantHardwareAffinity) Score(ctx context.Context, state framework.Cy
 	// 3. Calculate match score
 	score := tha.calculateAffinityScore(tenantPriority, hardwareTier, node)
 
+	// 4. Weigh in cost: over-budget tenants go to the cheapest hardware, other pods
+	// of a known workload type to the best price-performance
+	tenant, workload := podTenant(state, pod), podWorkloadType(state)
+	if resourcefragmentation.GetGPURequest(pod) > 0 {
+		cost, priced := nodeCostPerGPUHour(node)
+		if budget, spent, over := tha.budgets.overBudget(tenant); over {
+			// Unpriced nodes score neutral, so their tier affinity can't outrank
+			// hardware known to be cheap
+			score = ScoreNoHardwareInfo
+			if priced {
+				score = cheapnessScore(cost)
+			}
+			klog.V(4).InfoS("Steering over-budget tenant to cheaper hardware",
+				"pod", klog.KObj(pod), "tenant", tenant, "spend", spent, "budget", budget.PerHour,
+				"node", node.Name, "costPerGPUHour", cost, "score", score)
+		} else if value, ok := pricePerformanceScore(workloadPerformance(workload), performanceTier(node), cost); ok && priced {
+			score = (score*(100-tha.pricePerformanceWeight) + value*tha.pricePerformanceWeight) / 100
+		}
+	}
+
 	klog.V(5).InfoS("Tenant-hardware affinity scoring",
 		"pod", pod.Name,
 		"namespace", pod.Namespace,
 		"tenantPriority", tenantPriority,
+		"workloadType", workload,
 		"node", node.Name,
 		"hardwareTier", hardwareTier,
 		"score", score)
@@ -116,6 +173,61 @@ func (tha *TenantHardwareAffinity) Score(ctx context.Context, state framework.Cy
 	return score, framework.NewStatus(framework.Success)
 }
 
+// PreFilter holds GPU pods of tenants over a budget whose overBudget action is hold,
+// and counts the pods of over-budget tenants that are steered instead
+func (tha *TenantHardwareAffinity) PreFilter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodes []framework.NodeInfo) (*framework.PreFilterResult, *framework.Status) {
+	if resourcefragmentation.GetGPURequest(pod) == 0 {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	tenant := podTenant(state, pod)
+	budget, spent, over := tha.budgets.overBudget(tenant)
+	if !over {
+		return nil, framework.NewStatus(framework.Success)
+	}
+
+	schedulermetrics.TenantBudgetDecisions.WithLabelValues(tenant, string(budget.OverBudget)).Inc()
+	if budget.OverBudget != OverBudgetHold {
+		return nil, framework.NewStatus(framework.Success)
+	}
+	klog.V(3).InfoS("Holding pod of over-budget tenant", "pod", klog.KObj(pod), "tenant", tenant, "spend", spent, "budget", budget.PerHour)
+	tha.budgets.hold(pod, tenant)
+	return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable,
+		fmt.Sprintf("tenant %q GPU spend %.2f/h has reached its budget of %.2f/h", tenant, spent, budget.PerHour))
+}
+
+// PreFilterExtensions returns nil
+func (tha *TenantHardwareAffinity) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Reserve counts the pod's GPU spend against its tenant's budget until the pod is seen
+// bound, so pods placed between spend refreshes are not missed
+func (tha *TenantHardwareAffinity) Reserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	tha.budgets.reserve(pod, podTenant(state, pod), nodeName)
+	return framework.NewStatus(framework.Success)
+}
+
+// Unreserve stops counting the spend added at Reserve
+func (tha *TenantHardwareAffinity) Unreserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	tha.budgets.unreserve(pod)
+}
+
+// podTenant returns the tenant ProfileClassifier assigned the pod, else its namespace
+func podTenant(state framework.CycleState, pod *v1.Pod) string {
+	if profile, err := profileclassifier.GetProfile(state); err == nil && profile != nil && profile.TenantName != "" {
+		return profile.TenantName
+	}
+	return pod.Namespace
+}
+
+// podWorkloadType returns the workload type ProfileClassifier assigned the pod, if any
+func podWorkloadType(state framework.CycleState) profileclassifier.WorkloadType {
+	if profile, err := profileclassifier.GetProfile(state); err == nil && profile != nil {
+		return profile.WorkloadType
+	}
+	return profileclassifier.WorkloadUnknown
+}
+
 func (tha *TenantHardwareAffinity) ScoreExtensions() framework.ScoreExtensions {
 	return nil
 }
@@ -123,11 +235,19 @@ func (tha *TenantHardwareAffinity) ScoreExtensions() framework.ScoreExtensions {
 // getTenantPriorityFromProfile tries to get tenant classification from ProfileClassifier,
 // falls back to local classification if ProfileClassifier is not enabled
 func (tha *TenantHardwareAffinity) getTenantPriorityFromProfile(state framework.CycleState, pod *v1.Pod) string {
+	// An explicit tier annotation overrides any classification
+	if priority, ok := pod.Annotations[AnnotationPriorityTier]; ok {
+		return priority
+	}
+
 	// Try to get profile from ProfileClassifier (preferred)
 	profile, err := profileclassifier.GetProfile(state)
 	if err == nil && profile != nil {
-		// Map ProfileClassifier tenant tiers to our priority levels
-		priority := tha.mapTenantTierToPriority(profile.TenantTier)
+		// Map the tenant, else its ProfileClassifier tier, to a tenant tier
+		priority, ok := tha.matrix().tenantTierForTenant(profile.TenantName)
+		if !ok {
+			priority = tha.mapTenantTierToPriority(profile.TenantTier)
+		}
 		klog.V(4).InfoS("Using tenant classification from ProfileClassifier",
 			"pod", pod.Name,
 			"namespace", pod.Namespace,
@@ -144,45 +264,33 @@ func (tha *TenantHardwareAffinity) getTenantPriorityFromProfile(state framework.
 	return tha.getTenantPriority(pod)
 }
 
-// mapTenantTierToPriority maps ProfileClassifier tenant tiers to priority levels
+// mapTenantTierToPriority maps ProfileClassifier tenant tiers to tenant tiers of the matrix
 func (tha *TenantHardwareAffinity) mapTenantTierToPriority(tier profileclassifier.TenantTier) string {
-	switch tier {
-	case profileclassifier.TierGold:
-		return PriorityHigh
-	case profileclassifier.TierSilver:
-		return PriorityMedium
-	case profileclassifier.TierBronze:
-		return PriorityLow
-	default:
-		return PriorityMedium
-	}
+	return tha.matrix().tenantTierForProfile(tier)
 }
 
-// getTenantPriority determines the priority tier of a pod/tenant (fallback method)
+// getTenantPriority determines the tenant tier of a pod (fallback method)
 func (tha *TenantHardwareAffinity) getTenantPriority(pod *v1.Pod) string {
 	// Check pod annotations for explicit priority override (highest priority)
-	if priority, ok := pod.Annotations["scheduling.kubenexus.io/priority-tier"]; ok {
+	if priority, ok := pod.Annotations[AnnotationPriorityTier]; ok {
 		return priority
 	}
 
+	// Check the tiers listing the tenant, named after the namespace here
+	if tier, ok := tha.matrix().tenantTierForTenant(pod.Namespace); ok {
+		return tier
+	}
+
 	// Check explicit priority class
 	if pod.Spec.PriorityClassName != "" {
-		// Map common priority class patterns
-		priorityClass := strings.ToLower(pod.Spec.PriorityClassName)
-		if strings.Contains(priorityClass, "high") || strings.Contains(priorityClass, "critical") {
-			return PriorityHigh
-		}
-		if strings.Contains(priorityClass, "medium") || strings.Contains(priorityClass, "normal") {
-			return PriorityMedium
-		}
-		if strings.Contains(priorityClass, "low") || strings.Contains(priorityClass, "best-effort") {
-			return PriorityLow
+		if tier, ok := tha.matrix().tenantTierForPriorityClass(pod.Spec.PriorityClassName); ok {
+			return tier
 		}
 	}
 
-	// Default to medium priority if no explicit classification found
+	// Fall back to the default tier if no explicit classification found
 	// Cluster admins should use annotations or priority classes for explicit control
-	return PriorityMedium
+	return tha.matrix().defaultTenantTier
 }
 
 // getHardwareTier determines the hardware tier of a node
@@ -194,113 +302,103 @@ func (tha *TenantHardwareAffinity) getHardwareTier(node *v1.Node) string {
 
 	// Infer from GPU model
 	if gpuModel, ok := node.Labels[LabelGPUModel]; ok {
-		return tha.inferTierFromGPUModel(gpuModel)
+		return tha.matrix().hardwareTierForModel(gpuModel)
 	}
 
 	// No tier information
 	return ""
 }
 
-// inferTierFromGPUModel infers hardware tier from GPU model name
-func (tha *TenantHardwareAffinity) inferTierFromGPUModel(gpuModel string) string {
-	model := strings.ToUpper(gpuModel)
-
-	// Premium tier: Latest generation high-end GPUs
-	premiumModels := []string{"H100", "H200", "A100-80GB", "MI300"}
-	for _, premium := range premiumModels {
-		if strings.Contains(model, premium) {
-			return TierPremium
-		}
-	}
-
-	// Standard tier: Previous generation high-end or current mid-range
-	standardModels := []string{"A100", "A40", "A6000", "MI250"}
-	for _, standard := range standardModels {
-		if strings.Contains(model, standard) {
-			return TierStandard
-		}
+// inferTierFromGPUModel looks up the hardware tier of a GPU model in the GPU catalog
+func inferTierFromGPUModel(gpuModel string) string {
+	if model, ok := gpucatalog.Get().LookupModel(gpuModel); ok {
+		return model.Tier
 	}
+	return ""
+}
 
-	// Economy tier: Cost-effective GPUs
-	economyModels := []string{"L40", "L4", "T4", "A10", "A16"}
-	for _, economy := range economyModels {
-		if strings.Contains(model, economy) {
-			return TierEconomy
-		}
+// performanceTier returns the catalog tier (premium, standard or economy) that
+// price-performance is estimated from, whatever tiers the matrix uses
+func performanceTier(node *v1.Node) string {
+	switch tier := node.Labels[LabelHardwareTier]; tier {
+	case TierPremium, TierStandard, TierEconomy:
+		return tier
 	}
-
-	// Unknown GPU model
-	return ""
+	return inferTierFromGPUModel(node.Labels[LabelGPUModel])
 }
 
-// calculateAffinityScore calculates the affinity score based on tenant-hardware match
+// calculateAffinityScore looks up the score of the tenant tier on the hardware tier
 func (tha *TenantHardwareAffinity) calculateAffinityScore(tenantPriority, hardwareTier string, node *v1.Node) int64 {
 	// If no hardware tier info, return neutral score
 	if hardwareTier == "" {
 		return ScoreNoHardwareInfo
 	}
 
-	// Perfect match matrix
-	perfectMatches := map[string]string{
-		PriorityHigh:   TierPremium,
-		PriorityMedium: TierStandard,
-		PriorityLow:    TierEconomy,
-	}
+	score, listed := tha.matrix().score(tenantPriority, hardwareTier)
+	klog.V(4).InfoS("Tenant-hardware affinity",
+		"tenantPriority", tenantPriority,
+		"hardwareTier", hardwareTier,
+		"listed", listed,
+		"node", node.Name,
+		"score", score)
+	return score
+}
 
-	// Check for perfect match
-	if perfectMatches[tenantPriority] == hardwareTier {
-		klog.V(4).InfoS("Perfect tenant-hardware match",
-			"tenantPriority", tenantPriority,
-			"hardwareTier", hardwareTier,
-			"node", node.Name)
-		return ScorePerfectMatch
+// matrix returns the configured affinity matrix, or the built-in one
+func (tha *TenantHardwareAffinity) matrix() *affinityMatrix {
+	if tha.affinity != nil {
+		return tha.affinity
 	}
+	return defaultAffinity
+}
 
-	// Acceptable matches (can use but not ideal)
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args := TenantHardwareAffinityArgs{}
+	if err := frameworkruntime.DecodeInto(obj, &args); err != nil {
+		return nil, fmt.Errorf("failed to decode %s args: %w", Name, err)
+	}
+	weight := int64(DefaultPricePerformanceWeight)
+	if args.PricePerformanceWeight != nil {
+		weight = min(max(*args.PricePerformanceWeight, 0), 100)
+	}
+	interval := DefaultSpendInterval
+	if args.SpendIntervalSeconds > 0 {
+		interval = time.Duration(args.SpendIntervalSeconds) * time.Second
+	}
 
-	// High-priority can use any tier (but prefers premium)
-	if tenantPriority == PriorityHigh {
-		if hardwareTier == TierStandard {
-			return ScoreAcceptableMatch
-		}
-		if hardwareTier == TierEconomy {
-			return ScoreAcceptableMatch - 10 // Slightly worse
+	tha := &TenantHardwareAffinity{
+		handle:                 handle,
+		pricePerformanceWeight: weight,
+	}
+	if args.Affinity != nil {
+		affinity, err := args.Affinity.compile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s affinity matrix: %w", Name, err)
 		}
+		tha.affinity = affinity
 	}
 
-	// Medium-priority can use standard or economy (but not premium)
-	if tenantPriority == PriorityMedium {
-		if hardwareTier == TierEconomy {
-			return ScoreAcceptableMatch
-		}
-		// Heavy penalty: Don't waste premium on medium-priority
-		if hardwareTier == TierPremium {
-			klog.V(4).InfoS("Penalizing premium hardware for medium-priority tenant",
-				"tenantPriority", tenantPriority,
-				"hardwareTier", hardwareTier,
-				"node", node.Name)
-			return ScoreMismatchPenalty
+	// Hardware tiers come from the shared GPU catalog, hot-reloaded from its ConfigMap
+	if handle != nil && handle.ClientSet() != nil {
+		if err := gpucatalog.Watch(ctx, handle.ClientSet()); err != nil {
+			return nil, fmt.Errorf("failed to watch GPU catalog: %w", err)
 		}
-	}
 
-	// Low-priority should NOT use premium or standard
-	if tenantPriority == PriorityLow {
-		// Very heavy penalty: Preserve better hardware for higher-priority work
-		if hardwareTier == TierPremium || hardwareTier == TierStandard {
-			klog.V(4).InfoS("Penalizing premium/standard hardware for low-priority tenant",
-				"tenantPriority", tenantPriority,
-				"hardwareTier", hardwareTier,
-				"node", node.Name)
-			return ScoreMismatchPenalty
+		podInformer := handle.SharedInformerFactory().Core().V1().Pods()
+		nodeInformer := handle.SharedInformerFactory().Core().V1().Nodes()
+		tha.budgets = &budgetTracker{
+			podLister:  podInformer.Lister(),
+			nodeLister: nodeInformer.Lister(),
+			synced:     []cache.InformerSynced{podInformer.Informer().HasSynced, nodeInformer.Informer().HasSynced},
+			activate: func(pods map[string]*v1.Pod) {
+				handle.Activate(klog.FromContext(ctx), pods)
+			},
+		}
+		if err := utils.WatchConfigMap(ctx, handle.ClientSet(), utils.DefaultConfigNamespace, BudgetConfigMapName, tha.budgets.onBudgetChange); err != nil {
+			return nil, fmt.Errorf("failed to watch tenant budgets: %w", err)
 		}
+		go tha.budgets.run(ctx, interval)
 	}
 
-	// Default: acceptable but not perfect
-	return ScoreAcceptableMatch
-}
-
-func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
-	return &TenantHardwareAffinity{
-		handle: handle,
-	}, nil
+	return tha, nil
 }
@@ -33,8 +33,7 @@ import (
 
 const (
 	// Test constants
-	ResourceGPU            = v1.ResourceName("nvidia.com/gpu")
-	AnnotationPriorityTier = "scheduling.kubenexus.io/priority-tier"
+	ResourceGPU = v1.ResourceName("nvidia.com/gpu")
 )
 
 func TestName(t *testing.T) {
@@ -124,8 +123,6 @@ func TestGetTenantPriority(t *testing.T) {
 }
 
 func TestInferTierFromGPUModel(t *testing.T) {
-	plugin := &TenantHardwareAffinity{}
-
 	tests := []struct {
 		name         string
 		gpuModel     string
@@ -175,7 +172,7 @@ func TestInferTierFromGPUModel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tier := plugin.inferTierFromGPUModel(tt.gpuModel)
+			tier := inferTierFromGPUModel(tt.gpuModel)
 			if tier != tt.expectedTier {
 				t.Errorf("Expected tier %s for GPU %s, got %s", tt.expectedTier, tt.gpuModel, tier)
 			}
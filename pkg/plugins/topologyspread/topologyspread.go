@@ -26,6 +26,7 @@ import (
 	framework "k8s.io/kube-scheduler/framework"
 
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/snapshot"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/workload"
 )
 
@@ -34,6 +35,7 @@ import (
 // For batch workloads, topology is less critical (co-location is more important).
 type TopologySpreadScorePlugin struct {
 	handle framework.Handle
+	index  *snapshot.Builder
 }
 
 var _ framework.ScorePlugin = &TopologySpreadScorePlugin{}
@@ -59,7 +61,9 @@ func (t *TopologySpreadScorePlugin) Name() string {
 //
 // WORKLOAD-AWARE SPREADING:
 //   - Service/Inference workloads: Strong zone spreading for high availability
-//   - Batch/Training workloads: Neutral score (co-location more important for performance)
+//   - Batch/Training/Fine-tuning workloads: Neutral score (co-location more important for performance)
+//   - Data-prep and sandbox workloads: Neutral score (short-lived, no HA requirement)
+//   - Distributed inference: Keep a LeaderWorkerSet group in one zone, spread groups across zones
 func (t *TopologySpreadScorePlugin) Score(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo) (int64, *framework.Status) {
 	node := nodeInfo.Node()
 	if node == nil {
@@ -70,7 +74,8 @@ func (t *TopologySpreadScorePlugin) Score(ctx context.Context, state framework.C
 	workloadTypeFromProfile := t.getWorkloadTypeFromProfile(state, pod)
 
 	// For batch/training workloads, topology is less critical (return neutral score)
-	if workloadTypeFromProfile == "batch" || workloadTypeFromProfile == "training" {
+	switch workloadTypeFromProfile {
+	case "batch", "training", "fine-tuning", "data-prep", "sandbox":
 		return MaxScore / 2, framework.NewStatus(framework.Success, "")
 	}
 
@@ -81,8 +86,20 @@ func (t *TopologySpreadScorePlugin) Score(ctx context.Context, state framework.C
 		return MaxScore / 2, framework.NewStatus(framework.Success, "")
 	}
 
+	// Distributed inference: a leader and its workers exchange activations on
+	// every token, so once part of the group is placed the rest must follow it.
+	// The first pod of a group falls through to normal spreading.
+	if workloadTypeFromProfile == "distributed-inference" {
+		if groupZones := t.lwsGroupZones(state, pod); len(groupZones) > 0 {
+			if groupZones[zone] {
+				return MaxScore, framework.NewStatus(framework.Success, "")
+			}
+			return 0, framework.NewStatus(framework.Success, "")
+		}
+	}
+
 	// Count existing pods in each zone
-	zoneDistribution := t.calculateZoneDistribution(pod)
+	zoneDistribution := t.calculateZoneDistribution(state, pod)
 
 	// Calculate score: prefer zones with fewer pods
 	podsInThisZone := zoneDistribution[zone]
@@ -109,7 +126,8 @@ func (t *TopologySpreadScorePlugin) Score(ctx context.Context, state framework.C
 // Integration with ProfileClassifier:
 //   - Uses profile.WorkloadType for centralized classification
 //   - Falls back to workload.ClassifyPod() if ProfileClassifier unavailable
-//   - Returns normalized string: "training", "inference", "batch", "service", "unknown"
+//   - Returns normalized string: "training", "inference", "batch", "service", "fine-tuning",
+//     "distributed-inference", "data-prep", "sandbox", "interactive", "unknown"
 func (t *TopologySpreadScorePlugin) getWorkloadTypeFromProfile(state framework.CycleState, pod *v1.Pod) string {
 	// Try ProfileClassifier first
 	profile, err := profileclassifier.GetProfile(state)
@@ -129,9 +147,57 @@ func (t *TopologySpreadScorePlugin) getWorkloadTypeFromProfile(state framework.C
 	}
 }
 
-// calculateZoneDistribution counts nodes per zone as a proxy for pod distribution
-// This provides good spreading behavior without needing to iterate all pods
-func (t *TopologySpreadScorePlugin) calculateZoneDistribution(pod *v1.Pod) map[string]int {
+// lwsGroupZones returns the zones hosting other pods of the pod's LeaderWorkerSet group
+func (t *TopologySpreadScorePlugin) lwsGroupZones(state framework.CycleState, pod *v1.Pod) map[string]bool {
+	zones := make(map[string]bool)
+	lwsName, ok := pod.Labels[profileclassifier.LeaderWorkerSetNameLabel]
+	if !ok {
+		return zones
+	}
+	groupIndex := pod.Labels[profileclassifier.LeaderWorkerSetGroupIndexLabel]
+
+	if idx := t.index.Get(state); idx != nil {
+		for zone := range idx.LWSGroupZones(pod.Namespace, lwsName, groupIndex) {
+			zones[zone] = true
+		}
+		return zones
+	}
+
+	nodeInfoList, err := t.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return zones
+	}
+
+	for _, nodeInfo := range nodeInfoList {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		zone, hasZone := node.Labels[ZoneLabel]
+		if !hasZone {
+			continue
+		}
+		for _, podInfo := range nodeInfo.GetPods() {
+			p := podInfo.GetPod()
+			if p.Namespace == pod.Namespace && p.Name != pod.Name &&
+				p.Labels[profileclassifier.LeaderWorkerSetNameLabel] == lwsName &&
+				p.Labels[profileclassifier.LeaderWorkerSetGroupIndexLabel] == groupIndex {
+				zones[zone] = true
+				break
+			}
+		}
+	}
+
+	return zones
+}
+
+// calculateZoneDistribution counts pods per zone, read from the cycle's snapshot index
+// or, without one, by scanning the snapshot. Zones without pods are left out.
+func (t *TopologySpreadScorePlugin) calculateZoneDistribution(state framework.CycleState, pod *v1.Pod) map[string]int {
+	if idx := t.index.Get(state); idx != nil {
+		return idx.ZonePods()
+	}
+
 	distribution := make(map[string]int)
 
 	// Get all nodes
@@ -140,22 +206,20 @@ func (t *TopologySpreadScorePlugin) calculateZoneDistribution(pod *v1.Pod) map[s
 		return distribution
 	}
 
-	// Count nodes per zone
-	// Nodes with more pods will naturally have higher "weight"
 	for _, nodeInfo := range nodeInfoList {
 		node := nodeInfo.Node()
 		if node == nil {
 			continue
 		}
 
-		zone, hasZone := node.Labels[ZoneLabel]
-		if !hasZone {
+		zone := node.Labels[ZoneLabel]
+		if zone == "" {
 			continue
 		}
 
-		// Simple approach: count nodes per zone
-		// Prefer zones with fewer nodes to spread workload
-		distribution[zone]++
+		if pods := len(nodeInfo.GetPods()); pods > 0 {
+			distribution[zone] += pods
+		}
 	}
 
 	return distribution
@@ -170,5 +234,6 @@ func (t *TopologySpreadScorePlugin) ScoreExtensions() framework.ScoreExtensions
 func NewTopologySpreadScore(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	return &TopologySpreadScorePlugin{
 		handle: handle,
+		index:  snapshot.NewBuilder(handle),
 	}, nil
 }
@@ -19,7 +19,13 @@ package topologyspread
 import (
 	"testing"
 
-	framework "k8s.io/kube-scheduler/framework"
+	v1 "k8s.io/api/core/v1"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/snapshot"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
 )
 
 func TestName(t *testing.T) {
@@ -50,7 +56,7 @@ func TestConstants(t *testing.T) {
 		{
 			name: "MaxScore",
 			got:  MaxScore,
-			want: framework.MaxNodeScore,
+			want: fwk.MaxNodeScore,
 		},
 		{
 			name: "ZoneLabel",
@@ -67,3 +73,85 @@ func TestConstants(t *testing.T) {
 		})
 	}
 }
+
+func TestLWSGroupZones(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("node-a", map[string]string{ZoneLabel: "zone-a"}, nil),
+		testutil.MakeNode("node-b", map[string]string{ZoneLabel: "zone-b"}, nil),
+	}
+	groupLabels := map[string]string{
+		profileclassifier.LeaderWorkerSetNameLabel:       "llama",
+		profileclassifier.LeaderWorkerSetGroupIndexLabel: "0",
+	}
+	otherGroupLabels := map[string]string{
+		profileclassifier.LeaderWorkerSetNameLabel:       "llama",
+		profileclassifier.LeaderWorkerSetGroupIndexLabel: "1",
+	}
+	pods := []*v1.Pod{
+		testutil.MakePod("llama-0", "default", "node-a", nil, groupLabels, nil),
+		testutil.MakePod("llama-1", "default", "node-b", nil, otherGroupLabels, nil),
+	}
+
+	handle, err := testutil.NewTestFrameworkWithPods(pods, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	worker := testutil.MakePod("llama-0-1", "default", "", nil, groupLabels, nil)
+	leader := testutil.MakePod("llama-2", "default", "", nil, map[string]string{
+		profileclassifier.LeaderWorkerSetNameLabel:       "llama",
+		profileclassifier.LeaderWorkerSetGroupIndexLabel: "2",
+	}, nil)
+
+	// Scanning the snapshot and reading the cycle's snapshot index agree
+	for name, plugin := range map[string]*TopologySpreadScorePlugin{
+		"snapshot scan":  {handle: handle},
+		"snapshot index": {handle: handle, index: snapshot.NewBuilder(handle)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := framework.NewCycleState()
+			zones := plugin.lwsGroupZones(state, worker)
+			if len(zones) != 1 || !zones["zone-a"] {
+				t.Errorf("lwsGroupZones() = %v, want only zone-a", zones)
+			}
+			if zones := plugin.lwsGroupZones(state, leader); len(zones) != 0 {
+				t.Errorf("lwsGroupZones() for new group = %v, want empty", zones)
+			}
+		})
+	}
+}
+
+func TestCalculateZoneDistribution(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("node-a1", map[string]string{ZoneLabel: "zone-a"}, nil),
+		testutil.MakeNode("node-a2", map[string]string{ZoneLabel: "zone-a"}, nil),
+		testutil.MakeNode("node-b", map[string]string{ZoneLabel: "zone-b"}, nil),
+		testutil.MakeNode("node-c", map[string]string{ZoneLabel: "zone-c"}, nil),
+		testutil.MakeNode("no-zone", nil, nil),
+	}
+	pods := []*v1.Pod{
+		testutil.MakePod("a1-0", "default", "node-a1", nil, nil, nil),
+		testutil.MakePod("b-0", "default", "node-b", nil, nil, nil),
+		testutil.MakePod("b-1", "default", "node-b", nil, nil, nil),
+		testutil.MakePod("b-2", "default", "node-b", nil, nil, nil),
+		testutil.MakePod("no-zone-0", "default", "no-zone", nil, nil, nil),
+	}
+
+	handle, err := testutil.NewTestFrameworkWithPods(pods, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	pod := testutil.MakePod("new", "default", "", nil, nil, nil)
+
+	// Scanning the snapshot and reading the cycle's snapshot index agree
+	for name, plugin := range map[string]*TopologySpreadScorePlugin{
+		"snapshot scan":  {handle: handle},
+		"snapshot index": {handle: handle, index: snapshot.NewBuilder(handle)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := plugin.calculateZoneDistribution(framework.NewCycleState(), pod)
+			if len(got) != 2 || got["zone-a"] != 1 || got["zone-b"] != 3 {
+				t.Errorf("calculateZoneDistribution() = %v, want zone-a: 1, zone-b: 3", got)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	klog "k8s.io/klog/v2"
+	"k8s.io/kube-scheduler/framework"
 )
 
 // getGPUTopologyFromDRA extracts complete GPU topology from DRA ResourceSlices.
@@ -38,25 +39,10 @@ import (
 //   - vramPerGPU: Per-GPU VRAM capacity in bytes
 //   - devices: Slice of GPUDevice with full topology information
 //   - error: Non-nil if ResourceSlices couldn't be queried
-func (v *VRAMScheduler) getGPUTopologyFromDRA(ctx context.Context, node *v1.Node) (int64, []GPUDevice, error) {
-	// Use pre-initialized lister from struct (set once in New())
-	if v.resourceSliceLister == nil {
-		return 0, nil, fmt.Errorf("ResourceSlice lister not available")
-	}
-
-	// List all ResourceSlices and filter by node name
-	// Note: Field selectors are not supported by listers, so we filter after listing
-	allResourceSlices, err := v.resourceSliceLister.List(labels.Everything())
+func (v *VRAMScheduler) getGPUTopologyFromDRA(ctx context.Context, state framework.CycleState, node *v1.Node) (int64, []GPUDevice, error) {
+	resourceSlices, err := v.nodeResourceSlices(state, node.Name)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to list ResourceSlices: %w", err)
-	}
-
-	// Filter ResourceSlices for this specific node
-	var resourceSlices []*resourcev1.ResourceSlice
-	for _, slice := range allResourceSlices {
-		if slice.Spec.NodeName != nil && *slice.Spec.NodeName == node.Name {
-			resourceSlices = append(resourceSlices, slice)
-		}
+		return 0, nil, err
 	}
 
 	if len(resourceSlices) == 0 {
@@ -83,6 +69,10 @@ func (v *VRAMScheduler) getGPUTopologyFromDRA(ctx context.Context, node *v1.Node
 
 		// Extract GPU devices from this slice
 		for _, device := range slice.Spec.Devices {
+			if isMIGDevice(device) {
+				// MIG partitions are placed separately, see getNodeMIGDevices
+				continue
+			}
 			gpu := v.parseGPUDeviceFromDRA(device, slice.Spec.Driver)
 
 			if gpu.VRAM > 0 {
@@ -100,7 +90,8 @@ func (v *VRAMScheduler) getGPUTopologyFromDRA(ctx context.Context, node *v1.Node
 					"numaNode", gpu.NUMANode,
 					"nvlinkDomain", gpu.NVLinkDomain,
 					"nvlinkPeers", len(gpu.NVLinkPeers),
-					"pcieSwitch", gpu.PCIeSwitch)
+					"pcieSwitch", gpu.PCIeSwitch,
+					"rail", gpu.Rail)
 			}
 		}
 	}
@@ -117,6 +108,30 @@ func (v *VRAMScheduler) getGPUTopologyFromDRA(ctx context.Context, node *v1.Node
 	return vramPerGPU, gpuDevices, nil
 }
 
+// nodeResourceSlices returns the node's ResourceSlices from the cycle's snapshot index,
+// falling back to listing every slice when there is no index
+func (v *VRAMScheduler) nodeResourceSlices(state framework.CycleState, nodeName string) ([]*resourcev1.ResourceSlice, error) {
+	if idx := v.index.Get(state); idx != nil {
+		return idx.Slices(nodeName), nil
+	}
+	if v.resourceSliceLister == nil {
+		return nil, fmt.Errorf("ResourceSlice lister not available")
+	}
+
+	// Field selectors are not supported by listers, so we filter after listing
+	allResourceSlices, err := v.resourceSliceLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceSlices: %w", err)
+	}
+	var resourceSlices []*resourcev1.ResourceSlice
+	for _, slice := range allResourceSlices {
+		if slice.Spec.NodeName != nil && *slice.Spec.NodeName == nodeName {
+			resourceSlices = append(resourceSlices, slice)
+		}
+	}
+	return resourceSlices, nil
+}
+
 // parseGPUDeviceFromDRA extracts a GPUDevice from a DRA Device specification
 func (v *VRAMScheduler) parseGPUDeviceFromDRA(device resourcev1.Device, driver string) GPUDevice {
 	gpu := GPUDevice{
@@ -124,6 +139,7 @@ func (v *VRAMScheduler) parseGPUDeviceFromDRA(device resourcev1.Device, driver s
 		NUMANode:     -1,         // Default: unknown
 		NVLinkDomain: -1,         // Default: unknown
 		NVLinkPeers:  []string{}, // Default: no peers
+		Rail:         -1,         // Default: unknown
 	}
 
 	// Extract VRAM capacity from device capacity
@@ -190,6 +206,17 @@ func (v *VRAMScheduler) parseGPUDeviceFromDRA(device resourcev1.Device, driver s
 			}
 		}
 
+		// Rail-optimized fabrics pair GPU i with NIC i on rail i, each rail with its own leaf switch
+		if railAttr, exists := device.Attributes["rail"]; exists {
+			if railAttr.IntValue != nil {
+				gpu.Rail = int(*railAttr.IntValue)
+			} else if railAttr.StringValue != nil {
+				if rail, err := strconv.Atoi(*railAttr.StringValue); err == nil {
+					gpu.Rail = rail
+				}
+			}
+		}
+
 		// GPU model/type (optional, for informational purposes)
 		if modelAttr, exists := device.Attributes["model"]; exists && modelAttr.StringValue != nil {
 			// Store in PCIeSwitch field if not already populated (reuse field)
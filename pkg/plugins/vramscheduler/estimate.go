@@ -0,0 +1,281 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vramscheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+)
+
+// Model-size estimation: pods that know their parameter count but not their VRAM
+// footprint get a VRAM request derived from the model-size annotation and these hints,
+// plus the tensor-parallel annotation the rail alignment reads.
+const (
+	AnnotationModelPrecision = "scheduling.kubenexus.io/model-precision" // fp32, bf16/fp16 (default), fp8/int8, int4
+	AnnotationContextLength  = "scheduling.kubenexus.io/context-length"  // Tokens held in KV cache, context × concurrent sequences
+	AnnotationModelMode      = "scheduling.kubenexus.io/model-mode"      // "inference" or "training"; defaults from the workload type
+
+	// DefaultContextLength is the KV cache size assumed for inference without a context-length hint
+	DefaultContextLength = 4096
+
+	// TrainingBytesPerParam covers weights, gradients and Adam states in mixed precision
+	// (2 + 2 + 4 master + 8 moments); fp32 training lands on the same 16 bytes
+	TrainingBytesPerParam = 16
+
+	// EstimateOverheadFactor covers activations, allocator fragmentation and framework buffers
+	EstimateOverheadFactor = 1.2
+
+	// PerGPURuntimeOverhead is the CUDA context and communication buffers on each GPU
+	PerGPURuntimeOverhead = 1 << 30
+
+	// EventReasonVRAMEstimated is the event reason for a VRAM request derived from model size
+	EventReasonVRAMEstimated = "VRAMEstimated"
+)
+
+var _ framework.PreFilterPlugin = &VRAMScheduler{}
+
+// vramEstimate is a VRAM request derived from the model size, with its breakdown
+type vramEstimate struct {
+	ModelSize      string
+	Precision      string
+	Training       bool
+	ContextLength  int64
+	TensorParallel int
+
+	Weights  int64 // Model weights, or weights + gradients + optimizer states when training
+	KVCache  int64
+	Overhead int64
+	Total    int64
+}
+
+// String renders the estimate for events and Filter messages
+func (e *vramEstimate) String() string {
+	mode := "inference"
+	if e.Training {
+		mode = "training"
+	}
+	parts := []string{fmt.Sprintf("VRAM estimated from model-size %s (%s %s): %s", e.ModelSize, e.Precision, mode, formatBytes(e.Total))}
+	if e.Training {
+		parts = append(parts, fmt.Sprintf("weights+gradients+optimizer %s", formatBytes(e.Weights)))
+	} else {
+		parts = append(parts, fmt.Sprintf("weights %s", formatBytes(e.Weights)),
+			fmt.Sprintf("KV cache %s for %d tokens", formatBytes(e.KVCache), e.ContextLength))
+	}
+	parts = append(parts, fmt.Sprintf("overhead %s across %d GPU(s)", formatBytes(e.Overhead), e.TensorParallel))
+	return strings.Join(parts, ", ")
+}
+
+// estimateVRAMFromModel derives a VRAM request from the pod's model-size annotation.
+// workloadType decides between training and inference unless model-mode is set.
+// It returns nil when the pod has no model size or the size can't be parsed.
+func estimateVRAMFromModel(pod *v1.Pod, workloadType string) *vramEstimate {
+	modelSize := pod.Annotations[AnnotationModelSize]
+	if modelSize == "" {
+		return nil
+	}
+	params, err := parseModelSize(modelSize)
+	if err != nil {
+		klog.V(4).InfoS("Ignoring invalid model-size annotation", "pod", klog.KObj(pod), "modelSize", modelSize, "error", err)
+		return nil
+	}
+
+	precision, bytesPerParam := modelPrecision(pod)
+	est := &vramEstimate{
+		ModelSize:      modelSize,
+		Precision:      precision,
+		Training:       isTrainingMode(pod, workloadType),
+		TensorParallel: tensorParallelDegree(pod),
+	}
+
+	if est.Training {
+		est.Weights = int64(params * TrainingBytesPerParam)
+	} else {
+		est.Weights = int64(params * bytesPerParam)
+		est.ContextLength = contextLength(pod)
+		// KV cache stays in at least 16-bit even for quantized weights
+		est.KVCache = est.ContextLength * kvCacheBytesPerToken(params, math.Max(bytesPerParam, 2))
+	}
+
+	working := est.Weights + est.KVCache
+	est.Overhead = int64(float64(working)*(EstimateOverheadFactor-1)) + int64(est.TensorParallel)*PerGPURuntimeOverhead
+	est.Total = roundUpGiB(working + est.Overhead)
+	return est
+}
+
+// parseModelSize parses parameter counts like "70B", "7b", "1.5B", "350M", "1T"
+// and mixture-of-experts sizes like "8x7B"
+func parseModelSize(size string) (float64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	experts := 1.0
+	if n, rest, ok := strings.Cut(s, "X"); ok {
+		e, err := strconv.ParseFloat(n, 64)
+		if err != nil || e <= 0 {
+			return 0, fmt.Errorf("invalid expert count in %q", size)
+		}
+		experts, s = e, rest
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "T"):
+		multiplier = 1e12
+	case strings.HasSuffix(s, "B"):
+		multiplier = 1e9
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1e3
+	default:
+		return 0, fmt.Errorf("model size %q needs a K, M, B or T suffix", size)
+	}
+	n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid model size %q", size)
+	}
+	return experts * n * multiplier, nil
+}
+
+// modelPrecision returns the weight precision and its bytes per parameter, bf16 by default
+func modelPrecision(pod *v1.Pod) (string, float64) {
+	precision := strings.ToLower(pod.Annotations[AnnotationModelPrecision])
+	switch precision {
+	case "fp32", "float32":
+		return precision, 4
+	case "fp16", "float16", "bf16", "bfloat16", "half":
+		return precision, 2
+	case "fp8", "int8", "e4m3", "e5m2":
+		return precision, 1
+	case "int4", "fp4", "nf4", "awq", "gptq":
+		return precision, 0.5
+	case "":
+		return "bf16", 2
+	default:
+		klog.V(4).InfoS("Unknown model precision, assuming bf16", "pod", klog.KObj(pod), "precision", precision)
+		return "bf16", 2
+	}
+}
+
+// isTrainingMode reports whether the model is trained (optimizer states held in VRAM)
+// rather than served. The model-mode annotation wins over the workload type.
+func isTrainingMode(pod *v1.Pod, workloadType string) bool {
+	switch strings.ToLower(pod.Annotations[AnnotationModelMode]) {
+	case "training", "train":
+		return true
+	case "inference", "serving":
+		return false
+	}
+	switch profileclassifier.WorkloadType(workloadType) {
+	case profileclassifier.WorkloadTraining, profileclassifier.WorkloadFineTuning:
+		return true
+	}
+	return false
+}
+
+// tensorParallelDegree returns the tensor-parallel degree, defaulting to the GPU request
+func tensorParallelDegree(pod *v1.Pod) int {
+	if value, ok := pod.Annotations[networkfabric.AnnotationTensorParallel]; ok {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+		klog.V(4).InfoS("Ignoring invalid tensor-parallel annotation", "pod", klog.KObj(pod), "value", value)
+	}
+	if gpus := getGPURequest(pod); gpus > 0 {
+		return gpus
+	}
+	return 1
+}
+
+// contextLength returns the KV cache size in tokens
+func contextLength(pod *v1.Pod) int64 {
+	if value, ok := pod.Annotations[AnnotationContextLength]; ok {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil && n >= 0 {
+			return n
+		}
+		klog.V(4).InfoS("Ignoring invalid context-length annotation", "pod", klog.KObj(pod), "value", value)
+	}
+	return DefaultContextLength
+}
+
+// kvCacheBytesPerToken approximates the KV cache per token for a dense transformer.
+//
+// Typical shapes have params ≈ 12·L·d² with d ≈ 100·L (Llama 2 7B: L=32, d=4096;
+// 70B: L=80, d=8192), and grouped-query attention keeps K and V at d/8 per layer,
+// so one token costs 2·L·(d/8) elements = 25·L². For 70B in bf16 that's ~340KiB.
+func kvCacheBytesPerToken(params, bytesPerElement float64) int64 {
+	layers := math.Cbrt(params / 120000)
+	return int64(25 * layers * layers * bytesPerElement)
+}
+
+func roundUpGiB(bytes int64) int64 {
+	const gib = 1 << 30
+	return ceilDiv(bytes, gib) * gib
+}
+
+// estimatedVRAM returns the model-size estimate when it is the pod's VRAM source:
+// no DRA claim and no explicit vram-request annotation
+func (v *VRAMScheduler) estimatedVRAM(state framework.CycleState, pod *v1.Pod) *vramEstimate {
+	if len(pod.Spec.ResourceClaims) > 0 {
+		return nil
+	}
+	if _, ok := pod.Annotations[AnnotationVRAMRequest]; ok {
+		return nil
+	}
+	return estimateVRAMFromModel(pod, podWorkloadType(state, pod))
+}
+
+// podWorkloadType returns the workload type from the cycle's profile, or the one
+// ProfileClassifier published on an already bound pod
+func podWorkloadType(state framework.CycleState, pod *v1.Pod) string {
+	if state != nil {
+		if profile, err := profileclassifier.GetProfile(state); err == nil && profile != nil {
+			return string(profile.WorkloadType)
+		}
+	}
+	return pod.Annotations[profileclassifier.AnnotationProfileWorkloadType]
+}
+
+// PreFilter reports the model-size VRAM estimate as an event on the pod, so users
+// see the number Filter compares against GPU capacity
+func (v *VRAMScheduler) PreFilter(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodes []framework.NodeInfo) (*framework.PreFilterResult, *framework.Status) {
+	est := v.estimatedVRAM(state, pod)
+	if est == nil {
+		return nil, nil
+	}
+
+	klog.V(4).InfoS("Estimated VRAM from model size", "pod", klog.KObj(pod),
+		"modelSize", est.ModelSize, "precision", est.Precision, "training", est.Training,
+		"tensorParallel", est.TensorParallel, "vram", formatBytes(est.Total))
+	if recorder := v.handle.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, EventReasonVRAMEstimated, "Scheduling", "%s", est.String())
+	}
+	return nil, nil
+}
+
+// PreFilterExtensions returns nil as VRAMScheduler has no AddPod/RemovePod state
+func (v *VRAMScheduler) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
@@ -0,0 +1,474 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vramscheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kube-scheduler/framework"
+)
+
+// MIG (Multi-Instance GPU) support.
+//
+// MIG partitions are discovered from DRA ResourceSlices (devices with a "type"
+// attribute of "mig") or from NVIDIA GPU Feature Discovery labels (mixed strategy).
+// Pods ask for a partition by profile, through an nvidia.com/mig-<profile> resource
+// or the mig-profile annotation, or by VRAM amount, in which case the smallest free
+// partition that holds the request is used. A VRAM-only pod takes a partition when
+// it sets gpu-sharing: mig, or when no whole GPU on the node fits it. Partitions are
+// only fitted, never created.
+const (
+	AnnotationMIGProfile   = "scheduling.kubenexus.io/mig-profile"   // e.g., "3g.40gb"
+	AnnotationMIGPlacement = "scheduling.kubenexus.io/mig-placement" // "pack" (default) or "spread"
+
+	// MIGResourcePrefix is the extended resource prefix the NVIDIA device plugin uses for MIG
+	MIGResourcePrefix = "nvidia.com/mig-"
+
+	// GPU Feature Discovery labels for MIG (mixed strategy)
+	LabelMIGStrategy = "nvidia.com/mig.strategy"
+	LabelGFDGPUCount = "nvidia.com/gpu.count"
+
+	// MIGPlacementSpread spreads partitions across parent GPUs for isolation
+	MIGPlacementSpread = "spread"
+
+	// GPUSharingMIG is the gpu-sharing value opting a VRAM-only pod into MIG partitions
+	GPUSharingMIG = "mig"
+
+	// PenaltyMIGPristineParent is subtracted in pack mode when the best partition on a
+	// node would come from a GPU with no partitions in use, so nodes that can fill a
+	// partially used GPU win
+	PenaltyMIGPristineParent = 30
+)
+
+// MIGDevice is a MIG partition of a physical GPU
+type MIGDevice struct {
+	Name          string // Device name (DRA) or synthesized from labels, e.g. "gpu-0-mig-1g.10gb-0"
+	Profile       string // MIG profile, e.g. "1g.10gb", "3g.40gb"
+	Memory        int64  // Partition memory in bytes
+	ComputeSlices int    // Compute slices of the GPU instance (the "3" in "3g.40gb")
+	ParentGPU     string // Parent GPU name or UUID
+}
+
+// migOccupancy tracks whether a partition is taken
+type migOccupancy struct {
+	Device MIGDevice
+	Used   bool
+}
+
+// migRequest is what a pod needs from MIG partitions
+type migRequest struct {
+	profile string // requested profile; empty when fitting by VRAM
+	count   int
+	vram    int64
+	spread  bool
+	// explicit is set when the pod opted into MIG; otherwise it only takes a partition
+	// when no whole GPU fits it
+	explicit bool
+}
+
+// matches reports whether a partition satisfies the request
+func (r migRequest) matches(d MIGDevice) bool {
+	if r.profile != "" {
+		return strings.EqualFold(d.Profile, r.profile)
+	}
+	return d.Memory >= r.vram
+}
+
+// podMIGRequest returns the pod's MIG request. A pod opts into MIG when it names a
+// profile or sets gpu-sharing: mig; a pod asking only for VRAM, with no whole-GPU
+// request and no GPU sharing, may fall back to MIG without opting in.
+func podMIGRequest(pod *v1.Pod, vramRequest int64) (migRequest, bool) {
+	req := migRequest{count: 1, vram: vramRequest, explicit: true}
+	req.spread = strings.EqualFold(pod.Annotations[AnnotationMIGPlacement], MIGPlacementSpread)
+
+	for profile, count := range migResourceRequests(pod) {
+		req.profile, req.count = profile, count
+		return req, true
+	}
+	if profile := pod.Annotations[AnnotationMIGProfile]; profile != "" {
+		req.profile = profile
+		return req, true
+	}
+	if vramRequest > 0 && getGPURequest(pod) == 0 && !isSharedGPUPod(pod) {
+		req.explicit = strings.EqualFold(pod.Annotations[AnnotationGPUSharing], GPUSharingMIG)
+		return req, true
+	}
+	return migRequest{}, false
+}
+
+// useMIG reports whether the pod takes MIG partitions on the node: always when it
+// opted in, otherwise only when no whole GPU on the node fits its VRAM request
+func (v *VRAMScheduler) useMIG(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo, req migRequest) bool {
+	if req.explicit {
+		return true
+	}
+	node := nodeInfo.Node()
+	_, gpuDevices := v.getNodeGPUTopology(ctx, state, node)
+	if len(gpuDevices) == 0 {
+		return true
+	}
+	occupancy := v.buildGPUOccupancy(nodeInfo, gpuDevices)
+	return selectGPUs(occupancy, v.podGPURequest(state, pod, node, req.vram)) == nil
+}
+
+// migResourceRequests returns the pod's nvidia.com/mig-<profile> requests by profile
+func migResourceRequests(pod *v1.Pod) map[string]int {
+	requests := map[string]int{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			if profile, ok := strings.CutPrefix(string(name), MIGResourcePrefix); ok && quantity.Value() > 0 {
+				requests[profile] += int(quantity.Value())
+			}
+		}
+	}
+	return requests
+}
+
+// getNodeMIGDevices returns the node's MIG partitions, preferring DRA over labels
+func (v *VRAMScheduler) getNodeMIGDevices(state framework.CycleState, node *v1.Node) []MIGDevice {
+	if devices := v.getMIGDevicesFromDRA(state, node); len(devices) > 0 {
+		return devices
+	}
+	return getMIGDevicesFromLabels(node)
+}
+
+// getMIGDevicesFromDRA extracts MIG partitions from the node's GPU ResourceSlices
+func (v *VRAMScheduler) getMIGDevicesFromDRA(state framework.CycleState, node *v1.Node) []MIGDevice {
+	slices, err := v.nodeResourceSlices(state, node.Name)
+	if err != nil {
+		return nil
+	}
+
+	var devices []MIGDevice
+	for _, slice := range slices {
+		if !isGPUDriver(slice.Spec.Driver) {
+			continue
+		}
+		for _, device := range slice.Spec.Devices {
+			if mig, ok := parseMIGDeviceFromDRA(device); ok {
+				devices = append(devices, mig)
+			}
+		}
+	}
+	return devices
+}
+
+// isMIGDevice reports whether a DRA device is a MIG partition rather than a whole GPU
+func isMIGDevice(device resourcev1.Device) bool {
+	attr, ok := device.Attributes["type"]
+	return ok && attr.StringValue != nil && strings.EqualFold(*attr.StringValue, "mig")
+}
+
+// parseMIGDeviceFromDRA builds a MIGDevice from a DRA device with type=mig
+func parseMIGDeviceFromDRA(device resourcev1.Device) (MIGDevice, bool) {
+	if !isMIGDevice(device) {
+		return MIGDevice{}, false
+	}
+
+	mig := MIGDevice{Name: device.Name}
+	if attr, ok := device.Attributes["profile"]; ok && attr.StringValue != nil {
+		mig.Profile = *attr.StringValue
+	}
+	for _, key := range []resourcev1.QualifiedName{"parentUUID", "parentIndex", "parent"} {
+		attr, ok := device.Attributes[key]
+		if !ok {
+			continue
+		}
+		if attr.StringValue != nil {
+			mig.ParentGPU = *attr.StringValue
+		} else if attr.IntValue != nil {
+			mig.ParentGPU = strconv.FormatInt(*attr.IntValue, 10)
+		}
+		break
+	}
+	if capacity, ok := device.Capacity["memory"]; ok {
+		mig.Memory = capacity.Value.Value()
+	}
+
+	slices, memory := parseMIGProfile(mig.Profile)
+	mig.ComputeSlices = slices
+	if mig.Memory == 0 {
+		mig.Memory = memory
+	}
+	return mig, mig.Profile != ""
+}
+
+// getMIGDevicesFromLabels synthesizes MIG partitions from GPU Feature Discovery labels:
+//
+//	nvidia.com/mig.strategy=mixed
+//	nvidia.com/gpu.count=8
+//	nvidia.com/mig-1g.10gb.count=14
+//	nvidia.com/mig-1g.10gb.memory=9856   # MiB
+//
+// Labels don't say which GPU holds a partition, so partitions of each profile are
+// assigned to parent GPUs round-robin, matching uniform mig-manager layouts.
+func getMIGDevicesFromLabels(node *v1.Node) []MIGDevice {
+	if node.Labels[LabelMIGStrategy] != "mixed" {
+		return nil
+	}
+	gpus, err := strconv.Atoi(node.Labels[LabelGFDGPUCount])
+	if err != nil || gpus <= 0 {
+		gpus = 1
+	}
+
+	var profiles []string
+	for label := range node.Labels {
+		if rest, ok := strings.CutPrefix(label, MIGResourcePrefix); ok && strings.HasSuffix(rest, ".count") {
+			profiles = append(profiles, strings.TrimSuffix(rest, ".count"))
+		}
+	}
+	sort.Strings(profiles)
+
+	var devices []MIGDevice
+	for _, profile := range profiles {
+		count, err := strconv.Atoi(node.Labels[MIGResourcePrefix+profile+".count"])
+		if err != nil || count <= 0 {
+			continue
+		}
+		slices, memory := parseMIGProfile(profile)
+		if mib, err := strconv.ParseInt(node.Labels[MIGResourcePrefix+profile+".memory"], 10, 64); err == nil && mib > 0 {
+			memory = mib * 1024 * 1024
+		}
+		for i := 0; i < count; i++ {
+			parent := fmt.Sprintf("gpu-%d", i%gpus)
+			devices = append(devices, MIGDevice{
+				Name:          fmt.Sprintf("%s-mig-%s-%d", parent, profile, i/gpus),
+				Profile:       profile,
+				Memory:        memory,
+				ComputeSlices: slices,
+				ParentGPU:     parent,
+			})
+		}
+	}
+	return devices
+}
+
+// parseMIGProfile parses compute slices and memory from a profile name like "3g.40gb"
+// or "1g.10gb+me". Profile memory is nominal, so the label or DRA capacity wins when present.
+func parseMIGProfile(profile string) (int, int64) {
+	compute, memory, ok := strings.Cut(strings.ToLower(profile), "g.")
+	if !ok {
+		return 0, 0
+	}
+	slices, _ := strconv.Atoi(compute)
+	memory, _, _ = strings.Cut(memory, "+")
+	gb, err := strconv.ParseInt(strings.TrimSuffix(memory, "gb"), 10, 64)
+	if err != nil {
+		return slices, 0
+	}
+	return slices, gb * 1024 * 1024 * 1024
+}
+
+// buildMIGOccupancy marks partitions held by pods already on the node: DRA allocations
+// and recorded assignments by name, device-plugin requests replayed by profile
+func (v *VRAMScheduler) buildMIGOccupancy(nodeInfo framework.NodeInfo, devices []MIGDevice) []migOccupancy {
+	occupancy := make([]migOccupancy, len(devices))
+	byName := make(map[string]int, len(devices))
+	for i, device := range devices {
+		occupancy[i] = migOccupancy{Device: device}
+		byName[device.Name] = i
+	}
+
+	pods := make([]*v1.Pod, 0, len(nodeInfo.GetPods()))
+	for _, podInfo := range nodeInfo.GetPods() {
+		pods = append(pods, podInfo.GetPod())
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	for _, pod := range pods {
+		marked := false
+		for _, name := range append(v.migClaimDevices(pod), v.recordedGPUs(pod)...) {
+			if i, ok := byName[name]; ok {
+				occupancy[i].Used = true
+				marked = true
+			}
+		}
+		if marked {
+			continue
+		}
+		for profile, count := range migResourceRequests(pod) {
+			req := migRequest{profile: profile, count: count}
+			selected := selectMIGDevices(occupancy, req)
+			if selected == nil {
+				klog.V(5).InfoS("Bound pod's MIG request doesn't fit replayed partitions", "pod", klog.KObj(pod), "profile", profile)
+				continue
+			}
+			for _, i := range selected {
+				occupancy[i].Used = true
+			}
+		}
+	}
+	return occupancy
+}
+
+// migClaimDevices returns the device names allocated to the pod's ResourceClaims
+func (v *VRAMScheduler) migClaimDevices(pod *v1.Pod) []string {
+	if v.resourceClaimLister == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return nil
+	}
+	var names []string
+	for _, claimName := range podClaimNames(pod) {
+		claim, err := v.resourceClaimLister.ResourceClaims(pod.Namespace).Get(claimName)
+		if err != nil || claim.Status.Allocation == nil {
+			continue
+		}
+		for _, result := range claim.Status.Allocation.Devices.Results {
+			names = append(names, result.Device)
+		}
+	}
+	return names
+}
+
+// selectMIGDevices picks free partitions for the request, or nil if too few fit.
+//
+// Smaller fitting partitions come first. Among equals, pack mode prefers parents
+// with the most partitions already in use, so pristine GPUs stay whole for jobs
+// that need a full GPU or a large profile; spread mode prefers the least used.
+func selectMIGDevices(occupancy []migOccupancy, req migRequest) []int {
+	inUse := migParentUsage(occupancy)
+
+	candidates := make([]int, 0, len(occupancy))
+	for i := range occupancy {
+		if !occupancy[i].Used && req.matches(occupancy[i].Device) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) < req.count {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		da, db := occupancy[candidates[a]].Device, occupancy[candidates[b]].Device
+		if da.Memory != db.Memory {
+			return da.Memory < db.Memory
+		}
+		if ua, ub := inUse[da.ParentGPU], inUse[db.ParentGPU]; ua != ub {
+			if req.spread {
+				return ua < ub
+			}
+			return ua > ub
+		}
+		return da.Name < db.Name
+	})
+	return candidates[:req.count]
+}
+
+// migParentUsage counts partitions in use per parent GPU
+func migParentUsage(occupancy []migOccupancy) map[string]int {
+	inUse := make(map[string]int)
+	for i := range occupancy {
+		if occupancy[i].Used {
+			inUse[occupancy[i].Device.ParentGPU]++
+		}
+	}
+	return inUse
+}
+
+// filterMIG checks MIG partitions for the pod. handled is false when the pod should
+// fall back to whole-GPU placement: it asked only for VRAM and no partition fits.
+func (v *VRAMScheduler) filterMIG(state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo, req migRequest) (status *framework.Status, handled bool) {
+	node := nodeInfo.Node()
+	devices := v.getNodeMIGDevices(state, node)
+	if len(devices) == 0 {
+		if req.profile != "" {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				fmt.Sprintf("node %s has no MIG partitions", node.Name)), true
+		}
+		return nil, false
+	}
+
+	occupancy := v.buildMIGOccupancy(nodeInfo, devices)
+	if selectMIGDevices(occupancy, req) != nil {
+		return framework.NewStatus(framework.Success), true
+	}
+	if req.profile != "" {
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("no free MIG partition: need %d × %s", req.count, req.profile)), true
+	}
+	return nil, false
+}
+
+// scoreMIG scores the best partition fit for the pod, with handled mirroring filterMIG
+func (v *VRAMScheduler) scoreMIG(state framework.CycleState, pod *v1.Pod, nodeInfo framework.NodeInfo, req migRequest) (int64, bool) {
+	devices := v.getNodeMIGDevices(state, nodeInfo.Node())
+	if len(devices) == 0 {
+		return ScoreInsufficientVRAM, req.profile != ""
+	}
+
+	occupancy := v.buildMIGOccupancy(nodeInfo, devices)
+	selected := selectMIGDevices(occupancy, req)
+	if selected == nil {
+		return ScoreInsufficientVRAM, req.profile != ""
+	}
+
+	score := int64(ScorePerfectFit)
+	if req.profile == "" {
+		// Fitting by VRAM: reward partitions sized close to the request
+		var memory int64
+		for _, i := range selected {
+			memory += occupancy[i].Device.Memory
+		}
+		score = calculateUtilizationScore(float64(req.vram*int64(req.count))/float64(memory), v.getTenantTierFromProfile(state, pod))
+	}
+
+	// Pack mode: don't split a pristine GPU when the node only has pristine fits
+	inUse := migParentUsage(occupancy)
+	if !req.spread && inUse[occupancy[selected[0]].Device.ParentGPU] == 0 {
+		score -= PenaltyMIGPristineParent
+		if score < 0 {
+			score = 0
+		}
+	}
+
+	klog.V(4).InfoS("MIG placement score",
+		"pod", klog.KObj(pod),
+		"node", nodeInfo.Node().Name,
+		"profile", occupancy[selected[0]].Device.Profile,
+		"parentGPU", occupancy[selected[0]].Device.ParentGPU,
+		"score", score)
+	return score, true
+}
+
+// reserveMIG picks partitions at Reserve; handled mirrors filterMIG
+func (v *VRAMScheduler) reserveMIG(state framework.CycleState, nodeInfo framework.NodeInfo, req migRequest) ([]string, bool) {
+	devices := v.getNodeMIGDevices(state, nodeInfo.Node())
+	if len(devices) == 0 {
+		return nil, req.profile != ""
+	}
+	occupancy := v.buildMIGOccupancy(nodeInfo, devices)
+	selected := selectMIGDevices(occupancy, req)
+	if selected == nil {
+		return nil, req.profile != ""
+	}
+	names := make([]string, 0, len(selected))
+	for _, i := range selected {
+		names = append(names, occupancy[i].Device.Name)
+	}
+	return names, true
+}
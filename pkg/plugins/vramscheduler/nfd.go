@@ -25,6 +25,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	klog "k8s.io/klog/v2"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/gpucatalog"
 )
 
 // NFD label prefixes and patterns
@@ -53,46 +55,6 @@ const (
 	NFDSystemPrefix = NFDLabelPrefix + "system-"
 )
 
-// Known GPU PCI device IDs to VRAM mapping
-// Source: https://pci-ids.ucw.cz/ and GPU vendor specifications
-var nvidiaDeviceIDToVRAM = map[string]int64{
-	// H100 series
-	"2330": 80 * 1024 * 1024 * 1024,  // H100 PCIe 80GB
-	"2331": 80 * 1024 * 1024 * 1024,  // H100 SXM5 80GB
-	"2322": 141 * 1024 * 1024 * 1024, // H200 141GB
-
-	// A100 series
-	"20b0": 40 * 1024 * 1024 * 1024, // A100 PCIe 40GB
-	"20b1": 40 * 1024 * 1024 * 1024, // A100 SXM4 40GB
-	"20b2": 80 * 1024 * 1024 * 1024, // A100 PCIe 80GB
-	"20b5": 80 * 1024 * 1024 * 1024, // A100 SXM4 80GB
-
-	// L40/L40S series
-	"26b5": 48 * 1024 * 1024 * 1024, // L40S 48GB
-	"26b1": 48 * 1024 * 1024 * 1024, // L40 48GB
-
-	// L4 series
-	"27b8": 24 * 1024 * 1024 * 1024, // L4 24GB
-
-	// A40 series
-	"2235": 48 * 1024 * 1024 * 1024, // A40 48GB
-
-	// A30 series
-	"20b7": 24 * 1024 * 1024 * 1024, // A30 24GB
-
-	// T4 series
-	"1eb8": 16 * 1024 * 1024 * 1024, // T4 16GB
-
-	// V100 series
-	"1db4": 16 * 1024 * 1024 * 1024, // V100 PCIe 16GB
-	"1db5": 32 * 1024 * 1024 * 1024, // V100 SXM2 32GB
-	"1db6": 32 * 1024 * 1024 * 1024, // V100 SXM2 32GB (variant)
-
-	// RTX series (datacenter)
-	"2204": 48 * 1024 * 1024 * 1024, // RTX 6000 Ada 48GB
-	"2206": 48 * 1024 * 1024 * 1024, // RTX 5000 Ada 32GB (approximate)
-}
-
 // getTopologyFromNFD extracts GPU topology from NFD-populated node labels.
 // This is the SECONDARY fallback when DRA is not available (K8s < 1.26 or no DRA driver).
 //
@@ -194,15 +156,10 @@ func (v *VRAMScheduler) scanNFDForPCIDevices(node *v1.Node, vendorID string) []G
 			}
 		}
 
-		// Look up VRAM for this device ID
+		// Look up VRAM for this device ID in the GPU catalog
 		var vram int64
-		switch vendorID {
-		case NVIDIAVendorID:
-			vram = nvidiaDeviceIDToVRAM[deviceID]
-		case AMDVendorID:
-			vram = inferAMDVRAMFromDeviceID(deviceID)
-		case IntelGPUVendorID:
-			vram = inferIntelVRAMFromDeviceID(deviceID)
+		if model, ok := gpucatalog.Get().LookupPCI(vendorID, deviceID); ok {
+			vram = model.VRAMBytes()
 		}
 
 		if vram == 0 {
@@ -222,6 +179,7 @@ func (v *VRAMScheduler) scanNFDForPCIDevices(node *v1.Node, vendorID string) []G
 				NUMANode:     -1, // Will be inferred later if NUMA info available
 				NVLinkDomain: -1,
 				NVLinkPeers:  []string{},
+				Rail:         -1,
 			}
 			devices = append(devices, gpu)
 			deviceIndex++
@@ -275,34 +233,3 @@ func (v *VRAMScheduler) getNUMANodeCountFromNFD(node *v1.Node) int {
 
 	return 0
 }
-
-// inferAMDVRAMFromDeviceID infers VRAM for AMD GPUs
-// TODO: Expand this mapping as needed
-func inferAMDVRAMFromDeviceID(deviceID string) int64 {
-	amdDeviceMap := map[string]int64{
-		"740f": 32 * 1024 * 1024 * 1024, // MI300X 192GB (HBM3)
-		"740c": 64 * 1024 * 1024 * 1024, // MI250X 128GB
-		"7408": 32 * 1024 * 1024 * 1024, // MI250 64GB
-		"738c": 32 * 1024 * 1024 * 1024, // MI100 32GB
-	}
-
-	if vram, ok := amdDeviceMap[deviceID]; ok {
-		return vram
-	}
-	return 0
-}
-
-// inferIntelVRAMFromDeviceID infers VRAM for Intel GPUs
-// TODO: Expand this mapping as needed
-func inferIntelVRAMFromDeviceID(deviceID string) int64 {
-	intelDeviceMap := map[string]int64{
-		"0bd5": 48 * 1024 * 1024 * 1024, // Data Center GPU Max 1550 (Ponte Vecchio)
-		"56c0": 16 * 1024 * 1024 * 1024, // Arc A770 16GB
-		"56c1": 12 * 1024 * 1024 * 1024, // Arc A750 8GB
-	}
-
-	if vram, ok := intelDeviceMap[deviceID]; ok {
-		return vram
-	}
-	return 0
-}
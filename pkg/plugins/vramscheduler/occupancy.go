@@ -0,0 +1,369 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vramscheduler
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+)
+
+// gpuOccupancy is the VRAM in use on one physical GPU and the pods holding it
+type gpuOccupancy struct {
+	Device   GPUDevice
+	UsedVRAM int64
+
+	// SharedTenants counts pods placed in GPU sharing mode; ExclusiveTenants counts the rest
+	SharedTenants    int
+	ExclusiveTenants int
+
+	// Tiers counts shared tenants per tenant tier, for isolation between tiers
+	Tiers map[string]int
+}
+
+// charge records a pod holding vram on the GPU
+func (g *gpuOccupancy) charge(vram int64, shared bool, tier string) {
+	g.UsedVRAM += vram
+	if !shared {
+		g.ExclusiveTenants++
+		return
+	}
+	g.SharedTenants++
+	if g.Tiers == nil {
+		g.Tiers = make(map[string]int)
+	}
+	g.Tiers[tier]++
+}
+
+// gpuRequest is what a pod needs from each GPU it lands on
+type gpuRequest struct {
+	count int
+	// perGPU is the VRAM needed on each GPU; 0 asks for entirely free GPUs
+	perGPU int64
+
+	// shared requests may join other shared tenants up to maxTenants, subject to tier isolation
+	shared     bool
+	tier       string
+	maxTenants int
+
+	// railAligned requests take GPUs in rail order, so rank i of every gang member
+	// sits on rail i and cross-node collectives stay on one rail switch
+	railAligned bool
+}
+
+// fits reports whether the GPU can host the request
+func (r gpuRequest) fits(g *gpuOccupancy) bool {
+	if r.perGPU == 0 {
+		return g.UsedVRAM == 0 && g.Device.VRAM > 0
+	}
+	if g.freeVRAM() < r.perGPU {
+		return false
+	}
+	if !r.shared {
+		// Exclusive pods don't join GPUs handed out in sharing mode
+		return g.SharedTenants == 0
+	}
+	if g.ExclusiveTenants > 0 || (r.maxTenants > 0 && g.SharedTenants >= r.maxTenants) {
+		return false
+	}
+	for tier, n := range g.Tiers {
+		if n > 0 && !tiersCanShare(r.tier, tier) {
+			return false
+		}
+	}
+	return true
+}
+
+// freeVRAM returns the unallocated VRAM on the GPU
+func (g *gpuOccupancy) freeVRAM() int64 {
+	if free := g.Device.VRAM - g.UsedVRAM; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// buildGPUOccupancy attributes the VRAM held by pods already on the node to its GPUs.
+//
+// Attribution, most precise first:
+//  1. DRA allocations: the allocated devices, charged their consumed "memory"
+//     capacity when the driver shares devices, otherwise the whole device
+//  2. Devices recorded by this scheduler (gpu-devices annotation or a pending Reserve)
+//  3. VRAM annotation: the request split evenly across the pod's GPUs, placed best-fit
+//  4. Whole-GPU requests without a VRAM annotation occupy entire free devices
+//
+// Pods without device-level placement are replayed in name order, so the same node
+// state always produces the same occupancy.
+func (v *VRAMScheduler) buildGPUOccupancy(nodeInfo framework.NodeInfo, devices []GPUDevice) []gpuOccupancy {
+	occupancy := make([]gpuOccupancy, len(devices))
+	byName := make(map[string]int, len(devices))
+	for i, device := range devices {
+		occupancy[i] = gpuOccupancy{Device: device}
+		byName[device.Name] = i
+	}
+
+	pods := make([]*v1.Pod, 0, len(nodeInfo.GetPods()))
+	for _, podInfo := range nodeInfo.GetPods() {
+		pods = append(pods, podInfo.GetPod())
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	for _, pod := range pods {
+		if v.chargeDRAAllocations(pod, occupancy, byName) {
+			continue
+		}
+
+		vram := v.boundPodVRAM(pod)
+		gpus := getGPURequest(pod)
+		if vram == 0 && gpus == 0 {
+			continue
+		}
+		shared := isSharedGPUPod(pod)
+		tier := v.boundPodTier(pod)
+
+		// Devices recorded by the scheduler at Reserve time are authoritative
+		if names := v.recordedGPUs(pod); len(names) > 0 {
+			perGPU := ceilDiv(vram, int64(len(names)))
+			for _, name := range names {
+				if i, ok := byName[name]; ok {
+					if vram == 0 {
+						perGPU = occupancy[i].Device.VRAM
+					}
+					occupancy[i].charge(perGPU, shared, tier)
+				}
+			}
+			continue
+		}
+
+		if gpus == 0 || shared {
+			gpus = 1
+		}
+		req := gpuRequest{count: gpus, shared: shared, tier: tier}
+		if vram > 0 {
+			req.perGPU = ceilDiv(vram, int64(gpus))
+		}
+		placeOnGPUs(occupancy, req)
+	}
+
+	return occupancy
+}
+
+// chargeDRAAllocations charges the devices allocated to the pod's ResourceClaims.
+// It returns false when the pod has no allocated GPU devices on this node.
+func (v *VRAMScheduler) chargeDRAAllocations(pod *v1.Pod, occupancy []gpuOccupancy, byName map[string]int) bool {
+	if v.resourceClaimLister == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return false
+	}
+
+	charged := false
+	for _, claimName := range podClaimNames(pod) {
+		claim, err := v.resourceClaimLister.ResourceClaims(pod.Namespace).Get(claimName)
+		if err != nil || claim.Status.Allocation == nil {
+			continue
+		}
+		for _, result := range claim.Status.Allocation.Devices.Results {
+			i, ok := byName[result.Device]
+			if !ok || !isGPUDriver(result.Driver) {
+				continue
+			}
+			occupancy[i].charge(consumedVRAM(result, occupancy[i].Device.VRAM), isSharedGPUPod(pod), v.boundPodTier(pod))
+			charged = true
+		}
+	}
+	return charged
+}
+
+// podClaimNames resolves the names of the ResourceClaim objects used by the pod
+func podClaimNames(pod *v1.Pod) []string {
+	generated := make(map[string]string, len(pod.Status.ResourceClaimStatuses))
+	for _, status := range pod.Status.ResourceClaimStatuses {
+		if status.ResourceClaimName != nil {
+			generated[status.Name] = *status.ResourceClaimName
+		}
+	}
+
+	names := make([]string, 0, len(pod.Spec.ResourceClaims))
+	for _, ref := range pod.Spec.ResourceClaims {
+		if ref.ResourceClaimName != nil {
+			names = append(names, *ref.ResourceClaimName)
+		} else if name, ok := generated[ref.Name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// consumedVRAM returns the VRAM charged for a DRA allocation: the consumed memory
+// capacity for shared devices, or the whole device for exclusive allocations
+func consumedVRAM(result resourcev1.DeviceRequestAllocationResult, deviceVRAM int64) int64 {
+	for name, quantity := range result.ConsumedCapacity {
+		switch strings.ToLower(string(name)) {
+		case "memory", "gpu.memory", "nvidia.com/memory", "vram":
+			return quantity.Value()
+		}
+	}
+	return deviceVRAM
+}
+
+// boundPodVRAM returns the VRAM a pod on the node holds: its VRAM request annotation,
+// else the model-size estimate made when it was scheduled (recorded at Reserve, then
+// at PreBind), else a re-derived estimate. It returns 0 if none is usable.
+func (v *VRAMScheduler) boundPodVRAM(pod *v1.Pod) int64 {
+	if vramStr, ok := pod.Annotations[AnnotationVRAMRequest]; ok {
+		return parseVRAMAnnotation(pod, AnnotationVRAMRequest, vramStr)
+	}
+
+	v.assignmentsLock.Lock()
+	reserved, ok := v.assignments[podKey(pod)]
+	v.assignmentsLock.Unlock()
+	if ok && reserved.estimate > 0 {
+		return reserved.estimate
+	}
+	if vramStr, ok := pod.Annotations[AnnotationVRAMEstimate]; ok {
+		return parseVRAMAnnotation(pod, AnnotationVRAMEstimate, vramStr)
+	}
+	if est := estimateVRAMFromModel(pod, podWorkloadType(nil, pod)); est != nil {
+		return est.Total
+	}
+	return 0
+}
+
+// parseVRAMAnnotation parses a VRAM quantity annotation of a bound pod, returning 0
+// if it is invalid
+func parseVRAMAnnotation(pod *v1.Pod, key, value string) int64 {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		klog.V(5).InfoS("Ignoring invalid VRAM annotation on bound pod", "pod", klog.KObj(pod), "annotation", key, "value", value)
+		return 0
+	}
+	return quantity.Value()
+}
+
+// selectGPUs picks the GPUs for a request, preferring the tightest fit. It returns
+// nil when the node cannot host the request.
+func selectGPUs(occupancy []gpuOccupancy, req gpuRequest) []int {
+	candidates := make([]int, 0, len(occupancy))
+	for i := range occupancy {
+		if req.fits(&occupancy[i]) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) < req.count {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if req.railAligned {
+			if ra, rb := railOrder(occupancy[candidates[a]].Device), railOrder(occupancy[candidates[b]].Device); ra != rb {
+				return ra < rb
+			}
+		}
+		fa, fb := occupancy[candidates[a]].freeVRAM(), occupancy[candidates[b]].freeVRAM()
+		if fa != fb {
+			return fa < fb
+		}
+		return occupancy[candidates[a]].Device.Name < occupancy[candidates[b]].Device.Name
+	})
+	return candidates[:req.count]
+}
+
+// recordSelectedRails publishes the rails of the selected GPUs, so NetworkFabricScore
+// judges rail alignment on the rails the pod would get on the node
+func recordSelectedRails(state framework.CycleState, nodeName string, occupancy []gpuOccupancy, selected []int) {
+	rails := make([]int, 0, len(selected))
+	for _, i := range selected {
+		if rail := occupancy[i].Device.Rail; rail >= 0 {
+			rails = append(rails, rail)
+		}
+	}
+	networkfabric.RecordSelectedRails(state, nodeName, rails)
+}
+
+// railOrder sorts GPUs by rail, GPUs with an unknown rail last
+func railOrder(device GPUDevice) int {
+	if device.Rail < 0 {
+		return math.MaxInt
+	}
+	return device.Rail
+}
+
+// placeOnGPUs charges a bound pod to the GPUs it most likely occupies. If the node is
+// already overcommitted, the pod is charged to the GPUs with the most free VRAM so the
+// overcommit stays visible instead of being dropped.
+func placeOnGPUs(occupancy []gpuOccupancy, req gpuRequest) {
+	selected := selectGPUs(occupancy, req)
+	if selected == nil {
+		selected = make([]int, len(occupancy))
+		for i := range selected {
+			selected[i] = i
+		}
+		sort.SliceStable(selected, func(a, b int) bool {
+			return occupancy[selected[a]].freeVRAM() > occupancy[selected[b]].freeVRAM()
+		})
+		if req.count < len(selected) {
+			selected = selected[:req.count]
+		}
+	}
+
+	for _, i := range selected {
+		vram := req.perGPU
+		if vram == 0 {
+			vram = occupancy[i].Device.VRAM
+		}
+		occupancy[i].charge(vram, req.shared, req.tier)
+	}
+}
+
+// largestFreeVRAM returns the most free VRAM on any single GPU
+func largestFreeVRAM(occupancy []gpuOccupancy) int64 {
+	var largest int64
+	for i := range occupancy {
+		if free := occupancy[i].freeVRAM(); free > largest {
+			largest = free
+		}
+	}
+	return largest
+}
+
+// nodeVRAMUtilization returns the fraction of the node's total VRAM in use
+func nodeVRAMUtilization(occupancy []gpuOccupancy) float64 {
+	var used, total int64
+	for i := range occupancy {
+		used += occupancy[i].Device.VRAM - occupancy[i].freeVRAM()
+		total += occupancy[i].Device.VRAM
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
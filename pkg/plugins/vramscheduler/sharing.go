@@ -0,0 +1,313 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vramscheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+	"k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+)
+
+const (
+	// DefaultMaxTenantsPerGPU bounds how many shared pods land on one GPU when the
+	// node doesn't set gpu.kubenexus.io/max-tenants
+	DefaultMaxTenantsPerGPU = 4
+
+	gpuAssignmentStateKey = Name + "/gpu-assignment"
+)
+
+var (
+	_ framework.ReservePlugin  = &VRAMScheduler{}
+	_ framework.PreBindPlugin  = &VRAMScheduler{}
+	_ framework.PostBindPlugin = &VRAMScheduler{}
+)
+
+// gpuAssignment is the set of GPUs chosen for the pod at Reserve, and the VRAM
+// estimated from its model size, if any
+type gpuAssignment struct {
+	devices  []string
+	shared   bool
+	estimate int64
+}
+
+// Clone implements framework.StateData
+func (a *gpuAssignment) Clone() framework.StateData {
+	return a
+}
+
+// reservedGPUs is what Reserve picked for a pod that isn't bound yet
+type reservedGPUs struct {
+	devices []string
+	// tier is the pod's tenant tier, known before ProfileClassifier publishes it
+	tier string
+	// estimate is the VRAM estimated from the pod's model size, known before PreBind
+	// records it
+	estimate int64
+}
+
+// isSharedGPUPod reports whether the pod opted into fractional GPU sharing
+func isSharedGPUPod(pod *v1.Pod) bool {
+	return strings.EqualFold(pod.Annotations[AnnotationGPUSharing], "true")
+}
+
+// boundPodTier returns the tenant tier of a pod on the node: the tier recorded at
+// Reserve while the pod binds, else the tier ProfileClassifier published on it
+func (v *VRAMScheduler) boundPodTier(pod *v1.Pod) string {
+	v.assignmentsLock.Lock()
+	reserved, ok := v.assignments[podKey(pod)]
+	v.assignmentsLock.Unlock()
+	if ok && reserved.tier != "" {
+		return reserved.tier
+	}
+	if tier := pod.Annotations[profileclassifier.AnnotationProfileTier]; tier != "" {
+		return strings.ToLower(tier)
+	}
+	return string(profileclassifier.TierUnknown)
+}
+
+// tierRank orders tenant tiers; unknown tiers rank 0
+func tierRank(tier string) int {
+	switch strings.ToLower(tier) {
+	case "gold":
+		return 3
+	case "silver":
+		return 2
+	case "bronze":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// tiersCanShare reports whether two tenant tiers may share a physical GPU. Only
+// adjacent tiers mix: gold with silver and silver with bronze, never gold with bronze.
+// A pod of unknown tier shares with no one, since isolation can't be checked.
+func tiersCanShare(a, b string) bool {
+	rankA, rankB := tierRank(a), tierRank(b)
+	if rankA == 0 || rankB == 0 {
+		return false
+	}
+	diff := rankA - rankB
+	return diff >= -1 && diff <= 1
+}
+
+// maxTenantsPerGPU returns the node's shared-tenant limit per GPU
+func maxTenantsPerGPU(node *v1.Node) int {
+	if value, ok := node.Labels[LabelGPUMaxTenants]; ok {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+		klog.V(4).InfoS("Ignoring invalid GPU max-tenants label", "node", node.Name, "value", value)
+	}
+	return DefaultMaxTenantsPerGPU
+}
+
+// podGPURequest describes what the pod being scheduled needs from each GPU on the node
+func (v *VRAMScheduler) podGPURequest(state framework.CycleState, pod *v1.Pod, node *v1.Node, vramRequest int64) gpuRequest {
+	gpus := getGPURequest(pod)
+	if gpus == 0 {
+		gpus = 1
+	}
+
+	req := gpuRequest{count: gpus, perGPU: ceilDiv(vramRequest, int64(gpus)), railAligned: networkfabric.IsRailAligned(pod)}
+	if isSharedGPUPod(pod) {
+		// A shared pod takes a slice of one GPU, bounded by its VRAM request
+		req.count = 1
+		req.perGPU = vramRequest
+		req.shared = true
+		req.tier = v.getTenantTierFromProfile(state, pod)
+		req.maxTenants = maxTenantsPerGPU(node)
+	}
+	return req
+}
+
+// recordedGPUs returns the GPUs the scheduler assigned to the pod, from a pending
+// Reserve or from the gpu-devices annotation written at PreBind
+func (v *VRAMScheduler) recordedGPUs(pod *v1.Pod) []string {
+	v.assignmentsLock.Lock()
+	reserved, ok := v.assignments[podKey(pod)]
+	v.assignmentsLock.Unlock()
+	if ok {
+		return reserved.devices
+	}
+
+	value := pod.Annotations[AnnotationGPUDevices]
+	if value == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Reserve picks the GPUs for the pod on the chosen node and holds them until the
+// placement is written to the pod at PreBind
+func (v *VRAMScheduler) Reserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	vramRequest := v.getVRAMRequest(ctx, state, pod)
+	migReq, isMIG := podMIGRequest(pod, vramRequest)
+	if vramRequest == 0 && !isMIG {
+		return nil
+	}
+
+	nodeInfo, err := v.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.AsStatus(fmt.Errorf("getting node %q from snapshot: %w", nodeName, err))
+	}
+	node := nodeInfo.Node()
+	isMIG = isMIG && v.useMIG(ctx, state, pod, nodeInfo, migReq)
+
+	if isMIG {
+		if devices, handled := v.reserveMIG(state, nodeInfo, migReq); handled {
+			if devices == nil {
+				return framework.NewStatus(framework.Unschedulable,
+					fmt.Sprintf("no free MIG partition of profile %s on node %s", migReq.profile, nodeName))
+			}
+			v.recordAssignment(state, pod, devices, false)
+			klog.V(4).InfoS("Reserve: assigned MIG partitions", "pod", klog.KObj(pod), "node", nodeName, "devices", devices)
+			return nil
+		}
+		if vramRequest == 0 {
+			return nil
+		}
+	}
+	_, gpuDevices := v.getNodeGPUTopology(ctx, state, node)
+
+	req := v.podGPURequest(state, pod, node, vramRequest)
+	occupancy := v.buildGPUOccupancy(nodeInfo, gpuDevices)
+	selected := selectGPUs(occupancy, req)
+	if selected == nil {
+		if req.shared {
+			return framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("no GPU on node %s can host a %s shared slice", nodeName, formatBytes(req.perGPU)))
+		}
+		// Exclusive pods are still bound; the device plugin picks their GPUs
+		v.recordAssignment(state, pod, nil, false)
+		klog.V(4).InfoS("Reserve: no GPU assignment recorded", "pod", klog.KObj(pod), "node", nodeName)
+		return nil
+	}
+
+	devices := make([]string, 0, len(selected))
+	for _, i := range selected {
+		devices = append(devices, occupancy[i].Device.Name)
+	}
+
+	v.recordAssignment(state, pod, devices, req.shared)
+
+	klog.V(4).InfoS("Reserve: assigned GPUs", "pod", klog.KObj(pod), "node", nodeName,
+		"devices", devices, "shared", req.shared, "vramPerGPU", formatBytes(req.perGPU))
+	return nil
+}
+
+// Unreserve releases the GPUs held for the pod
+func (v *VRAMScheduler) Unreserve(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	v.releaseAssignment(pod)
+}
+
+// PreBindPreFlight skips PreBind for pods without a GPU assignment
+func (v *VRAMScheduler) PreBindPreFlight(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if _, err := state.Read(gpuAssignmentStateKey); err != nil {
+		return framework.NewStatus(framework.Skip)
+	}
+	return nil
+}
+
+// PreBind records the GPU assignment on the pod so the device runtime can honor it,
+// and the model-size VRAM estimate so occupancy doesn't re-derive it without the
+// cycle's profile. Shared pods fail to bind without the record, since nothing else
+// bounds their slice.
+func (v *VRAMScheduler) PreBind(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	data, err := state.Read(gpuAssignmentStateKey)
+	if err != nil {
+		return nil
+	}
+	assignment, ok := data.(*gpuAssignment)
+	if !ok {
+		return framework.AsStatus(fmt.Errorf("%+v convert to *gpuAssignment error", data))
+	}
+
+	annotations := map[string]string{}
+	if len(assignment.devices) > 0 {
+		annotations[AnnotationGPUDevices] = strings.Join(assignment.devices, ",")
+	}
+	if assignment.estimate > 0 {
+		annotations[AnnotationVRAMEstimate] = resource.NewQuantity(assignment.estimate, resource.BinarySI).String()
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+
+	if _, err := v.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		if assignment.shared {
+			return framework.AsStatus(fmt.Errorf("recording shared GPU assignment: %w", err))
+		}
+		klog.V(2).InfoS("PreBind: failed to record GPU assignment", "pod", klog.KObj(pod), "node", nodeName, "error", err)
+	}
+	return nil
+}
+
+// PostBind drops the in-memory assignment once the pod carries the annotation
+func (v *VRAMScheduler) PostBind(ctx context.Context, state framework.CycleState, pod *v1.Pod, nodeName string) {
+	v.releaseAssignment(pod)
+}
+
+// recordAssignment holds the devices, tenant tier and VRAM estimate for the pod until
+// PreBind writes them to it
+func (v *VRAMScheduler) recordAssignment(state framework.CycleState, pod *v1.Pod, devices []string, shared bool) {
+	reserved := reservedGPUs{devices: devices, tier: v.getTenantTierFromProfile(state, pod)}
+	if est := v.estimatedVRAM(state, pod); est != nil {
+		reserved.estimate = est.Total
+	}
+	v.assignmentsLock.Lock()
+	v.assignments[podKey(pod)] = reserved
+	v.assignmentsLock.Unlock()
+	state.Write(gpuAssignmentStateKey, &gpuAssignment{devices: devices, shared: shared, estimate: reserved.estimate})
+}
+
+func (v *VRAMScheduler) releaseAssignment(pod *v1.Pod) {
+	v.assignmentsLock.Lock()
+	delete(v.assignments, podKey(pod))
+	v.assignmentsLock.Unlock()
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
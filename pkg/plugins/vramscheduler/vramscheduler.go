@@ -45,6 +45,18 @@ limitations under the License.
 //     scheduling.kubenexus.io/vram-request: "80Gi"
 //     scheduling.kubenexus.io/model-size: "70B"  # informational
 //
+//  3. Model-size estimate (when no claim or vram-request gives the number):
+//     VRAM is derived from the parameter count, precision, KV cache context,
+//     tensor-parallel degree and, for training, gradients and optimizer states.
+//     The estimate is reported in a VRAMEstimated event and in Filter messages.
+//     metadata:
+//     annotations:
+//     scheduling.kubenexus.io/model-size: "70B"
+//     scheduling.kubenexus.io/model-precision: "fp8"     # default bf16
+//     scheduling.kubenexus.io/context-length: "32768"   # default 4096, inference only
+//     scheduling.kubenexus.io/tensor-parallel: "4"      # default: GPU request
+//     scheduling.kubenexus.io/model-mode: "inference"   # default from workload type
+//
 // # Node VRAM Capacity Discovery (Node-side)
 //
 //  1. DRA ResourceSlices (Kubernetes v1.26+, preferred):
@@ -66,6 +78,39 @@ limitations under the License.
 //     gpu.kubenexus.io/model: "H100"
 //     gpu.kubenexus.io/count: "8"
 //
+// # Per-GPU Occupancy
+//
+// Filter and Score work on free VRAM per GPU, not raw capacity. VRAM held by pods
+// already on the node is attributed to devices from their DRA allocations, or
+// replayed best-fit from their vram-request annotations, so shared-GPU nodes are
+// not overcommitted. Among fitting GPUs the one with the least free VRAM wins.
+//
+// # Fractional GPU Sharing
+//
+// Pods annotated scheduling.kubenexus.io/gpu-sharing: "true" take a slice of one
+// GPU bounded by their vram-request, alongside other shared pods. A GPU holds at
+// most gpu.kubenexus.io/max-tenants shared pods, and only adjacent tenant tiers
+// mix (no bronze on a GPU shared with gold). The chosen GPUs are recorded in the
+// scheduling.kubenexus.io/gpu-devices annotation at PreBind.
+//
+// # MIG Partitions
+//
+// MIG partitions come from DRA devices with type=mig (profile, memory capacity,
+// parent GPU) or from GPU Feature Discovery labels in mixed strategy. Pods asking
+// for nvidia.com/mig-<profile> or scheduling.kubenexus.io/mig-profile get a free
+// partition of that profile; VRAM-only pods get the smallest partition that holds
+// their request, falling back to whole GPUs. By default partitions are packed onto
+// GPUs that already have partitions in use, keeping pristine GPUs whole;
+// scheduling.kubenexus.io/mig-placement: spread does the opposite.
+//
+// # Rail-Aligned GPU Selection
+//
+// On rail-optimized fabrics GPU i pairs with NIC i, and each rail has its own leaf
+// switch. DRA devices report it in the rail and rail-switch attributes. Gang members
+// with a tensor-parallel or pipeline-parallel degree above 1 take GPUs in rail order
+// rather than tightest fit, so rank i of every member sits on rail i and
+// NetworkFabricScore can line the nodes up by rail switch.
+//
 // # Migration Path
 //
 // Operators can migrate incrementally:
@@ -81,6 +126,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -91,8 +137,10 @@ import (
 	klog "k8s.io/klog/v2"
 	"k8s.io/kube-scheduler/framework"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/gpucatalog"
 	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
 	schedulermetrics "github.com/kube-nexus/kubenexus-scheduler/pkg/scheduler"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/snapshot"
 )
 
 const (
@@ -102,14 +150,18 @@ const (
 	// Pod annotations for VRAM requirements (fallback for non-DRA clusters or explicit hints)
 	// Modern approach: Use DRA ResourceClaims with memory capacity
 	// Legacy approach: Use these annotations
-	AnnotationVRAMRequest = "scheduling.kubenexus.io/vram-request" // e.g., "80Gi", "24Gi"
-	AnnotationModelSize   = "scheduling.kubenexus.io/model-size"   // e.g., "70B", "7B" (informational)
+	AnnotationVRAMRequest  = "scheduling.kubenexus.io/vram-request"  // e.g., "80Gi", "24Gi"
+	AnnotationModelSize    = "scheduling.kubenexus.io/model-size"    // e.g., "70B", "7B"; VRAM is estimated when vram-request is absent
+	AnnotationGPUSharing   = "scheduling.kubenexus.io/gpu-sharing"   // "true": share a GPU, bounded by vram-request; "mig": take a MIG partition
+	AnnotationGPUDevices   = "scheduling.kubenexus.io/gpu-devices"   // Set by the scheduler: assigned GPUs, e.g. "gpu-0,gpu-1"
+	AnnotationVRAMEstimate = "scheduling.kubenexus.io/vram-estimate" // Set by the scheduler: VRAM estimated from model-size, e.g. "152Gi"
 
 	// Node labels for GPU VRAM capacity (per-GPU) - fallback when DRA ResourceSlices unavailable
-	LabelGPUVRAM     = "gpu.kubenexus.io/vram"     // e.g., "80Gi", "40Gi", "24Gi"
-	LabelGPUModel    = "gpu.kubenexus.io/model"    // e.g., "H100", "A100-80GB", "L40S"
-	LabelGPUCount    = "gpu.kubenexus.io/count"    // Total GPUs on node
-	LabelGPUTopology = "topology.kubenexus.io/gpu" // e.g., "nvswitch", "nvlink"
+	LabelGPUVRAM       = "gpu.kubenexus.io/vram"        // e.g., "80Gi", "40Gi", "24Gi"
+	LabelGPUModel      = "gpu.kubenexus.io/model"       // e.g., "H100", "A100-80GB", "L40S"
+	LabelGPUCount      = "gpu.kubenexus.io/count"       // Total GPUs on node
+	LabelGPUTopology   = "topology.kubenexus.io/gpu"    // e.g., "nvswitch", "nvlink"
+	LabelGPUMaxTenants = "gpu.kubenexus.io/max-tenants" // Max shared pods per GPU (default 4)
 
 	// NodeResourceTopology zones for per-GPU VRAM
 	NRTZoneGPUPrefix = "gpu-"                  // e.g., "gpu-0", "gpu-1"
@@ -170,6 +222,7 @@ type GPUDevice struct {
 	PCIeSwitch   string   // PCIe switch identifier
 	NVLinkPeers  []string // List of GPU names with NVLink connections
 	NVLinkDomain int      // NVLink domain/island ID (-1 if unknown)
+	Rail         int      // Rail of the GPU's paired NIC (-1 if unknown)
 }
 
 // VRAMScheduler implements VRAM-aware scheduling to prevent OOM and optimize VRAM utilization
@@ -183,6 +236,13 @@ type VRAMScheduler struct {
 	resourceSliceLister         resourcev1listers.ResourceSliceLister
 	resourceClaimLister         resourcev1listers.ResourceClaimLister
 	resourceClaimTemplateLister resourcev1listers.ResourceClaimTemplateLister
+
+	// index caches the cycle's ResourceSlices per node
+	index *snapshot.Builder
+
+	// assignments holds GPUs picked at Reserve until PreBind records them on the pod
+	assignmentsLock sync.Mutex
+	assignments     map[string]reservedGPUs
 }
 
 // Ensure VRAMScheduler implements required interfaces
@@ -216,7 +276,13 @@ func (v *VRAMScheduler) Score(ctx context.Context, state framework.CycleState, p
 	}
 
 	// Check if pod requests VRAM (using DRA-first fallback chain)
-	vramRequest := v.getVRAMRequest(ctx, pod)
+	vramRequest := v.getVRAMRequest(ctx, state, pod)
+	if req, ok := podMIGRequest(pod, vramRequest); ok && v.useMIG(ctx, state, pod, nodeInfo, req) {
+		if score, handled := v.scoreMIG(state, pod, nodeInfo, req); handled {
+			schedulermetrics.VRAMPlacementDecisions.WithLabelValues("mig", workloadType, "none").Inc()
+			return score, framework.NewStatus(framework.Success)
+		}
+	}
 	if vramRequest == 0 {
 		// No VRAM request - return neutral score
 		klog.V(5).InfoS("Pod has no VRAM request, scoring neutrally",
@@ -229,10 +295,10 @@ func (v *VRAMScheduler) Score(ctx context.Context, state framework.CycleState, p
 	schedulermetrics.VRAMRequestedBytes.WithLabelValues(pod.Namespace, workloadType).Observe(float64(vramRequest))
 
 	// Get GPU VRAM capacity and topology from node (now reading from DRA ResourceSlices)
-	gpuVRAM, gpuCount := v.getNodeGPUVRAM(ctx, node)
-	_, gpuDevices := v.getNodeGPUTopology(ctx, node)
+	_, gpuDevices := v.getNodeGPUTopology(ctx, state, node)
+	gpuCount := len(gpuDevices)
 
-	if gpuVRAM == 0 {
+	if gpuCount == 0 {
 		// Node has no GPU or VRAM info
 		klog.V(5).InfoS("Node has no GPU VRAM information",
 			"node", node.Name, "pod", pod.Name)
@@ -241,52 +307,34 @@ func (v *VRAMScheduler) Score(ctx context.Context, state framework.CycleState, p
 		return ScoreInsufficientVRAM, framework.NewStatus(framework.Success)
 	}
 
-	// Calculate how many GPUs the pod needs based on GPU request
-	gpusRequested := getGPURequest(pod)
-	if gpusRequested == 0 && vramRequest > 0 {
-		// Only default to 1 GPU if VRAM was explicitly requested
-		gpusRequested = 1
-	}
-
-	// Calculate total VRAM needed and available
+	// Place the request on the GPUs with the least free VRAM that still fit it,
+	// after subtracting what pods already on the node hold
+	req := v.podGPURequest(state, pod, node, vramRequest)
+	gpusRequested := req.count
 	totalVRAMNeeded := vramRequest
-	totalVRAMPerGPU := gpuVRAM // VRAM per single GPU
-
-	// Check if single GPU has enough VRAM
-	if totalVRAMNeeded > totalVRAMPerGPU {
-		// Need to check if multi-GPU setup can handle it
-		totalAvailableVRAM := totalVRAMPerGPU * int64(gpusRequested)
-		if totalVRAMNeeded > totalAvailableVRAM {
-			klog.V(4).InfoS("Insufficient VRAM for pod",
-				"pod", pod.Name,
-				"node", node.Name,
-				"vramNeeded", formatBytes(totalVRAMNeeded),
-				"vramPerGPU", formatBytes(totalVRAMPerGPU),
-				"gpusRequested", gpusRequested,
-				"totalAvailable", formatBytes(totalAvailableVRAM))
-			schedulermetrics.VRAMPlacementDecisions.WithLabelValues("insufficient_vram", workloadType, "unknown").Inc()
-			schedulermetrics.GPUAllocationFailures.WithLabelValues("insufficient_vram", workloadType).Inc()
-			return ScoreInsufficientVRAM, framework.NewStatus(framework.Success)
-		}
-		// Multi-GPU case: calculate utilization across all GPUs
-		utilizationRatio := float64(totalVRAMNeeded) / float64(totalAvailableVRAM)
-		tenantTier := v.getTenantTierFromProfile(state, pod)
-		score := calculateUtilizationScore(utilizationRatio, tenantTier)
-
-		klog.V(4).InfoS("Multi-GPU VRAM scheduling",
+	vramPerGPU := req.perGPU
+	occupancy := v.buildGPUOccupancy(nodeInfo, gpuDevices)
+	selected := selectGPUs(occupancy, req)
+	if selected == nil {
+		klog.V(4).InfoS("Insufficient free VRAM for pod",
 			"pod", pod.Name,
 			"node", node.Name,
 			"vramNeeded", formatBytes(totalVRAMNeeded),
+			"vramPerGPU", formatBytes(vramPerGPU),
 			"gpusRequested", gpusRequested,
-			"totalAvailable", formatBytes(totalAvailableVRAM),
-			"utilization", fmt.Sprintf("%.1f%%", utilizationRatio*100),
-			"score", score)
-
-		return score, framework.NewStatus(framework.Success)
+			"shared", req.shared,
+			"largestFreeVRAM", formatBytes(largestFreeVRAM(occupancy)))
+		schedulermetrics.VRAMPlacementDecisions.WithLabelValues("insufficient_vram", workloadType, "unknown").Inc()
+		schedulermetrics.GPUAllocationFailures.WithLabelValues("insufficient_vram", workloadType).Inc()
+		return ScoreInsufficientVRAM, framework.NewStatus(framework.Success)
 	}
 
-	// Single GPU case: calculate utilization
-	utilizationRatio := float64(totalVRAMNeeded) / float64(totalVRAMPerGPU)
+	// Tightest per-device fit: how much of the chosen GPUs' free VRAM the pod uses
+	var freeOnSelected int64
+	for _, i := range selected {
+		freeOnSelected += occupancy[i].freeVRAM()
+	}
+	utilizationRatio := float64(vramPerGPU*int64(gpusRequested)) / float64(freeOnSelected)
 
 	// Get tenant tier for threshold adjustment
 	tenantTier := v.getTenantTierFromProfile(state, pod)
@@ -312,7 +360,7 @@ func (v *VRAMScheduler) Score(ctx context.Context, state framework.CycleState, p
 			"pod", pod.Name,
 			"node", node.Name,
 			"utilization", fmt.Sprintf("%.1f%%", utilizationRatio*100),
-			"strandedVRAM", formatBytes(totalVRAMPerGPU-totalVRAMNeeded),
+			"strandedVRAM", formatBytes(freeOnSelected-vramPerGPU*int64(gpusRequested)),
 			"finalScore", score)
 	}
 
@@ -353,14 +401,14 @@ func (v *VRAMScheduler) Score(ctx context.Context, state framework.CycleState, p
 	if gpuModel == "" {
 		gpuModel = "unknown"
 	}
-	schedulermetrics.VRAMNodeUtilization.WithLabelValues(node.Name, gpuModel).Set(utilizationRatio * 100)
+	schedulermetrics.VRAMNodeUtilization.WithLabelValues(node.Name, gpuModel).Set(nodeVRAMUtilization(occupancy) * 100)
 
 	klog.V(4).InfoS("VRAM-aware scheduling score",
 		"pod", pod.Name,
 		"namespace", pod.Namespace,
 		"node", node.Name,
 		"vramRequest", formatBytes(totalVRAMNeeded),
-		"gpuVRAM", formatBytes(totalVRAMPerGPU),
+		"freeVRAMOnSelected", formatBytes(freeOnSelected),
 		"gpuCount", gpuCount,
 		"gpusRequested", gpusRequested,
 		"utilization", fmt.Sprintf("%.1f%%", utilizationRatio*100),
@@ -508,44 +556,60 @@ func (v *VRAMScheduler) Filter(ctx context.Context, state framework.CycleState,
 	}
 
 	// Check if pod requests VRAM (using DRA-first fallback chain)
-	vramRequest := v.getVRAMRequest(ctx, pod)
+	vramRequest := v.getVRAMRequest(ctx, state, pod)
+	if req, ok := podMIGRequest(pod, vramRequest); ok && v.useMIG(ctx, state, pod, nodeInfo, req) {
+		if status, handled := v.filterMIG(state, pod, nodeInfo, req); handled {
+			return status
+		}
+	}
 	if vramRequest == 0 {
 		// No VRAM requirement - allow scheduling
 		return framework.NewStatus(framework.Success)
 	}
 
-	// Get GPU VRAM capacity from node (using DRA-first fallback chain)
-	gpuVRAM, _ := v.getNodeGPUVRAM(ctx, node)
-	if gpuVRAM == 0 {
+	// Get GPU devices from node (using DRA-first fallback chain)
+	_, gpuDevices := v.getNodeGPUTopology(ctx, state, node)
+	if len(gpuDevices) == 0 {
 		// Node has no GPU VRAM info - filter out
 		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
 			fmt.Sprintf("node %s has no GPU VRAM information", node.Name))
 	}
 
-	// Get number of GPUs requested
-	gpusRequested := getGPURequest(pod)
-	if gpusRequested == 0 {
-		gpusRequested = 1
-	}
-
-	// Calculate total available VRAM
-	totalAvailableVRAM := gpuVRAM * int64(gpusRequested)
-
-	// Filter out if insufficient VRAM
-	if vramRequest > totalAvailableVRAM {
-		return framework.NewStatus(framework.Unschedulable,
-			fmt.Sprintf("insufficient VRAM: need %s, available %s (%d GPUs × %s)",
+	// Each requested GPU must have its share of the request free, after
+	// subtracting the VRAM held by pods already bound to the node
+	req := v.podGPURequest(state, pod, node, vramRequest)
+	occupancy := v.buildGPUOccupancy(nodeInfo, gpuDevices)
+	selected := selectGPUs(occupancy, req)
+	if selected == nil {
+		if req.shared {
+			return framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("no shareable GPU: need %s free on a GPU with fewer than %d tenants and no tier conflict with %q, largest free on one GPU is %s",
+					formatBytes(req.perGPU),
+					req.maxTenants,
+					req.tier,
+					formatBytes(largestFreeVRAM(occupancy))))
+		}
+		status := framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("insufficient VRAM: need %s (%d GPUs × %s free), largest free on one GPU is %s",
 				formatBytes(vramRequest),
-				formatBytes(totalAvailableVRAM),
-				gpusRequested,
-				formatBytes(gpuVRAM)))
+				req.count,
+				formatBytes(req.perGPU),
+				formatBytes(largestFreeVRAM(occupancy))))
+		if est := v.estimatedVRAM(state, pod); est != nil {
+			status.AppendReason(est.String())
+		}
+		return status
+	}
+	if req.railAligned {
+		recordSelectedRails(state, node.Name, occupancy, selected)
 	}
 
 	klog.V(5).InfoS("Node passes VRAM filter",
 		"pod", pod.Name,
 		"node", node.Name,
 		"vramRequest", formatBytes(vramRequest),
-		"availableVRAM", formatBytes(totalAvailableVRAM))
+		"vramPerGPU", formatBytes(req.perGPU),
+		"shared", req.shared)
 
 	return framework.NewStatus(framework.Success)
 }
@@ -626,7 +690,7 @@ func (v *VRAMScheduler) getTenantTierFromProfile(state framework.CycleState, pod
 //  2. Annotation scheduling.kubenexus.io/vram-request (any K8s version, manual or operator-set)
 //
 // Returns VRAM requirement in bytes, or 0 if not specified.
-func (v *VRAMScheduler) getVRAMRequest(ctx context.Context, pod *v1.Pod) int64 {
+func (v *VRAMScheduler) getVRAMRequest(ctx context.Context, state framework.CycleState, pod *v1.Pod) int64 {
 	// PRIORITY 1: DRA ResourceClaims (Kubernetes 1.26+)
 	if len(pod.Spec.ResourceClaims) > 0 {
 		vram, err := v.getVRAMFromResourceClaim(ctx, pod)
@@ -662,6 +726,16 @@ func (v *VRAMScheduler) getVRAMRequest(ctx context.Context, pod *v1.Pod) int64 {
 		return vramBytes
 	}
 
+	// PRIORITY 3: Estimate from model size (parameter count, precision, context, mode)
+	if est := estimateVRAMFromModel(pod, podWorkloadType(state, pod)); est != nil {
+		klog.V(4).InfoS("✅ Using VRAM estimate from model size",
+			"pod", klog.KObj(pod),
+			"source", "model-size",
+			"modelSize", est.ModelSize,
+			"vram", formatBytes(est.Total))
+		return est.Total
+	}
+
 	// No VRAM requirement specified - pod will be scored based on GPU count only
 	klog.V(6).InfoS("No VRAM requirement specified for pod",
 		"pod", klog.KObj(pod),
@@ -669,28 +743,6 @@ func (v *VRAMScheduler) getVRAMRequest(ctx context.Context, pod *v1.Pod) int64 {
 	return 0
 }
 
-// getNodeGPUVRAM extracts GPU VRAM capacity from DRA Resource Slices (returns per-GPU VRAM in bytes and total GPU count)
-// This function now queries the Kubernetes API for ResourceSlices associated with the node
-func (v *VRAMScheduler) getNodeGPUVRAM(ctx context.Context, node *v1.Node) (int64, int) {
-	// New: also return GPU topology information
-	_, gpuDevices := v.getNodeGPUTopology(ctx, node)
-
-	if len(gpuDevices) == 0 {
-		// Fallback to labels
-		return getNodeGPUVRAMFromLabels(node)
-	}
-
-	// Use minimum VRAM for heterogeneous GPUs
-	var vramPerGPU int64
-	for _, gpu := range gpuDevices {
-		if vramPerGPU == 0 || gpu.VRAM < vramPerGPU {
-			vramPerGPU = gpu.VRAM
-		}
-	}
-
-	return vramPerGPU, len(gpuDevices)
-}
-
 // getNodeGPUTopology extracts full GPU topology information using priority-based fallback chain.
 //
 // 3-Tier Fallback Strategy (for Kubernetes version compatibility):
@@ -699,13 +751,13 @@ func (v *VRAMScheduler) getNodeGPUVRAM(ctx context.Context, node *v1.Node) (int6
 //  3. Manual node labels (any K8s version, operator-managed fallback)
 //
 // Returns (vramPerGPU, []GPUDevice with full topology)
-func (v *VRAMScheduler) getNodeGPUTopology(ctx context.Context, node *v1.Node) (int64, []GPUDevice) {
+func (v *VRAMScheduler) getNodeGPUTopology(ctx context.Context, state framework.CycleState, node *v1.Node) (int64, []GPUDevice) {
 	// PRIORITY 1: DRA ResourceSlices (Kubernetes 1.34+)
 	// Provides: Full topology (VRAM, NUMA, PCIe, NVLink), dynamic updates
-	if v.resourceSliceLister != nil {
+	if v.resourceSliceLister != nil || v.index.Get(state) != nil {
 		// Add 5-second timeout to DRA queries to prevent scheduler stalls
 		draCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		vramPerGPU, devices, err := v.getGPUTopologyFromDRA(draCtx, node)
+		vramPerGPU, devices, err := v.getGPUTopologyFromDRA(draCtx, state, node)
 		cancel()
 
 		if err == nil && len(devices) > 0 {
@@ -762,6 +814,7 @@ func (v *VRAMScheduler) getNodeGPUTopology(ctx context.Context, node *v1.Node) (
 			VRAM:         vramPerGPU,
 			NUMANode:     -1, // Unknown from manual labels
 			NVLinkDomain: -1, // Unknown from manual labels
+			Rail:         -1, // Unknown from manual labels
 		}
 	}
 	return vramPerGPU, devices
@@ -807,7 +860,10 @@ func getNodeGPUVRAMFromLabels(node *v1.Node) (int64, int) {
 
 	// Fallback: Infer from GPU model label
 	if gpuModel, ok := node.Labels[LabelGPUModel]; ok {
-		vramBytes := inferVRAMFromModel(gpuModel)
+		var vramBytes int64
+		if model, found := gpucatalog.Get().LookupModel(gpuModel); found {
+			vramBytes = model.VRAMBytes()
+		}
 		if vramBytes > 0 {
 			// Get GPU count
 			gpuCount := 1
@@ -827,55 +883,11 @@ func getNodeGPUVRAMFromLabels(node *v1.Node) (int64, int) {
 	return 0, 0
 }
 
-// inferVRAMFromModel infers VRAM capacity from GPU model name
-func inferVRAMFromModel(gpuModel string) int64 {
-	model := strings.ToUpper(gpuModel)
-
-	// Map of known GPU models to VRAM capacity (in bytes)
-	// Check specific models first, then fall back to base models
-	vramMap := []struct {
-		modelKey string
-		vram     int64
-	}{
-		// Specific models first (longest match wins)
-		{"H100-80GB", 80 * 1024 * 1024 * 1024},
-		{"H200", 141 * 1024 * 1024 * 1024},
-		{"H100", 80 * 1024 * 1024 * 1024},
-		{"A100-80GB", 80 * 1024 * 1024 * 1024},
-		{"A100-40GB", 40 * 1024 * 1024 * 1024},
-		{"A100", 40 * 1024 * 1024 * 1024},
-		{"L40S", 48 * 1024 * 1024 * 1024}, // Check L40S before L40
-		{"L40", 48 * 1024 * 1024 * 1024},
-		{"L4", 24 * 1024 * 1024 * 1024},
-		{"A40", 48 * 1024 * 1024 * 1024},
-		{"A30", 24 * 1024 * 1024 * 1024},
-		{"T4", 16 * 1024 * 1024 * 1024},
-		{"V100-32GB", 32 * 1024 * 1024 * 1024},
-		{"V100-16GB", 16 * 1024 * 1024 * 1024},
-		{"V100", 32 * 1024 * 1024 * 1024},
-		{"RTX8000", 48 * 1024 * 1024 * 1024},
-		{"RTX6000", 24 * 1024 * 1024 * 1024},
-		{"MI300", 192 * 1024 * 1024 * 1024}, // AMD MI300X
-	}
-
-	for _, entry := range vramMap {
-		if strings.Contains(model, entry.modelKey) {
-			return entry.vram
-		}
-	}
-
-	return 0 // Unknown model
-}
-
-// isHighEndGPU checks if node has high-end GPUs (H100, H200, A100-80GB)
+// isHighEndGPU checks if node has high-end GPUs: premium-tier models in the GPU catalog
 func isHighEndGPU(node *v1.Node) bool {
 	if gpuModel, ok := node.Labels[LabelGPUModel]; ok {
-		model := strings.ToUpper(gpuModel)
-		highEndModels := []string{"H100", "H200", "A100-80GB", "MI300"}
-		for _, highEnd := range highEndModels {
-			if strings.Contains(model, highEnd) {
-				return true
-			}
+		if model, found := gpucatalog.Get().LookupModel(gpuModel); found {
+			return model.Tier == gpucatalog.TierPremium
 		}
 	}
 	return false
@@ -907,9 +919,16 @@ func formatBytes(bytes int64) string {
 }
 
 // New creates a new VRAMScheduler plugin
-func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+func New(ctx context.Context, _ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	klog.V(3).InfoS("Creating new VRAMScheduler plugin with DRA ResourceSlice support")
 
+	// VRAM of NFD-discovered and model-labeled GPUs comes from the shared GPU catalog
+	if handle.ClientSet() != nil {
+		if err := gpucatalog.Watch(ctx, handle.ClientSet()); err != nil {
+			return nil, fmt.Errorf("failed to watch GPU catalog: %w", err)
+		}
+	}
+
 	// Get clientset from scheduler handle for ResourceSlice queries
 	var clientset kubernetes.Interface
 	kubeConfig := handle.KubeConfig()
@@ -947,5 +966,7 @@ func New(_ context.Context, _ runtime.Object, handle framework.Handle) (framewor
 		resourceSliceLister:         resourceSliceLister,
 		resourceClaimLister:         resourceClaimLister,
 		resourceClaimTemplateLister: resourceClaimTemplateLister,
+		index:                       snapshot.NewBuilder(handle),
+		assignments:                 make(map[string]reservedGPUs),
 	}, nil
 }
@@ -18,15 +18,19 @@ package vramscheduler
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
 	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
 )
 
@@ -129,7 +133,7 @@ func TestGetVRAMRequest(t *testing.T) {
 					ResourceClaims: tt.resourceClaims,
 				},
 			}
-			vram := scheduler.getVRAMRequest(ctx, pod)
+			vram := scheduler.getVRAMRequest(ctx, framework.NewCycleState(), pod)
 			if vram != tt.expectedVRAM {
 				t.Errorf("Expected VRAM %d bytes, got %d bytes", tt.expectedVRAM, vram)
 			}
@@ -137,34 +141,6 @@ func TestGetVRAMRequest(t *testing.T) {
 	}
 }
 
-func TestInferVRAMFromModel(t *testing.T) {
-	tests := []struct {
-		model    string
-		expected int64
-	}{
-		{"H100", 80 * 1024 * 1024 * 1024},
-		{"H100-80GB", 80 * 1024 * 1024 * 1024},
-		{"H200", 141 * 1024 * 1024 * 1024},
-		{"A100-80GB", 80 * 1024 * 1024 * 1024},
-		{"A100", 40 * 1024 * 1024 * 1024},
-		{"A40", 48 * 1024 * 1024 * 1024},
-		{"L40S", 48 * 1024 * 1024 * 1024},
-		{"L4", 24 * 1024 * 1024 * 1024},
-		{"T4", 16 * 1024 * 1024 * 1024},
-		{"V100", 32 * 1024 * 1024 * 1024},
-		{"UNKNOWN-GPU", 0},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.model, func(t *testing.T) {
-			vram := inferVRAMFromModel(tt.model)
-			if vram != tt.expected {
-				t.Errorf("Model %s: expected %d bytes, got %d bytes", tt.model, tt.expected, vram)
-			}
-		})
-	}
-}
-
 func TestIsHighEndGPU(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -861,3 +837,875 @@ func TestCheckPCIeLocality(t *testing.T) {
 		})
 	}
 }
+
+// vramPod builds a pod requesting the given VRAM and GPU count
+func vramPod(name, nodeName, vram string, gpus string) *v1.Pod {
+	annotations := map[string]string{}
+	if vram != "" {
+		annotations[AnnotationVRAMRequest] = vram
+	}
+	requests := v1.ResourceList{}
+	if gpus != "" {
+		requests[ResourceGPU] = resource.MustParse(gpus)
+	}
+	return testutil.MakePod(name, "default", nodeName, requests, nil, annotations)
+}
+
+func TestBuildGPUOccupancy(t *testing.T) {
+	devices := []GPUDevice{
+		{Name: "gpu-0", VRAM: 80 * GiB},
+		{Name: "gpu-1", VRAM: 80 * GiB},
+		{Name: "gpu-2", VRAM: 80 * GiB},
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*v1.Pod
+		expected []int64 // used VRAM per device, in GiB
+	}{
+		{
+			name:     "empty node",
+			expected: []int64{0, 0, 0},
+		},
+		{
+			name: "VRAM requests share the tightest GPU",
+			pods: []*v1.Pod{
+				vramPod("a", "node", "30Gi", "1"),
+				vramPod("b", "node", "40Gi", "1"),
+			},
+			expected: []int64{70, 0, 0},
+		},
+		{
+			name: "multi-GPU request is split evenly",
+			pods: []*v1.Pod{
+				vramPod("a", "node", "100Gi", "2"),
+			},
+			expected: []int64{50, 50, 0},
+		},
+		{
+			name: "whole-GPU request without VRAM annotation takes a free device",
+			pods: []*v1.Pod{
+				vramPod("a", "node", "20Gi", "1"),
+				vramPod("b", "node", "", "1"),
+			},
+			expected: []int64{20, 80, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(tt.pods...)
+			nodeInfo.SetNode(testutil.MakeNode("node", nil, nil))
+
+			occupancy := (&VRAMScheduler{}).buildGPUOccupancy(nodeInfo, devices)
+			for i, want := range tt.expected {
+				if got := occupancy[i].UsedVRAM / GiB; got != want {
+					t.Errorf("%s used = %dGi, want %dGi", occupancy[i].Device.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterAccountsForBoundPods(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("shared-node", map[string]string{
+			LabelGPUModel: "H100",
+			LabelGPUVRAM:  "80Gi",
+			LabelGPUCount: "2",
+		}, v1.ResourceList{ResourceGPU: resource.MustParse("2")}),
+	}
+	existing := []*v1.Pod{
+		vramPod("existing-0", "shared-node", "60Gi", "1"),
+		vramPod("existing-1", "shared-node", "50Gi", "1"),
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		shouldPass bool
+	}{
+		{"fits remaining 30Gi", vramPod("small", "", "30Gi", "1"), true},
+		{"exceeds largest free GPU", vramPod("medium", "", "40Gi", "1"), false},
+		{"two GPUs with 20Gi each", vramPod("split", "", "40Gi", "2"), true},
+	}
+
+	fh, err := testutil.NewTestFrameworkWithPods(existing, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	plugin, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	filterPlugin := plugin.(fwk.FilterPlugin)
+
+	nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get("shared-node")
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := filterPlugin.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+			if status.IsSuccess() != tt.shouldPass {
+				t.Errorf("expected pass=%v, got pass=%v (status: %v)", tt.shouldPass, status.IsSuccess(), status.Message())
+			}
+		})
+	}
+}
+
+func TestScorePrefersTightestPerGPUFit(t *testing.T) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("empty-node", map[string]string{
+			LabelGPUModel: "L40S",
+			LabelGPUVRAM:  "48Gi",
+			LabelGPUCount: "1",
+		}, v1.ResourceList{ResourceGPU: resource.MustParse("1")}),
+		testutil.MakeNode("partial-node", map[string]string{
+			LabelGPUModel: "L40S",
+			LabelGPUVRAM:  "48Gi",
+			LabelGPUCount: "1",
+		}, v1.ResourceList{ResourceGPU: resource.MustParse("1")}),
+	}
+	existing := []*v1.Pod{
+		vramPod("existing", "partial-node", "24Gi", "1"),
+	}
+
+	fh, err := testutil.NewTestFrameworkWithPods(existing, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	plugin, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	scorePlugin := plugin.(fwk.ScorePlugin)
+
+	pod := vramPod("new", "", "23Gi", "1")
+	scores := map[string]int64{}
+	for _, node := range nodes {
+		nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(node.Name)
+		if err != nil {
+			t.Fatalf("Failed to get node %s: %v", node.Name, err)
+		}
+		score, status := scorePlugin.Score(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+		if !status.IsSuccess() {
+			t.Fatalf("Score failed on %s: %v", node.Name, status.AsError())
+		}
+		scores[node.Name] = score
+	}
+
+	if scores["partial-node"] <= scores["empty-node"] {
+		t.Errorf("expected partially used GPU to score higher than empty GPU, got %v", scores)
+	}
+}
+
+// sharedPod builds a GPU-sharing pod with a published tenant tier
+func sharedPod(name, nodeName, vram, tier string) *v1.Pod {
+	pod := vramPod(name, nodeName, vram, "")
+	pod.Annotations[AnnotationGPUSharing] = "true"
+	if tier != "" {
+		pod.Annotations[profileclassifier.AnnotationProfileTier] = tier
+	}
+	return pod
+}
+
+func TestTiersCanShare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"gold", "gold", true},
+		{"gold", "silver", true},
+		{"silver", "bronze", true},
+		{"gold", "bronze", false},
+		{"bronze", "gold", false},
+		{"gold", "unknown", false},
+		{"bronze", "unknown", false},
+		{"unknown", "unknown", false},
+		{"", "silver", false},
+	}
+
+	for _, tt := range tests {
+		if got := tiersCanShare(tt.a, tt.b); got != tt.expected {
+			t.Errorf("tiersCanShare(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestSharedGPUFilter(t *testing.T) {
+	node := testutil.MakeNode("shared-node", map[string]string{
+		LabelGPUModel:      "H100",
+		LabelGPUVRAM:       "80Gi",
+		LabelGPUCount:      "1",
+		LabelGPUMaxTenants: "2",
+	}, v1.ResourceList{ResourceGPU: resource.MustParse("1")})
+
+	tests := []struct {
+		name       string
+		existing   []*v1.Pod
+		tier       profileclassifier.TenantTier
+		shouldPass bool
+	}{
+		{
+			name:       "joins a compatible tenant",
+			existing:   []*v1.Pod{sharedPod("a", "shared-node", "20Gi", "gold")},
+			tier:       profileclassifier.TierSilver,
+			shouldPass: true,
+		},
+		{
+			name:       "bronze never shares with gold",
+			existing:   []*v1.Pod{sharedPod("a", "shared-node", "20Gi", "gold")},
+			tier:       profileclassifier.TierBronze,
+			shouldPass: false,
+		},
+		{
+			name: "max tenants reached",
+			existing: []*v1.Pod{
+				sharedPod("a", "shared-node", "10Gi", "silver"),
+				sharedPod("b", "shared-node", "10Gi", "silver"),
+			},
+			tier:       profileclassifier.TierSilver,
+			shouldPass: false,
+		},
+		{
+			name:       "exclusive GPU is not shared",
+			existing:   []*v1.Pod{vramPod("a", "shared-node", "20Gi", "1")},
+			tier:       profileclassifier.TierSilver,
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fh, err := testutil.NewTestFrameworkWithPods(tt.existing, []*v1.Node{node}, nil)
+			if err != nil {
+				t.Fatalf("Failed to create framework: %v", err)
+			}
+			plugin, err := New(context.Background(), nil, fh)
+			if err != nil {
+				t.Fatalf("Failed to create plugin: %v", err)
+			}
+			nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(node.Name)
+			if err != nil {
+				t.Fatalf("Failed to get node: %v", err)
+			}
+
+			// ProfileClassifier stores the profile under its plugin name
+			state := framework.NewCycleState()
+			state.Write(profileclassifier.Name, &profileclassifier.SchedulingProfile{TenantTier: tt.tier})
+
+			status := plugin.(fwk.FilterPlugin).Filter(context.Background(), state, sharedPod("new", "", "20Gi", ""), nodeInfo)
+			if status.IsSuccess() != tt.shouldPass {
+				t.Errorf("expected pass=%v, got pass=%v (status: %v)", tt.shouldPass, status.IsSuccess(), status.Message())
+			}
+		})
+	}
+}
+
+func TestSharedGPUFilterUsesReservedTier(t *testing.T) {
+	node := testutil.MakeNode("shared-node", map[string]string{
+		LabelGPUModel: "H100",
+		LabelGPUVRAM:  "80Gi",
+		LabelGPUCount: "1",
+	}, v1.ResourceList{ResourceGPU: resource.MustParse("1")})
+	// Assumed on the node, before ProfileClassifier publishes its tier
+	binding := sharedPod("binding", "shared-node", "20Gi", "")
+
+	fh, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{binding}, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+	nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(node.Name)
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+
+	reserveState := framework.NewCycleState()
+	reserveState.Write(profileclassifier.Name, &profileclassifier.SchedulingProfile{TenantTier: profileclassifier.TierGold})
+	plugin.recordAssignment(reserveState, binding, []string{"gpu-0"}, true)
+
+	state := framework.NewCycleState()
+	state.Write(profileclassifier.Name, &profileclassifier.SchedulingProfile{TenantTier: profileclassifier.TierSilver})
+	if status := plugin.Filter(context.Background(), state, sharedPod("new", "", "20Gi", ""), nodeInfo); !status.IsSuccess() {
+		t.Errorf("silver pod next to a reserved gold pod: %v, want Success", status.Message())
+	}
+
+	// Without the reserved tier or a published one, the GPU isn't shared
+	plugin.releaseAssignment(binding)
+	if status := plugin.Filter(context.Background(), state, sharedPod("new", "", "20Gi", ""), nodeInfo); status.IsSuccess() {
+		t.Error("silver pod joined a GPU held by a pod of unknown tier")
+	}
+}
+
+func TestReserveRecordsGPUAssignment(t *testing.T) {
+	node := testutil.MakeNode("gpu-node", map[string]string{
+		LabelGPUModel: "H100",
+		LabelGPUVRAM:  "80Gi",
+		LabelGPUCount: "2",
+	}, v1.ResourceList{ResourceGPU: resource.MustParse("2")})
+	existing := sharedPod("existing", "gpu-node", "40Gi", "silver")
+	existing.Annotations[AnnotationGPUDevices] = "gpu-1"
+	pod := sharedPod("new", "", "30Gi", "")
+
+	fh, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{existing, pod}, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+
+	state := framework.NewCycleState()
+	if status := plugin.Reserve(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("Reserve failed: %v", status.Message())
+	}
+	if got := plugin.recordedGPUs(pod); len(got) != 1 || got[0] != "gpu-1" {
+		t.Errorf("Reserve assigned %v, want the partially used gpu-1", got)
+	}
+
+	if status := plugin.PreBindPreFlight(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("PreBindPreFlight = %v, want Success", status.Code())
+	}
+	if status := plugin.PreBind(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("PreBind failed: %v", status.Message())
+	}
+	updated, err := fh.ClientSet().CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	if got := updated.Annotations[AnnotationGPUDevices]; got != "gpu-1" {
+		t.Errorf("%s = %q, want %q", AnnotationGPUDevices, got, "gpu-1")
+	}
+
+	plugin.PostBind(context.Background(), state, pod, node.Name)
+	if got := plugin.recordedGPUs(pod); got != nil {
+		t.Errorf("assignment not released after PostBind: %v", got)
+	}
+}
+
+// migNode builds a node advertising MIG partitions through GPU Feature Discovery labels
+func migNode(name string, gpus int, profiles map[string]int) *v1.Node {
+	labels := map[string]string{
+		LabelMIGStrategy: "mixed",
+		LabelGFDGPUCount: strconv.Itoa(gpus),
+	}
+	for profile, count := range profiles {
+		labels[MIGResourcePrefix+profile+".count"] = strconv.Itoa(count)
+	}
+	return testutil.MakeNode(name, labels, nil)
+}
+
+// migPod builds a pod requesting MIG partitions through the device plugin resource
+func migPod(name, nodeName, profile string, count int) *v1.Pod {
+	requests := v1.ResourceList{
+		v1.ResourceName(MIGResourcePrefix + profile): *resource.NewQuantity(int64(count), resource.DecimalSI),
+	}
+	return testutil.MakePod(name, "default", nodeName, requests, nil, nil)
+}
+
+func TestParseMIGProfile(t *testing.T) {
+	tests := []struct {
+		profile string
+		slices  int
+		memory  int64
+	}{
+		{"1g.10gb", 1, 10 * GiB},
+		{"3g.40gb", 3, 40 * GiB},
+		{"7g.80gb", 7, 80 * GiB},
+		{"1g.10gb+me", 1, 10 * GiB},
+		{"invalid", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			slices, memory := parseMIGProfile(tt.profile)
+			if slices != tt.slices || memory != tt.memory {
+				t.Errorf("parseMIGProfile(%q) = (%d, %d), want (%d, %d)", tt.profile, slices, memory, tt.slices, tt.memory)
+			}
+		})
+	}
+}
+
+func TestGetMIGDevicesFromLabels(t *testing.T) {
+	node := migNode("mig-node", 2, map[string]int{"3g.40gb": 4})
+	node.Labels[MIGResourcePrefix+"3g.40gb.memory"] = "40192"
+
+	devices := getMIGDevicesFromLabels(node)
+	if len(devices) != 4 {
+		t.Fatalf("got %d partitions, want 4", len(devices))
+	}
+	perParent := map[string]int{}
+	for _, d := range devices {
+		perParent[d.ParentGPU]++
+		if d.Memory != 40192*1024*1024 || d.ComputeSlices != 3 {
+			t.Errorf("%s: memory=%d slices=%d, want label memory and 3 slices", d.Name, d.Memory, d.ComputeSlices)
+		}
+	}
+	if perParent["gpu-0"] != 2 || perParent["gpu-1"] != 2 {
+		t.Errorf("partitions per parent = %v, want 2 on each GPU", perParent)
+	}
+
+	delete(node.Labels, LabelMIGStrategy)
+	if devices := getMIGDevicesFromLabels(node); devices != nil {
+		t.Errorf("expected no partitions without mixed strategy, got %d", len(devices))
+	}
+}
+
+func TestSelectMIGDevices(t *testing.T) {
+	occupancy := []migOccupancy{
+		{Device: MIGDevice{Name: "gpu-0-a", Profile: "1g.10gb", Memory: 10 * GiB, ParentGPU: "gpu-0"}},
+		{Device: MIGDevice{Name: "gpu-0-b", Profile: "3g.40gb", Memory: 40 * GiB, ParentGPU: "gpu-0"}},
+		{Device: MIGDevice{Name: "gpu-1-a", Profile: "1g.10gb", Memory: 10 * GiB, ParentGPU: "gpu-1"}, Used: true},
+		{Device: MIGDevice{Name: "gpu-1-b", Profile: "1g.10gb", Memory: 10 * GiB, ParentGPU: "gpu-1"}},
+		{Device: MIGDevice{Name: "gpu-1-c", Profile: "3g.40gb", Memory: 40 * GiB, ParentGPU: "gpu-1"}},
+	}
+
+	tests := []struct {
+		name     string
+		req      migRequest
+		expected []string
+	}{
+		{"pack fills partially used GPU", migRequest{profile: "1g.10gb", count: 1}, []string{"gpu-1-b"}},
+		{"spread prefers pristine GPU", migRequest{profile: "1g.10gb", count: 1, spread: true}, []string{"gpu-0-a"}},
+		{"VRAM request takes smallest fitting partition", migRequest{count: 1, vram: 20 * GiB}, []string{"gpu-1-c"}},
+		{"too few partitions of the profile", migRequest{profile: "1g.10gb", count: 3}, nil},
+		{"unknown profile", migRequest{profile: "7g.80gb", count: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, i := range selectMIGDevices(occupancy, tt.req) {
+				got = append(got, occupancy[i].Device.Name)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("selected %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("selected %v, want %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestMIGFilter(t *testing.T) {
+	nodes := []*v1.Node{
+		migNode("mig-node", 1, map[string]int{"3g.40gb": 2}),
+		testutil.MakeNode("whole-gpu-node", map[string]string{
+			LabelGPUModel: "H100",
+			LabelGPUVRAM:  "80Gi",
+			LabelGPUCount: "1",
+		}, v1.ResourceList{ResourceGPU: resource.MustParse("1")}),
+	}
+	existing := []*v1.Pod{migPod("existing", "mig-node", "3g.40gb", 1)}
+
+	fh, err := testutil.NewTestFrameworkWithPods(existing, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	plugin, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	filterPlugin := plugin.(fwk.FilterPlugin)
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		node     string
+		expected fwk.Code
+	}{
+		{"free partition left", migPod("one", "", "3g.40gb", 1), "mig-node", fwk.Success},
+		{"partitions exhausted", migPod("two", "", "3g.40gb", 2), "mig-node", fwk.Unschedulable},
+		{"profile not on node", migPod("big", "", "7g.80gb", 1), "mig-node", fwk.Unschedulable},
+		{"node without MIG", migPod("one", "", "3g.40gb", 1), "whole-gpu-node", fwk.UnschedulableAndUnresolvable},
+		{"VRAM-only pod fits a partition", vramPod("vram", "", "30Gi", ""), "mig-node", fwk.Success},
+		{"VRAM-only pod falls back to whole GPUs", vramPod("vram", "", "30Gi", ""), "whole-gpu-node", fwk.Success},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(tt.node)
+			if err != nil {
+				t.Fatalf("Failed to get node: %v", err)
+			}
+			status := filterPlugin.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+			if status.Code() != tt.expected {
+				t.Errorf("Filter = %v, want %v (status: %v)", status.Code(), tt.expected, status.Message())
+			}
+		})
+	}
+}
+
+func TestMIGScorePrefersPartiallyPartitionedGPU(t *testing.T) {
+	nodes := []*v1.Node{
+		migNode("pristine-node", 1, map[string]int{"1g.10gb": 7}),
+		migNode("partial-node", 1, map[string]int{"1g.10gb": 7}),
+	}
+	existing := []*v1.Pod{migPod("existing", "partial-node", "1g.10gb", 2)}
+
+	fh, err := testutil.NewTestFrameworkWithPods(existing, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+
+	scores := map[string]int64{}
+	for _, name := range []string{"pristine-node", "partial-node"} {
+		nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(name)
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		score, status := plugin.Score(context.Background(), framework.NewCycleState(), migPod("new", "", "1g.10gb", 1), nodeInfo)
+		if !status.IsSuccess() {
+			t.Fatalf("Score failed: %v", status.Message())
+		}
+		scores[name] = score
+	}
+	if scores["partial-node"] <= scores["pristine-node"] {
+		t.Errorf("expected partial-node (%d) to outscore pristine-node (%d)", scores["partial-node"], scores["pristine-node"])
+	}
+}
+
+func TestReserveRecordsMIGAssignment(t *testing.T) {
+	node := migNode("mig-node", 2, map[string]int{"3g.40gb": 4})
+	existing := migPod("existing", "mig-node", "3g.40gb", 1)
+	pod := migPod("new", "", "3g.40gb", 1)
+
+	fh, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{existing, pod}, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+
+	if status := plugin.Reserve(context.Background(), framework.NewCycleState(), pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("Reserve failed: %v", status.Message())
+	}
+	// The existing pod was replayed onto gpu-0, so packing picks its sibling partition
+	if got := plugin.recordedGPUs(pod); len(got) != 1 || got[0] != "gpu-0-mig-3g.40gb-1" {
+		t.Errorf("Reserve assigned %v, want [gpu-0-mig-3g.40gb-1]", got)
+	}
+}
+
+func TestVRAMOnlyPodUsesMIGOnlyWhenNeeded(t *testing.T) {
+	// Two whole GPUs, the first also advertising MIG partitions
+	node := migNode("mixed-node", 2, map[string]int{"3g.40gb": 2})
+	node.Labels[LabelGPUModel] = "H100"
+	node.Labels[LabelGPUVRAM] = "80Gi"
+	node.Labels[LabelGPUCount] = "2"
+	node.Status.Allocatable = v1.ResourceList{ResourceGPU: resource.MustParse("2")}
+	optedIn := vramPod("opted-in", "", "30Gi", "")
+	optedIn.Annotations[AnnotationGPUSharing] = GPUSharingMIG
+	tooBig := vramPod("too-big", "", "30Gi", "")
+
+	tests := []struct {
+		name     string
+		existing []*v1.Pod
+		pod      *v1.Pod
+		wantMIG  bool
+	}{
+		{"whole GPU fits", nil, vramPod("vram", "", "30Gi", ""), false},
+		{"opted in", nil, optedIn, true},
+		{"no whole GPU fits", []*v1.Pod{vramPod("a", "mixed-node", "60Gi", "1"), vramPod("b", "mixed-node", "60Gi", "1")}, tooBig, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fh, err := testutil.NewTestFrameworkWithPods(append(tt.existing, tt.pod), []*v1.Node{node}, nil)
+			if err != nil {
+				t.Fatalf("Failed to create framework: %v", err)
+			}
+			p, err := New(context.Background(), nil, fh)
+			if err != nil {
+				t.Fatalf("Failed to create plugin: %v", err)
+			}
+			plugin := p.(*VRAMScheduler)
+
+			if status := plugin.Reserve(context.Background(), framework.NewCycleState(), tt.pod, node.Name); !status.IsSuccess() {
+				t.Fatalf("Reserve failed: %v", status.Message())
+			}
+			got := plugin.recordedGPUs(tt.pod)
+			if len(got) != 1 || strings.Contains(got[0], "-mig-") != tt.wantMIG {
+				t.Errorf("Reserve assigned %v, want MIG=%v", got, tt.wantMIG)
+			}
+		})
+	}
+}
+
+func TestParseModelSize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected float64
+		wantErr  bool
+	}{
+		{"70B", 70e9, false},
+		{"7b", 7e9, false},
+		{"1.5B", 1.5e9, false},
+		{"350M", 350e6, false},
+		{"8x7B", 56e9, false},
+		{"70", 0, true},
+		{"B", 0, true},
+		{"xB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseModelSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseModelSize(%q) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseModelSize(%q) = %g, want %g", tt.size, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimateVRAMFromModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		gpus         string
+		workloadType string
+		minGiB       int64
+		maxGiB       int64
+	}{
+		{
+			name:        "7B bf16 inference fits a 24GB GPU",
+			annotations: map[string]string{AnnotationModelSize: "7B"},
+			minGiB:      15, maxGiB: 20,
+		},
+		{
+			name:        "70B bf16 inference needs multiple 80GB GPUs",
+			annotations: map[string]string{AnnotationModelSize: "70B"},
+			minGiB:      150, maxGiB: 180,
+		},
+		{
+			name:        "int4 quarters the weights",
+			annotations: map[string]string{AnnotationModelSize: "70B", AnnotationModelPrecision: "int4"},
+			minGiB:      40, maxGiB: 50,
+		},
+		{
+			name:        "long context grows the KV cache",
+			annotations: map[string]string{AnnotationModelSize: "70B", AnnotationContextLength: "131072"},
+			minGiB:      190, maxGiB: 220,
+		},
+		{
+			name:         "training workload charges optimizer states",
+			annotations:  map[string]string{AnnotationModelSize: "7B"},
+			workloadType: "training",
+			minGiB:       120, maxGiB: 130,
+		},
+		{
+			name:         "model-mode overrides the workload type",
+			annotations:  map[string]string{AnnotationModelSize: "7B", AnnotationModelMode: "inference"},
+			workloadType: "training",
+			minGiB:       15, maxGiB: 20,
+		},
+		{
+			name:        "per-GPU runtime overhead follows the GPU request",
+			annotations: map[string]string{AnnotationModelSize: "7B"},
+			gpus:        "4",
+			minGiB:      18, maxGiB: 23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := vramPod("model", "", "", tt.gpus)
+			pod.Annotations = tt.annotations
+			est := estimateVRAMFromModel(pod, tt.workloadType)
+			if est == nil {
+				t.Fatal("expected an estimate")
+			}
+			if got := est.Total / GiB; got < tt.minGiB || got > tt.maxGiB {
+				t.Errorf("estimate = %dGi, want %d-%dGi (%s)", got, tt.minGiB, tt.maxGiB, est)
+			}
+		})
+	}
+
+	if est := estimateVRAMFromModel(vramPod("none", "", "", ""), ""); est != nil {
+		t.Errorf("expected no estimate without model-size, got %s", est)
+	}
+}
+
+func TestFilterReportsVRAMEstimate(t *testing.T) {
+	node := testutil.MakeNode("l40s-node", map[string]string{
+		LabelGPUModel: "L40S",
+		LabelGPUVRAM:  "48Gi",
+		LabelGPUCount: "1",
+	}, v1.ResourceList{ResourceGPU: resource.MustParse("1")})
+
+	fh, err := testutil.NewTestFrameworkWithPods(nil, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+	nodeInfo, err := fh.SnapshotSharedLister().NodeInfos().Get(node.Name)
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+
+	pod := vramPod("llama-70b", "", "", "1")
+	pod.Annotations[AnnotationModelSize] = "70B"
+	state := framework.NewCycleState()
+
+	if _, status := plugin.PreFilter(context.Background(), state, pod, nil); !status.IsSuccess() {
+		t.Fatalf("PreFilter failed: %v", status.Message())
+	}
+	status := plugin.Filter(context.Background(), state, pod, nodeInfo)
+	if status.Code() != fwk.Unschedulable {
+		t.Fatalf("Filter = %v, want Unschedulable", status.Code())
+	}
+	if !strings.Contains(status.Message(), "estimated from model-size 70B") {
+		t.Errorf("Filter message doesn't explain the estimate: %q", status.Message())
+	}
+}
+
+func TestBoundPodKeepsTrainingEstimate(t *testing.T) {
+	node := testutil.MakeNode("gpu-node", map[string]string{
+		LabelGPUModel: "H100",
+		LabelGPUVRAM:  "80Gi",
+		LabelGPUCount: "2",
+	}, v1.ResourceList{ResourceGPU: resource.MustParse("2")})
+	pod := vramPod("trainer", "", "", "2")
+	pod.Annotations[AnnotationModelSize] = "7B"
+
+	fh, err := testutil.NewTestFrameworkWithPods([]*v1.Pod{pod}, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	p, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	plugin := p.(*VRAMScheduler)
+
+	state := framework.NewCycleState()
+	state.Write(profileclassifier.Name, &profileclassifier.SchedulingProfile{WorkloadType: profileclassifier.WorkloadTraining})
+	training := estimateVRAMFromModel(pod, string(profileclassifier.WorkloadTraining)).Total
+	if status := plugin.Reserve(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("Reserve failed: %v", status.Message())
+	}
+	// While binding, before ProfileClassifier publishes the workload type
+	if got := plugin.boundPodVRAM(pod); got != training {
+		t.Errorf("boundPodVRAM() after Reserve = %s, want the training estimate %s", formatBytes(got), formatBytes(training))
+	}
+
+	if status := plugin.PreBind(context.Background(), state, pod, node.Name); !status.IsSuccess() {
+		t.Fatalf("PreBind failed: %v", status.Message())
+	}
+	plugin.PostBind(context.Background(), state, pod, node.Name)
+	bound, err := fh.ClientSet().CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	if got := plugin.boundPodVRAM(bound); got != training {
+		t.Errorf("boundPodVRAM() after PreBind = %s, want the training estimate %s (%s=%q)",
+			formatBytes(got), formatBytes(training), AnnotationVRAMEstimate, bound.Annotations[AnnotationVRAMEstimate])
+	}
+}
+
+func TestParseGPUDeviceRailFromDRA(t *testing.T) {
+	rail := int64(3)
+	gpu := (&VRAMScheduler{}).parseGPUDeviceFromDRA(resourcev1.Device{
+		Name: "gpu-3",
+		Attributes: map[resourcev1.QualifiedName]resourcev1.DeviceAttribute{
+			"rail": {IntValue: &rail},
+		},
+	}, "gpu.nvidia.com")
+	if gpu.Rail != 3 {
+		t.Errorf("parsed rail %d, want 3", gpu.Rail)
+	}
+
+	gpu = (&VRAMScheduler{}).parseGPUDeviceFromDRA(resourcev1.Device{Name: "gpu-0"}, "gpu.nvidia.com")
+	if gpu.Rail != -1 {
+		t.Errorf("rail without attribute = %d, want -1", gpu.Rail)
+	}
+}
+
+func TestSelectGPUsRailOrder(t *testing.T) {
+	// gpu-2 is partly used, so tightest fit would pick it first
+	occupancy := []gpuOccupancy{
+		{Device: GPUDevice{Name: "gpu-0", VRAM: 80 * GiB, Rail: 0}},
+		{Device: GPUDevice{Name: "gpu-1", VRAM: 80 * GiB, Rail: 1}},
+		{Device: GPUDevice{Name: "gpu-2", VRAM: 80 * GiB, Rail: 2}, UsedVRAM: 20 * GiB, ExclusiveTenants: 1},
+		{Device: GPUDevice{Name: "gpu-3", VRAM: 80 * GiB, Rail: 3}},
+	}
+
+	req := gpuRequest{count: 2, perGPU: 40 * GiB}
+	if got := selectGPUs(occupancy, req); len(got) != 2 || got[0] != 2 {
+		t.Errorf("tightest fit selected %v, want gpu-2 first", got)
+	}
+
+	req.railAligned = true
+	if got := selectGPUs(occupancy, req); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("rail-aligned selection = %v, want rails 0 and 1", got)
+	}
+}
+
+func TestDRADevicesFromSnapshotIndex(t *testing.T) {
+	node := testutil.MakeNode("dra-node", nil, v1.ResourceList{ResourceGPU: resource.MustParse("1")})
+	fh, err := testutil.NewTestFrameworkWithPods(nil, []*v1.Node{node}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	plugin, err := New(context.Background(), nil, fh)
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	v := plugin.(*VRAMScheduler)
+
+	migType, profile := "mig", "3g.40gb"
+	slice := &resourcev1.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "dra-node-gpu"},
+		Spec: resourcev1.ResourceSliceSpec{
+			Driver:   "gpu.nvidia.com",
+			NodeName: &node.Name,
+			Devices: []resourcev1.Device{
+				{Name: "gpu-0", Capacity: map[resourcev1.QualifiedName]resourcev1.DeviceCapacity{
+					"memory": {Value: resource.MustParse("80Gi")},
+				}},
+				{Name: "gpu-1-mig-0", Attributes: map[resourcev1.QualifiedName]resourcev1.DeviceAttribute{
+					"type":    {StringValue: &migType},
+					"profile": {StringValue: &profile},
+				}},
+			},
+		},
+	}
+	// The index reads the informer's store, so the slice needs no lister of its own
+	if err := fh.SharedInformerFactory().Resource().V1().ResourceSlices().Informer().GetStore().Add(slice); err != nil {
+		t.Fatalf("Failed to add ResourceSlice: %v", err)
+	}
+	v.resourceSliceLister = nil
+
+	state := framework.NewCycleState()
+	if _, devices := v.getNodeGPUTopology(context.Background(), state, node); len(devices) != 1 || devices[0].VRAM != 80*GiB {
+		t.Errorf("getNodeGPUTopology() = %+v, want gpu-0 with 80Gi from the snapshot index", devices)
+	}
+	if migs := v.getNodeMIGDevices(state, node); len(migs) != 1 || migs[0].Profile != profile {
+		t.Errorf("getNodeMIGDevices() = %+v, want one %s partition from the snapshot index", migs, profile)
+	}
+}
@@ -33,6 +33,7 @@ import (
 // WorkloadAware implements workload-aware scoring:
 // - Batch workloads: Bin packing (prefer fuller nodes for co-location)
 // - Service workloads: Spreading (prefer emptier nodes for HA)
+// - Sandboxes: Bin packing (short-lived, keep whole nodes free)
 type WorkloadAware struct {
 	handle framework.Handle
 }
@@ -74,13 +75,29 @@ func (w *WorkloadAware) Score(ctx context.Context, state framework.CycleState, p
 
 	var score int64
 	switch workloadType {
-	case workload.TypeBatch, "batch", "training":
+	case workload.TypeBatch, "batch", "training", "fine-tuning", "distributed-inference":
 		// Batch/Training: Bin packing - prefer fuller nodes
 		// Higher utilization = higher score
 		// Goal: Co-locate batch pods on same nodes to:
 		// 1. Reduce network latency (ML training, Spark shuffles)
 		// 2. Leave empty nodes for services
 		// 3. Maximize resource utilization
+		// Distributed inference packs too: a LeaderWorkerSet group serves as one
+		// unit, so its HA comes from spreading groups, not pods within a group
+		score = int64(utilization)
+
+	case "data-prep":
+		// Data preprocessing: pack onto CPU-only nodes, away from accelerators.
+		// GPU nodes score 0, whatever their utilization, so CPU-heavy pods don't
+		// strand GPUs
+		score = int64(utilization)
+		if nodeHasGPU(nodeInfo) {
+			score = 0
+		}
+
+	case "sandbox":
+		// Sandboxes: short-lived and small, so pack them densely. This keeps
+		// emptier nodes free for long-running services and gang jobs
 		score = int64(utilization)
 
 	case workload.TypeService, "service", "inference":
@@ -126,6 +143,16 @@ func (w *WorkloadAware) ScoreExtensions() framework.ScoreExtensions {
 	return nil
 }
 
+// nodeHasGPU returns true if the node advertises allocatable GPUs
+func nodeHasGPU(nodeInfo framework.NodeInfo) bool {
+	node := nodeInfo.Node()
+	if node == nil {
+		return false
+	}
+	gpus := node.Status.Allocatable[v1.ResourceName(GPUResourceName)]
+	return gpus.Value() > 0
+}
+
 // calculateNodeUtilization returns the node's resource utilization as a percentage (0-100).
 // Considers CPU, memory, and GPU resources with appropriate weights for GPU clusters.
 // Uses NodeInfo.GetRequested() which already aggregates pod resource requests.
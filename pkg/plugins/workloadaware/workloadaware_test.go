@@ -17,9 +17,16 @@ limitations under the License.
 package workloadaware
 
 import (
+	"context"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	framework "k8s.io/kube-scheduler/framework"
+	schedframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/profileclassifier"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
 )
 
 func TestName(t *testing.T) {
@@ -62,3 +69,56 @@ func TestConstants(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreByWorkloadType(t *testing.T) {
+	// Half the CPU and memory of each node is requested, and one of the GPU node's
+	// four GPUs: the CPU node is 35% utilized and the GPU node 42.5%
+	half := v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("4Gi")}
+	oneGPU := v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("4Gi"), GPUResourceName: resource.MustParse("1")}
+	cpuNode := schedframework.NewNodeInfo(testutil.MakePod("used", "default", "cpu", half, nil, nil))
+	cpuNode.SetNode(testutil.MakeNode("cpu", nil, v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi"),
+	}))
+	gpuNode := schedframework.NewNodeInfo(testutil.MakePod("used", "default", "gpu", oneGPU, nil, nil))
+	gpuNode.SetNode(testutil.MakeNode("gpu", nil, v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi"), GPUResourceName: resource.MustParse("4"),
+	}))
+
+	tests := []struct {
+		workloadType profileclassifier.WorkloadType
+		wantCPU      int64
+		wantGPU      int64
+	}{
+		// Packed: fuller nodes score higher
+		{profileclassifier.WorkloadTraining, 35, 42},
+		{profileclassifier.WorkloadFineTuning, 35, 42},
+		{profileclassifier.WorkloadDistributedInference, 35, 42},
+		{profileclassifier.WorkloadSandbox, 35, 42},
+		// Packed onto CPU-only nodes; GPU nodes score 0
+		{profileclassifier.WorkloadDataPrep, 35, 0},
+		// Spread: emptier nodes score higher
+		{profileclassifier.WorkloadInference, 65, 57},
+		{profileclassifier.WorkloadService, 65, 57},
+		{profileclassifier.WorkloadUnknown, 65, 57},
+	}
+
+	plugin := &WorkloadAware{}
+	pod := testutil.MakePod("p", "default", "", nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(string(tt.workloadType), func(t *testing.T) {
+			// ProfileClassifier stores the profile under its plugin name
+			state := schedframework.NewCycleState()
+			state.Write(profileclassifier.Name, &profileclassifier.SchedulingProfile{WorkloadType: tt.workloadType})
+
+			for nodeInfo, want := range map[*schedframework.NodeInfo]int64{cpuNode: tt.wantCPU, gpuNode: tt.wantGPU} {
+				score, status := plugin.Score(context.Background(), state, pod, nodeInfo)
+				if !status.IsSuccess() {
+					t.Fatalf("Score(%s) status = %v", nodeInfo.Node().Name, status)
+				}
+				if score != want {
+					t.Errorf("Score(%s) = %d, want %d", nodeInfo.Node().Name, score, want)
+				}
+			}
+		})
+	}
+}
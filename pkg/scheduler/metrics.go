@@ -205,6 +205,16 @@ var (
 		[]string{"namespace", "pod_group", "gang_size"},
 	)
 
+	// RailAlignmentScore tracks how well tensor/pipeline-parallel gang members line up by rail
+	RailAlignmentScore = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubenexus_rail_alignment_score",
+			Help:    "Share of GPU rails whose rail switch matches the gang's placed members (0-100)",
+			Buckets: []float64{0, 25, 50, 75, 90, 100},
+		},
+		[]string{"parallelism"},
+	)
+
 	// NUMA Topology Metrics
 
 	// NumaPlacementDecisions tracks NUMA placement outcomes
@@ -301,4 +311,70 @@ var (
 		},
 		[]string{"topology_type"},
 	)
+
+	// Cluster-wide GPU Fragmentation Metrics
+
+	// GangPlacementsAvailable tracks how many gangs of each GPU count could be placed now
+	GangPlacementsAvailable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_gpu_gang_placements_available",
+			Help: "Gangs of each size (GPUs) that free capacity could hold right now",
+		},
+		[]string{"gang_size"},
+	)
+
+	// StrandedGPUs tracks free GPUs on partially used islands
+	StrandedGPUs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_gpu_stranded",
+			Help: "Free GPUs on partially used GPU islands, by island topology",
+		},
+		[]string{"topology_type"},
+	)
+
+	// PristineIslands tracks fully free GPU islands
+	PristineIslands = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_gpu_pristine_islands",
+			Help: "Fully free GPU islands, by GPU model",
+		},
+		[]string{"gpu_model"},
+	)
+
+	// GPUFragmentationIndex tracks the share of free GPUs that are stranded
+	GPUFragmentationIndex = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_gpu_fragmentation_index",
+			Help: "Share of free GPUs stranded on partially used islands (0 = none, 1 = all)",
+		},
+	)
+
+	// Tenant GPU Budget Metrics
+
+	// TenantGPUSpend tracks the hourly GPU spend of each tenant's bound pods
+	TenantGPUSpend = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_tenant_gpu_spend_per_hour",
+			Help: "Running GPU spend per hour of bound pods, by tenant",
+		},
+		[]string{"tenant"},
+	)
+
+	// TenantGPUBudget tracks each tenant's configured GPU budget per hour
+	TenantGPUBudget = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubenexus_tenant_gpu_budget_per_hour",
+			Help: "Configured GPU budget per hour, by tenant",
+		},
+		[]string{"tenant"},
+	)
+
+	// TenantBudgetDecisions tracks pods steered or held because their tenant is over budget
+	TenantBudgetDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubenexus_tenant_budget_decisions_total",
+			Help: "Scheduling attempts of over-budget tenants, by tenant and action (steer, hold)",
+		},
+		[]string{"tenant", "action"},
+	)
 )
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot indexes the scheduler snapshot once per scheduling cycle, so plugins
+// look up cluster-wide facts instead of scanning every pod or ResourceSlice for each
+// node they filter or score.
+//
+// The Index holds:
+//   - resources requested per node (CPU, memory, GPUs)
+//   - DRA ResourceSlices per node
+//   - placed gang members per pod group, including pods reserved and waiting at Permit
+//   - pods per zone and the zones of each LeaderWorkerSet group
+//
+// The first plugin to ask for the index in a cycle builds it from the snapshot's
+// NodeInfos and stores it in the CycleState; every other plugin reads the same copy.
+// Builds are serialized, so Filter and Score running in parallel build it only once.
+package snapshot
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	resourcev1listers "k8s.io/client-go/listers/resource/v1"
+	klog "k8s.io/klog/v2"
+	framework "k8s.io/kube-scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+)
+
+const (
+	// LeaderWorkerSet group labels
+	LabelLWSName       = "leaderworkerset.sigs.k8s.io/name"
+	LabelLWSGroupIndex = "leaderworkerset.sigs.k8s.io/group-index"
+
+	stateKey = "kubenexus.io/snapshot-index"
+)
+
+// buildLock serializes index builds so parallel Filter and Score calls of one cycle
+// build the index once
+var buildLock sync.Mutex
+
+// Index is the per-cycle view of the snapshot
+type Index struct {
+	slices   map[string][]*resourcev1.ResourceSlice // node -> slices
+	gangs    map[string][]*v1.Pod                   // namespace/pod-group -> placed members
+	zonePods map[string]int                         // zone -> pods
+	lwsZones map[string]map[string]bool             // namespace/name/group-index -> zones
+}
+
+// Clone implements framework.StateData. The index is read-only once built.
+func (idx *Index) Clone() framework.StateData {
+	return idx
+}
+
+// Build indexes the nodes and their pods, and the ResourceSlices by node
+func Build(nodes []framework.NodeInfo, slices []*resourcev1.ResourceSlice) *Index {
+	idx := &Index{
+		slices:   make(map[string][]*resourcev1.ResourceSlice),
+		gangs:    make(map[string][]*v1.Pod),
+		zonePods: make(map[string]int),
+		lwsZones: make(map[string]map[string]bool),
+	}
+
+	for _, nodeInfo := range nodes {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		zone := node.Labels[v1.LabelTopologyZone]
+
+		for _, podInfo := range nodeInfo.GetPods() {
+			pod := podInfo.GetPod()
+			if group := podGroupName(pod); group != "" {
+				key := pod.Namespace + "/" + group
+				idx.gangs[key] = append(idx.gangs[key], pod)
+			}
+			if zone == "" {
+				continue
+			}
+			idx.zonePods[zone]++
+			if name, ok := pod.Labels[LabelLWSName]; ok {
+				key := pod.Namespace + "/" + name + "/" + pod.Labels[LabelLWSGroupIndex]
+				if idx.lwsZones[key] == nil {
+					idx.lwsZones[key] = make(map[string]bool)
+				}
+				idx.lwsZones[key][zone] = true
+			}
+		}
+	}
+
+	for _, slice := range slices {
+		if slice.Spec.NodeName != nil {
+			idx.slices[*slice.Spec.NodeName] = append(idx.slices[*slice.Spec.NodeName], slice)
+		}
+	}
+	return idx
+}
+
+// podGroupName returns the pod's gang: the pod-group annotation, else the pod group labels
+func podGroupName(pod *v1.Pod) string {
+	if group := pod.Annotations[utils.AnnotationPodGroup]; group != "" {
+		return group
+	}
+	if group := pod.Labels[utils.PodGroupNameLabel]; group != "" {
+		return group
+	}
+	return pod.Labels[utils.LegacyPodGroupNameLabel]
+}
+
+// Slices returns the DRA ResourceSlices published for the node
+func (idx *Index) Slices(nodeName string) []*resourcev1.ResourceSlice {
+	return idx.slices[nodeName]
+}
+
+// GangMembers returns the placed pods of the pod group
+func (idx *Index) GangMembers(namespace, podGroup string) []*v1.Pod {
+	return idx.gangs[namespace+"/"+podGroup]
+}
+
+// ZonePods returns the number of pods in each zone
+func (idx *Index) ZonePods() map[string]int {
+	return idx.zonePods
+}
+
+// LWSGroupZones returns the zones holding pods of the LeaderWorkerSet group
+func (idx *Index) LWSGroupZones(namespace, name, groupIndex string) map[string]bool {
+	return idx.lwsZones[namespace+"/"+name+"/"+groupIndex]
+}
+
+// Builder builds the Index from the handle's snapshot
+type Builder struct {
+	handle      framework.Handle
+	sliceLister resourcev1listers.ResourceSliceLister
+}
+
+// NewBuilder returns a Builder for the handle. It registers the ResourceSlice informer,
+// so it must be called from a plugin's New.
+func NewBuilder(handle framework.Handle) *Builder {
+	b := &Builder{handle: handle}
+	if handle != nil && handle.SharedInformerFactory() != nil {
+		b.sliceLister = handle.SharedInformerFactory().Resource().V1().ResourceSlices().Lister()
+	}
+	return b
+}
+
+// Get returns the cycle's Index, building it on first use. It returns nil without a
+// handle or CycleState, and callers fall back to their own lookups.
+func (b *Builder) Get(state framework.CycleState) *Index {
+	if b == nil || b.handle == nil || state == nil {
+		return nil
+	}
+	if idx := read(state); idx != nil {
+		return idx
+	}
+
+	buildLock.Lock()
+	defer buildLock.Unlock()
+	if idx := read(state); idx != nil {
+		return idx
+	}
+
+	lister := b.handle.SnapshotSharedLister()
+	if lister == nil {
+		return nil
+	}
+	nodes, err := lister.NodeInfos().List()
+	if err != nil {
+		klog.V(4).InfoS("Snapshot index: failed to list nodes", "err", err)
+		return nil
+	}
+	var slices []*resourcev1.ResourceSlice
+	if b.sliceLister != nil {
+		if slices, err = b.sliceLister.List(labels.Everything()); err != nil {
+			klog.V(4).InfoS("Snapshot index: failed to list ResourceSlices", "err", err)
+		}
+	}
+
+	idx := Build(nodes, slices)
+	state.Write(stateKey, idx)
+	klog.V(5).InfoS("Snapshot index built", "nodes", len(nodes), "resourceSlices", len(slices))
+	return idx
+}
+
+func read(state framework.CycleState) *Index {
+	data, err := state.Read(stateKey)
+	if err != nil {
+		return nil
+	}
+	idx, _ := data.(*Index)
+	return idx
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/utils"
+	testutil "github.com/kube-nexus/kubenexus-scheduler/test/util"
+)
+
+func testCluster() ([]*v1.Pod, []*v1.Node) {
+	nodes := []*v1.Node{
+		testutil.MakeNode("gpu-a", map[string]string{v1.LabelTopologyZone: "zone-a"}, nil),
+		testutil.MakeNode("gpu-b", map[string]string{v1.LabelTopologyZone: "zone-b"}, nil),
+		testutil.MakeNode("no-zone", nil, nil),
+	}
+	lws := map[string]string{LabelLWSName: "llama", LabelLWSGroupIndex: "0"}
+	pods := []*v1.Pod{
+		testutil.MakePod("train-0", "default", "gpu-a", nil, nil, map[string]string{utils.AnnotationPodGroup: "train"}),
+		testutil.MakePod("train-1", "default", "gpu-a", nil, nil, map[string]string{utils.AnnotationPodGroup: "train"}),
+		testutil.MakePod("labeled-0", "default", "gpu-b", nil, map[string]string{utils.PodGroupNameLabel: "labeled"}, nil),
+		testutil.MakePod("llama-0", "default", "gpu-b", nil, lws, nil),
+		testutil.MakePod("other-ns", "team-b", "no-zone", nil, nil, map[string]string{utils.AnnotationPodGroup: "train"}),
+	}
+	return pods, nodes
+}
+
+func TestBuild(t *testing.T) {
+	pods, nodes := testCluster()
+	nodeInfos, err := testutil.NewFakeSharedLister(pods, nodes).NodeInfos().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeName := "gpu-a"
+	slices := []*resourcev1.ResourceSlice{
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu-a-gpus"}, Spec: resourcev1.ResourceSliceSpec{NodeName: &nodeName}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "network"}},
+	}
+	idx := Build(nodeInfos, slices)
+
+	if got := idx.Slices("gpu-a"); len(got) != 1 || got[0].Name != "gpu-a-gpus" {
+		t.Errorf("Slices(gpu-a) = %v, want gpu-a-gpus", got)
+	}
+	if got := idx.GangMembers("default", "train"); len(got) != 2 {
+		t.Errorf("GangMembers(default/train) = %d pods, want 2", len(got))
+	}
+	if got := idx.GangMembers("default", "labeled"); len(got) != 1 {
+		t.Errorf("GangMembers(default/labeled) = %d pods, want 1 from the pod group label", len(got))
+	}
+	if got := idx.ZonePods(); got["zone-a"] != 2 || got["zone-b"] != 2 || len(got) != 2 {
+		t.Errorf("ZonePods() = %v, want 2 in zone-a and zone-b", got)
+	}
+	if got := idx.LWSGroupZones("default", "llama", "0"); len(got) != 1 || !got["zone-b"] {
+		t.Errorf("LWSGroupZones() = %v, want zone-b", got)
+	}
+}
+
+func TestBuilderGet(t *testing.T) {
+	pods, nodes := testCluster()
+	handle, err := testutil.NewTestFrameworkWithPods(pods, nodes, nil)
+	if err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	builder := NewBuilder(handle)
+
+	state := framework.NewCycleState()
+	idx := builder.Get(state)
+	if idx == nil {
+		t.Fatal("Get() = nil, want an index")
+	}
+	if again := builder.Get(state); again != idx {
+		t.Error("Get() rebuilt the index within one cycle")
+	}
+	if next := builder.Get(framework.NewCycleState()); next == idx {
+		t.Error("Get() reused the index of another cycle")
+	}
+
+	var noState fwk.CycleState
+	if got := builder.Get(noState); got != nil {
+		t.Errorf("Get(nil) = %v, want nil", got)
+	}
+	var noBuilder *Builder
+	if got := noBuilder.Get(state); got != nil {
+		t.Errorf("nil Builder Get() = %v, want nil", got)
+	}
+}
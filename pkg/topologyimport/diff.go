@@ -0,0 +1,174 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+)
+
+// LabelChange is one node label to set
+type LabelChange struct {
+	Node string
+	Key  string
+	Old  string // Empty when the label is not set
+	New  string
+}
+
+// String formats the change as a diff line
+func (c LabelChange) String() string {
+	if c.Old == "" {
+		return fmt.Sprintf("%s: + %s=%s", c.Node, c.Key, c.New)
+	}
+	return fmt.Sprintf("%s: ~ %s=%s -> %s", c.Node, c.Key, c.Old, c.New)
+}
+
+// DiffNodeLabels compares the desired labels with the cluster's nodes. Topology hosts
+// match a node by name or by the node name's first DNS label, so "dgx001" matches
+// "dgx001.cluster.local". It returns the changes, sorted by node and key, and the
+// topology hosts with no node.
+func DiffNodeLabels(nodes []v1.Node, desired map[string]map[string]string) ([]LabelChange, []string) {
+	byName := make(map[string]*v1.Node, len(nodes))
+	byShortName := make(map[string]*v1.Node, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		byName[node.Name] = node
+		short, _, _ := strings.Cut(node.Name, ".")
+		byShortName[short] = node
+	}
+
+	var changes []LabelChange
+	var missing []string
+	for _, host := range sortedHosts(desired) {
+		node := byName[host]
+		if node == nil {
+			node = byShortName[host]
+		}
+		if node == nil {
+			missing = append(missing, host)
+			continue
+		}
+		keys := make([]string, 0, len(desired[host]))
+		for key := range desired[host] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if current := node.Labels[key]; current != desired[host][key] {
+				changes = append(changes, LabelChange{Node: node.Name, Key: key, Old: current, New: desired[host][key]})
+			}
+		}
+	}
+	return changes, missing
+}
+
+// LabelPatches groups label changes into a JSON merge patch per node. It fails before
+// building any patch when a value is not a valid label value.
+func LabelPatches(changes []LabelChange) (map[string][]byte, error) {
+	byNode := make(map[string]map[string]string)
+	for _, change := range changes {
+		if errs := validation.IsValidLabelValue(change.New); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value %q for label %s on node %s: %s", change.New, change.Key, change.Node, strings.Join(errs, "; "))
+		}
+		if byNode[change.Node] == nil {
+			byNode[change.Node] = make(map[string]string)
+		}
+		byNode[change.Node][change.Key] = change.New
+	}
+	patches := make(map[string][]byte, len(byNode))
+	for node, nodeLabels := range byNode {
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": nodeLabels},
+		})
+		if err != nil {
+			return nil, err
+		}
+		patches[node] = patch
+	}
+	return patches, nil
+}
+
+// DiffTopology compares two NetworkTopology objects domain by domain. A nil current
+// topology means it does not exist yet.
+func DiffTopology(current, desired *v1alpha1.NetworkTopology) []string {
+	currentDomains := make(map[string]v1alpha1.TopologyDomain)
+	if current != nil {
+		for _, domain := range current.Spec.Domains {
+			currentDomains[domain.Name] = domain
+		}
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	for _, domain := range desired.Spec.Domains {
+		seen[domain.Name] = true
+		old, exists := currentDomains[domain.Name]
+		if !exists {
+			lines = append(lines, fmt.Sprintf("+ domain %s (level %s, parent %q, %d nodes)", domain.Name, domain.Level, domain.Parent, len(domain.Nodes)))
+			continue
+		}
+		if old.Level != domain.Level {
+			lines = append(lines, fmt.Sprintf("~ domain %s: level %s -> %s", domain.Name, old.Level, domain.Level))
+		}
+		if old.Parent != domain.Parent {
+			lines = append(lines, fmt.Sprintf("~ domain %s: parent %q -> %q", domain.Name, old.Parent, domain.Parent))
+		}
+		if old.LinkBandwidthGbps != domain.LinkBandwidthGbps {
+			lines = append(lines, fmt.Sprintf("~ domain %s: linkBandwidthGbps %d -> %d", domain.Name, old.LinkBandwidthGbps, domain.LinkBandwidthGbps))
+		}
+		if old.Oversubscription != domain.Oversubscription {
+			lines = append(lines, fmt.Sprintf("~ domain %s: oversubscription %q -> %q", domain.Name, old.Oversubscription, domain.Oversubscription))
+		}
+		if !reflect.DeepEqual(sortedCopy(old.Nodes), sortedCopy(domain.Nodes)) {
+			lines = append(lines, fmt.Sprintf("~ domain %s: nodes %d -> %d", domain.Name, len(old.Nodes), len(domain.Nodes)))
+		}
+	}
+	if current != nil {
+		for _, domain := range current.Spec.Domains {
+			if !seen[domain.Name] {
+				lines = append(lines, fmt.Sprintf("- domain %s", domain.Name))
+			}
+		}
+	}
+	return lines
+}
+
+func sortedHosts(desired map[string]map[string]string) []string {
+	hosts := make([]string, 0, len(desired))
+	for host := range desired {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
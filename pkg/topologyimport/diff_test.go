@@ -0,0 +1,113 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+)
+
+func TestDiffNodeLabels(t *testing.T) {
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "dgx001.cluster.local", Labels: map[string]string{
+			networkfabric.LabelRackID: "leaf1",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "dgx002", Labels: map[string]string{
+			networkfabric.LabelRackID: "old-rack",
+		}}},
+	}
+	desired := map[string]map[string]string{
+		"dgx001": {networkfabric.LabelRackID: "leaf1", networkfabric.LabelFabricID: "core"},
+		"dgx002": {networkfabric.LabelRackID: "leaf1"},
+		"dgx003": {networkfabric.LabelRackID: "leaf2"},
+	}
+
+	changes, missing := DiffNodeLabels(nodes, desired)
+	if len(changes) != 2 {
+		t.Fatalf("changes = %v, want 2", changes)
+	}
+	if got := changes[0].String(); got != "dgx001.cluster.local: + network.kubenexus.io/fabric-id=core" {
+		t.Errorf("first change = %q", got)
+	}
+	if got := changes[1].String(); got != "dgx002: ~ network.kubenexus.io/rack-id=old-rack -> leaf1" {
+		t.Errorf("second change = %q", got)
+	}
+	if len(missing) != 1 || missing[0] != "dgx003" {
+		t.Errorf("missing = %v, want [dgx003]", missing)
+	}
+
+	patches, err := LabelPatches(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patch struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(patches["dgx002"], &patch); err != nil {
+		t.Fatal(err)
+	}
+	if patch.Metadata.Labels[networkfabric.LabelRackID] != "leaf1" {
+		t.Errorf("dgx002 patch = %s", patches["dgx002"])
+	}
+}
+
+func TestDiffTopology(t *testing.T) {
+	current := &v1alpha1.NetworkTopology{Spec: v1alpha1.NetworkTopologySpec{Domains: []v1alpha1.TopologyDomain{
+		{Name: "leaf1", Level: "leaf", Parent: "spine", Nodes: []string{"b", "a"}},
+		{Name: "leaf9", Level: "leaf"},
+	}}}
+	desired := &v1alpha1.NetworkTopology{Spec: v1alpha1.NetworkTopologySpec{Domains: []v1alpha1.TopologyDomain{
+		{Name: "leaf1", Level: "leaf", Parent: "spine", Nodes: []string{"a", "b"}, Oversubscription: "2:1"},
+		{Name: "spine", Level: "spine"},
+	}}}
+
+	want := []string{
+		`~ domain leaf1: oversubscription "" -> "2:1"`,
+		`+ domain spine (level spine, parent "", 0 nodes)`,
+		`- domain leaf9`,
+	}
+	got := DiffTopology(current, desired)
+	if len(got) != len(want) {
+		t.Fatalf("diff = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if lines := DiffTopology(nil, desired); len(lines) != 2 {
+		t.Errorf("diff against a missing object = %q, want every domain added", lines)
+	}
+}
+
+func TestLabelPatchesRejectsInvalidValues(t *testing.T) {
+	changes := []LabelChange{
+		{Node: "dgx001", Key: networkfabric.LabelRackID, New: "leaf1"},
+		{Node: "dgx002", Key: networkfabric.LabelRackID, New: "rack a/row 3"},
+	}
+	if patches, err := LabelPatches(changes); err == nil {
+		t.Errorf("LabelPatches() = %v, want an error for the invalid rack-id", patches)
+	}
+}
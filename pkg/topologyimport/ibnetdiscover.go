@@ -0,0 +1,287 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	// Switch	40 "S-b8599f0300fc6de4"		# "MF0;leaf01:MQM8700/U1" enhanced port 0 lid 12 lmc 0
+	ibSwitchLine = regexp.MustCompile(`^Switch\s+\d+\s+"S-([0-9a-fA-F]+)"\s*(?:#\s*"([^"]*)")?`)
+	// [1]	"H-0c42a10300a1b2c2"[1](c42a10300a1b2c3) 		# "dgx001 mlx5_0" lid 5 4xHDR
+	ibPortLine = regexp.MustCompile(`^\[\d+\](?:\([0-9a-fA-F]+\))?\s+"([HS])-([0-9a-fA-F]+)"\[\d+\]\S*\s*(?:#\s*"([^"]*)")?(.*)$`)
+	// 4xHDR, 1xNDR, 4xFDR10
+	ibLinkRate = regexp.MustCompile(`(\d+)x(SDR|DDR|QDR|FDR10|FDR|EDR|HDR|NDR|XDR)\b`)
+	// Runs of characters a label value cannot hold, with the dashes around them
+	labelUnsafe = regexp.MustCompile(`-*[^A-Za-z0-9._-]+-*`)
+)
+
+// ibLaneGbps is the data rate of one lane per InfiniBand generation
+var ibLaneGbps = map[string]float64{
+	"SDR":   2.5,
+	"DDR":   5,
+	"QDR":   10,
+	"FDR10": 10,
+	"FDR":   14,
+	"EDR":   25,
+	"HDR":   50,
+	"NDR":   100,
+	"XDR":   200,
+}
+
+// ibSwitch is a switch read from ibnetdiscover with its links
+type ibSwitch struct {
+	guid        string
+	description string
+	hosts       []ibHostLink
+	switchLinks map[string][]int64 // neighbor switch GUID -> link rates in Gb/s
+}
+
+type ibHostLink struct {
+	host   string
+	device string
+	gbps   int64
+}
+
+// ParseIBNetDiscover parses the output of ibnetdiscover. Switches connected to host
+// channel adapters are leaves; every other switch sits one level above its nearest
+// lower neighbors. A switch linked to several upper switches, as in a fat-tree, is
+// placed under the one it has the most links to. Hosts are named by the first word of
+// their adapter description, and a host with several adapters is placed on the leaf of
+// its first adapter (mlx5_0 before mlx5_1).
+func ParseIBNetDiscover(r io.Reader) (*Topology, error) {
+	switches := make(map[string]*ibSwitch)
+	var current *ibSwitch
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			current = nil
+		case strings.HasPrefix(line, "Switch"):
+			m := ibSwitchLine.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("unrecognized switch line %q", line)
+			}
+			current = &ibSwitch{guid: m[1], description: m[2], switchLinks: make(map[string][]int64)}
+			switches[current.guid] = current
+		case strings.HasPrefix(line, "Ca"), strings.HasPrefix(line, "Rt"):
+			current = nil
+		case strings.HasPrefix(line, "[") && current != nil:
+			m := ibPortLine.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			gbps := ibLinkGbps(m[4])
+			if m[1] == "S" {
+				current.switchLinks[m[2]] = append(current.switchLinks[m[2]], gbps)
+				continue
+			}
+			fields := strings.Fields(m[3])
+			if len(fields) == 0 {
+				continue
+			}
+			link := ibHostLink{host: fields[0], gbps: gbps}
+			if len(fields) > 1 {
+				link.device = fields[1]
+			}
+			current.hosts = append(current.hosts, link)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(switches) == 0 {
+		return nil, fmt.Errorf("no switches found")
+	}
+	return buildIBTopology(switches)
+}
+
+// ibLinkGbps returns the link rate from a port comment such as "lid 5 4xHDR"
+func ibLinkGbps(comment string) int64 {
+	m := ibLinkRate.FindStringSubmatch(comment)
+	if m == nil {
+		return 0
+	}
+	lanes, _ := strconv.Atoi(m[1])
+	return int64(float64(lanes) * ibLaneGbps[m[2]])
+}
+
+func buildIBTopology(switches map[string]*ibSwitch) (*Topology, error) {
+	names := ibSwitchNames(switches)
+	guids := make([]string, 0, len(switches))
+	for guid := range switches {
+		guids = append(guids, guid)
+	}
+	sort.Strings(guids)
+
+	// Height of each switch: distance to the nearest switch holding hosts
+	height := make(map[string]int)
+	var queue []string
+	for _, guid := range guids {
+		if len(switches[guid].hosts) > 0 {
+			height[guid] = 0
+			queue = append(queue, guid)
+		}
+	}
+	for len(queue) > 0 {
+		guid := queue[0]
+		queue = queue[1:]
+		for neighbor := range switches[guid].switchLinks {
+			if _, seen := height[neighbor]; seen || switches[neighbor] == nil {
+				continue
+			}
+			height[neighbor] = height[guid] + 1
+			queue = append(queue, neighbor)
+		}
+	}
+
+	topology := NewTopology()
+	hostLeaf := make(map[string]ibHostLink)
+	hostSwitch := make(map[string]string)
+	for _, guid := range guids {
+		ib := switches[guid]
+		h, reachable := height[guid]
+		if !reachable {
+			continue
+		}
+		sw := topology.switchNamed(names[guid])
+
+		var down, up int64
+		for _, link := range ib.hosts {
+			down += link.gbps
+			sw.LinkBandwidthGbps = max(sw.LinkBandwidthGbps, link.gbps)
+			if existing, ok := hostLeaf[link.host]; !ok || link.device < existing.device {
+				hostLeaf[link.host] = link
+				hostSwitch[link.host] = sw.Name
+			}
+		}
+
+		parent, parentLinks := "", 0
+		for _, neighbor := range sortedNeighbors(ib.switchLinks) {
+			rates := ib.switchLinks[neighbor]
+			nh, ok := height[neighbor]
+			switch {
+			case !ok:
+				continue
+			case nh < h:
+				for _, gbps := range rates {
+					down += gbps
+					sw.LinkBandwidthGbps = max(sw.LinkBandwidthGbps, gbps)
+				}
+			case nh > h:
+				for _, gbps := range rates {
+					up += gbps
+				}
+				if len(rates) > parentLinks {
+					parent, parentLinks = neighbor, len(rates)
+				}
+			}
+		}
+		if parent != "" {
+			if err := topology.setParent(sw.Name, names[parent]); err != nil {
+				return nil, err
+			}
+		}
+		sw.Oversubscription = formatOversubscription(down, up)
+	}
+
+	for host, name := range hostSwitch {
+		sw := topology.Switches[name]
+		sw.Nodes = append(sw.Nodes, host)
+	}
+	return topology, topology.Validate()
+}
+
+// ibSwitchNames names switches by their description, "MF0;leaf01:MQM8700/U1" -> leaf01,
+// falling back to the GUID when the description is missing or not unique. Names become
+// rack-id and fabric-id label values, so a description that is not a valid label value
+// is sanitized and falls back to the GUID when that is not enough.
+func ibSwitchNames(switches map[string]*ibSwitch) map[string]string {
+	names := make(map[string]string, len(switches))
+	used := make(map[string]int)
+	for guid, sw := range switches {
+		name := sw.description
+		if i := strings.Index(name, ";"); i >= 0 {
+			name = name[i+1:]
+		}
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[:i]
+		}
+		name = sanitizeLabelValue(strings.Join(strings.Fields(name), "-"))
+		if name == "" {
+			name = "S-" + guid
+		}
+		names[guid] = name
+		used[name]++
+	}
+	for guid, name := range names {
+		if used[name] > 1 {
+			names[guid] = name + "-" + guid
+			if len(validation.IsValidLabelValue(names[guid])) > 0 {
+				names[guid] = "S-" + guid
+			}
+		}
+	}
+	return names
+}
+
+// sanitizeLabelValue replaces characters a label value cannot hold with dashes and trims
+// the result to a valid label value, empty when nothing usable is left
+func sanitizeLabelValue(value string) string {
+	value = labelUnsafe.ReplaceAllString(value, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	value = strings.TrimFunc(value, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	})
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		return ""
+	}
+	return value
+}
+
+func sortedNeighbors(links map[string][]int64) []string {
+	neighbors := make([]string, 0, len(links))
+	for neighbor := range links {
+		neighbors = append(neighbors, neighbor)
+	}
+	sort.Strings(neighbors)
+	return neighbors
+}
+
+// formatOversubscription returns the downlink to uplink ratio, empty when non-blocking
+// or without uplinks
+func formatOversubscription(down, up int64) string {
+	if up == 0 || down <= up {
+		return ""
+	}
+	ratio := strconv.FormatFloat(float64(down)/float64(up), 'f', 2, 64)
+	ratio = strings.TrimRight(strings.TrimRight(ratio, "0"), ".")
+	return ratio + ":1"
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+)
+
+func TestParseIBNetDiscover(t *testing.T) {
+	f, err := os.Open("testdata/fattree.ibnetdiscover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	topology, err := ParseIBNetDiscover(f)
+	if err != nil {
+		t.Fatalf("ParseIBNetDiscover failed: %v", err)
+	}
+
+	leaf1 := topology.Switches["leaf01"]
+	if leaf1 == nil {
+		t.Fatalf("leaf01 missing, switches = %v", topology.switchNames())
+	}
+	nodes := append([]string(nil), leaf1.Nodes...)
+	sort.Strings(nodes)
+	if strings.Join(nodes, ",") != "dgx001,dgx002,dgx003,dgx004" {
+		t.Errorf("leaf01 nodes = %v", nodes)
+	}
+	if leaf1.Parent != "spine01" || leaf1.LinkBandwidthGbps != 200 {
+		t.Errorf("leaf01 parent %q bandwidth %d, want spine01 and 200", leaf1.Parent, leaf1.LinkBandwidthGbps)
+	}
+	// Four 200G host links over one 200G uplink
+	if leaf1.Oversubscription != "4:1" {
+		t.Errorf("leaf01 oversubscription = %q, want 4:1", leaf1.Oversubscription)
+	}
+	if leaf2 := topology.Switches["leaf02"]; leaf2.Oversubscription != "" {
+		t.Errorf("leaf02 oversubscription = %q, want non-blocking", leaf2.Oversubscription)
+	}
+	if spine := topology.Switches["spine01"]; spine.Parent != "" {
+		t.Errorf("spine01 parent = %q, want root", spine.Parent)
+	}
+
+	labels := topology.NodeLabels(LabelOptions{FabricType: "infiniband"})
+	if got := labels["dgx006"][networkfabric.LabelFabricID]; got != "spine01" {
+		t.Errorf("dgx006 fabric-id = %q, want spine01", got)
+	}
+}
+
+func TestIBSwitchNames(t *testing.T) {
+	switches := map[string]*ibSwitch{
+		"01": {description: "MF0;leaf01:MQM8700/U1"},
+		"02": {description: "MF0;Rack A/Row 3 (leaf):MQM8700/U1"},
+		"03": {description: "MF0;" + strings.Repeat("x", 70) + ":MQM8700/U1"},
+		"04": {description: "MF0;//:MQM8700/U1"},
+		"05": {},
+	}
+	names := ibSwitchNames(switches)
+	want := map[string]string{
+		"01": "leaf01",
+		"02": "Rack-A-Row-3-leaf",
+		"03": strings.Repeat("x", 63),
+		"04": "S-04",
+		"05": "S-05",
+	}
+	for guid, name := range want {
+		if names[guid] != name {
+			t.Errorf("switch %s name = %q, want %q", guid, names[guid], name)
+		}
+	}
+}
+
+func TestIBLinkGbps(t *testing.T) {
+	tests := map[string]int64{
+		"lid 5 4xHDR":   200,
+		"lid 5 4xNDR":   400,
+		"lid 5 4xFDR10": 40,
+		"lid 5 1xEDR":   25,
+		"lid 5":         0,
+	}
+	for comment, want := range tests {
+		if got := ibLinkGbps(comment); got != want {
+			t.Errorf("ibLinkGbps(%q) = %d, want %d", comment, got, want)
+		}
+	}
+}
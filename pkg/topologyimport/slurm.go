@@ -0,0 +1,185 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseSlurmTopology parses a Slurm topology.conf for the tree plugin:
+//
+//	SwitchName=leaf1 Nodes=dgx[001-032]
+//	SwitchName=leaf2 Nodes=dgx[033-064]
+//	SwitchName=spine1 Switches=leaf[1-2]
+//
+// Lines may be continued with a trailing backslash. LinkSpeed is in arbitrary units in
+// Slurm and is ignored.
+func ParseSlurmTopology(r io.Reader) (*Topology, error) {
+	topology := NewTopology()
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	var pending string
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, "\\") {
+			pending += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		line = strings.TrimSpace(pending + line)
+		pending = ""
+		if line == "" {
+			continue
+		}
+		if err := parseSlurmSwitch(topology, line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return topology, topology.Validate()
+}
+
+func parseSlurmSwitch(topology *Topology, line string) error {
+	fields := make(map[string]string)
+	for _, field := range strings.Fields(line) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", field)
+		}
+		fields[strings.ToLower(key)] = value
+	}
+
+	name := fields["switchname"]
+	if name == "" {
+		return fmt.Errorf("missing SwitchName")
+	}
+	sw := topology.switchNamed(name)
+
+	if value := fields["nodes"]; value != "" {
+		nodes, err := ExpandHostlist(value)
+		if err != nil {
+			return fmt.Errorf("switch %s: %w", name, err)
+		}
+		sw.Nodes = append(sw.Nodes, nodes...)
+	}
+	if value := fields["switches"]; value != "" {
+		children, err := ExpandHostlist(value)
+		if err != nil {
+			return fmt.Errorf("switch %s: %w", name, err)
+		}
+		for _, child := range children {
+			if err := topology.setParent(child, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExpandHostlist expands a Slurm hostlist expression such as
+// "dgx[001-004,010],login1" or "rack[1-2]-n[01-02]" into host names
+func ExpandHostlist(expr string) ([]string, error) {
+	var hosts []string
+	for _, item := range splitHostlist(expr) {
+		expanded, err := expandHost(item)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, expanded...)
+	}
+	return hosts, nil
+}
+
+// splitHostlist splits on commas outside brackets
+func splitHostlist(expr string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, c := range expr {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				if item := strings.TrimSpace(expr[start:i]); item != "" {
+					items = append(items, item)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if item := strings.TrimSpace(expr[start:]); item != "" {
+		items = append(items, item)
+	}
+	return items
+}
+
+// expandHost expands the first bracket group of a host and recurses on the rest
+func expandHost(host string) ([]string, error) {
+	open := strings.Index(host, "[")
+	if open < 0 {
+		if strings.Contains(host, "]") {
+			return nil, fmt.Errorf("unbalanced brackets in %q", host)
+		}
+		return []string{host}, nil
+	}
+	closing := strings.Index(host[open:], "]")
+	if closing < 0 {
+		return nil, fmt.Errorf("unbalanced brackets in %q", host)
+	}
+	closing += open
+	prefix, ranges, suffix := host[:open], host[open+1:closing], host[closing+1:]
+
+	rest, err := expandHost(suffix)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, part := range strings.Split(ranges, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			hi = lo
+		}
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q in %q", part, host)
+		}
+		end, err := strconv.Atoi(hi)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("invalid range %q in %q", part, host)
+		}
+		for n := start; n <= end; n++ {
+			// Zero padding follows the width of the lower bound, as in Slurm
+			index := fmt.Sprintf("%0*d", len(lo), n)
+			for _, tail := range rest {
+				hosts = append(hosts, prefix+index+tail)
+			}
+		}
+	}
+	return hosts, nil
+}
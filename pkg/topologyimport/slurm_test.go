@@ -0,0 +1,112 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologyimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+)
+
+func TestExpandHostlist(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{"dgx001", []string{"dgx001"}, false},
+		{"dgx[001-003]", []string{"dgx001", "dgx002", "dgx003"}, false},
+		{"dgx[8-10],login1", []string{"dgx8", "dgx9", "dgx10", "login1"}, false},
+		{"dgx[01,05-06]", []string{"dgx01", "dgx05", "dgx06"}, false},
+		{"r[1-2]-n[1-2]", []string{"r1-n1", "r1-n2", "r2-n1", "r2-n2"}, false},
+		{"dgx[001-", nil, true},
+		{"dgx[5-3]", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ExpandHostlist(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExpandHostlist(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandHostlist(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+const slurmTopology = `
+# Two scalable units under one core switch
+SwitchName=leaf1 Nodes=dgx[001-002]
+SwitchName=leaf2 Nodes=dgx[003-004]
+SwitchName=leaf3 Nodes=dgx[005-006] LinkSpeed=200
+SwitchName=su1 Switches=leaf[1-2]
+SwitchName=su2 \
+    Switches=leaf3
+SwitchName=core Switches=su[1-2]
+`
+
+func TestParseSlurmTopology(t *testing.T) {
+	topology, err := ParseSlurmTopology(strings.NewReader(slurmTopology))
+	if err != nil {
+		t.Fatalf("ParseSlurmTopology failed: %v", err)
+	}
+
+	labels := topology.NodeLabels(LabelOptions{FabricType: "infiniband"})
+	if len(labels) != 6 {
+		t.Fatalf("labeled %d nodes, want 6", len(labels))
+	}
+	want := map[string]string{
+		networkfabric.LabelRackID:     "leaf3",
+		networkfabric.LabelFabricID:   "core",
+		networkfabric.LabelFabricType: "infiniband",
+	}
+	if !reflect.DeepEqual(labels["dgx005"], want) {
+		t.Errorf("dgx005 labels = %v, want %v", labels["dgx005"], want)
+	}
+
+	crd := topology.NetworkTopology("slurm")
+	levels := make(map[string]string)
+	parents := make(map[string]string)
+	for _, domain := range crd.Spec.Domains {
+		levels[domain.Name] = domain.Level
+		parents[domain.Name] = domain.Parent
+	}
+	if levels["leaf1"] != "leaf" || levels["su1"] != "spine" || levels["core"] != "core" {
+		t.Errorf("levels = %v", levels)
+	}
+	if parents["leaf3"] != "su2" || parents["su2"] != "core" || parents["core"] != "" {
+		t.Errorf("parents = %v", parents)
+	}
+}
+
+func TestParseSlurmTopologyErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing name":    "Nodes=dgx[001-002]",
+		"bad field":       "SwitchName=leaf1 dgx001",
+		"node twice":      "SwitchName=a Nodes=dgx1\nSwitchName=b Nodes=dgx1",
+		"two parents":     "SwitchName=s1 Switches=leaf1\nSwitchName=s2 Switches=leaf1",
+		"parent cycle":    "SwitchName=a Switches=b\nSwitchName=b Switches=a",
+		"bad host ranges": "SwitchName=leaf1 Nodes=dgx[a-b]",
+	}
+	for name, conf := range tests {
+		if _, err := ParseSlurmTopology(strings.NewReader(conf)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topologyimport converts existing network topology descriptions, Slurm
+// topology.conf files and InfiniBand ibnetdiscover dumps, into the node labels and the
+// NetworkTopology object read by the NetworkFabricScore plugin.
+//
+// Both formats describe a tree of switches: leaf switches hold nodes, upper switches hold
+// switches. Each node is labeled with its leaf switch as the rack and the root switch of
+// its tree as the fabric domain.
+package topologyimport
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/apis/scheduling/v1alpha1"
+	"github.com/kube-nexus/kubenexus-scheduler/pkg/plugins/networkfabric"
+)
+
+// Switch is one switch of the imported topology
+type Switch struct {
+	Name   string
+	Parent string   // Upper switch, empty for a root
+	Nodes  []string // Nodes attached to this switch
+
+	// LinkBandwidthGbps is the bandwidth of each link into the switch, 0 if unknown
+	LinkBandwidthGbps int64
+	// Oversubscription is the downlink to uplink ratio toward the parent, e.g. "2:1"
+	Oversubscription string
+}
+
+// Topology is a forest of switches
+type Topology struct {
+	Switches map[string]*Switch
+}
+
+// LabelOptions sets the labels that cannot be derived from the topology
+type LabelOptions struct {
+	FabricType string // network.kubenexus.io/fabric-type value, e.g. infiniband
+	AZ         string // network.kubenexus.io/az value
+}
+
+// NewTopology returns an empty topology
+func NewTopology() *Topology {
+	return &Topology{Switches: make(map[string]*Switch)}
+}
+
+// switchNamed returns the named switch, adding it when missing
+func (t *Topology) switchNamed(name string) *Switch {
+	sw, ok := t.Switches[name]
+	if !ok {
+		sw = &Switch{Name: name}
+		t.Switches[name] = sw
+	}
+	return sw
+}
+
+// setParent links a switch under its parent. A switch keeps its first parent.
+func (t *Topology) setParent(child, parent string) error {
+	sw := t.switchNamed(child)
+	t.switchNamed(parent)
+	if sw.Parent != "" && sw.Parent != parent {
+		return fmt.Errorf("switch %s is already under %s, cannot also be under %s", child, sw.Parent, parent)
+	}
+	sw.Parent = parent
+	return nil
+}
+
+// root returns the top switch of the tree holding the switch
+func (t *Topology) root(sw *Switch) *Switch {
+	seen := map[string]bool{sw.Name: true}
+	for sw.Parent != "" {
+		parent, ok := t.Switches[sw.Parent]
+		if !ok || seen[parent.Name] {
+			break
+		}
+		seen[parent.Name] = true
+		sw = parent
+	}
+	return sw
+}
+
+// Validate reports parent cycles and nodes attached to more than one switch
+func (t *Topology) Validate() error {
+	owners := make(map[string]string)
+	for _, name := range t.switchNames() {
+		sw := t.Switches[name]
+		for _, node := range sw.Nodes {
+			if owner, ok := owners[node]; ok {
+				return fmt.Errorf("node %s is attached to switches %s and %s", node, owner, name)
+			}
+			owners[node] = name
+		}
+		seen := map[string]bool{name: true}
+		for p := sw.Parent; p != ""; p = t.Switches[p].Parent {
+			if seen[p] {
+				return fmt.Errorf("switch %s is in a parent cycle", name)
+			}
+			seen[p] = true
+		}
+	}
+	return nil
+}
+
+// NodeLabels returns the NetworkFabricScore labels of every node in the topology
+func (t *Topology) NodeLabels(opts LabelOptions) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	for _, sw := range t.Switches {
+		for _, node := range sw.Nodes {
+			nodeLabels := map[string]string{
+				networkfabric.LabelRackID:   sw.Name,
+				networkfabric.LabelFabricID: t.root(sw).Name,
+			}
+			if opts.FabricType != "" {
+				nodeLabels[networkfabric.LabelFabricType] = opts.FabricType
+			}
+			if opts.AZ != "" {
+				nodeLabels[networkfabric.LabelAZ] = opts.AZ
+			}
+			result[node] = nodeLabels
+		}
+	}
+	return result
+}
+
+// NetworkTopology returns the topology as a NetworkTopology object. Switch levels are
+// named by height: leaf for switches holding nodes, then spine, core, and level-N.
+func (t *Topology) NetworkTopology(name string) *v1alpha1.NetworkTopology {
+	heights := t.heights()
+	topology := &v1alpha1.NetworkTopology{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "NetworkTopology",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	for _, switchName := range t.switchNames() {
+		sw := t.Switches[switchName]
+		nodes := append([]string(nil), sw.Nodes...)
+		sort.Strings(nodes)
+		topology.Spec.Domains = append(topology.Spec.Domains, v1alpha1.TopologyDomain{
+			Name:              sw.Name,
+			Level:             levelName(heights[sw.Name]),
+			Parent:            sw.Parent,
+			Nodes:             nodes,
+			LinkBandwidthGbps: sw.LinkBandwidthGbps,
+			Oversubscription:  sw.Oversubscription,
+		})
+	}
+	return topology
+}
+
+// heights returns each switch's distance from its deepest leaf switch
+func (t *Topology) heights() map[string]int {
+	heights := make(map[string]int, len(t.Switches))
+	for _, name := range t.switchNames() {
+		height := 0
+		seen := map[string]bool{}
+		for sw := t.Switches[name]; sw != nil && !seen[sw.Name]; sw = t.Switches[sw.Parent] {
+			seen[sw.Name] = true
+			if h, ok := heights[sw.Name]; !ok || height > h {
+				heights[sw.Name] = height
+			}
+			height++
+		}
+	}
+	return heights
+}
+
+func levelName(height int) string {
+	switch height {
+	case 0:
+		return "leaf"
+	case 1:
+		return "spine"
+	case 2:
+		return "core"
+	default:
+		return fmt.Sprintf("level-%d", height)
+	}
+}
+
+func (t *Topology) switchNames() []string {
+	names := make([]string, 0, len(t.Switches))
+	for name := range t.Switches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
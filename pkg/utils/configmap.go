@@ -0,0 +1,69 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// DefaultConfigNamespace is the namespace the scheduler's runtime ConfigMaps live in
+const DefaultConfigNamespace = "kubenexus-system"
+
+// WatchConfigMap watches a single ConfigMap and calls onChange with its latest contents
+// whenever it is created or updated, and with nil when it is deleted. The watch uses a
+// dedicated informer scoped to that one object, so it does not cache every ConfigMap in
+// the cluster. It runs until ctx is cancelled.
+func WatchConfigMap(ctx context.Context, client kubernetes.Interface, namespace, name string, onChange func(cm *v1.ConfigMap)) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector(metav1.ObjectNameField, name).String()
+		}),
+	)
+
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if cm, ok := obj.(*v1.ConfigMap); ok {
+				onChange(cm)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if cm, ok := newObj.(*v1.ConfigMap); ok {
+				onChange(cm)
+			}
+		},
+		DeleteFunc: func(_ interface{}) {
+			klog.V(2).InfoS("Watched ConfigMap deleted", "configMap", klog.KRef(namespace, name))
+			onChange(nil)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	return nil
+}
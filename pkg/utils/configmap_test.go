@@ -0,0 +1,73 @@
+/*
+Copyright 2026 KubeNexus Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestWatchConfigMap tests that changes to the watched ConfigMap reach the callback
+func TestWatchConfigMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewClientset()
+	changes := make(chan *v1.ConfigMap, 10)
+	if err := WatchConfigMap(ctx, client, DefaultConfigNamespace, "policy", func(cm *v1.ConfigMap) {
+		changes <- cm
+	}); err != nil {
+		t.Fatalf("WatchConfigMap() error = %v", err)
+	}
+
+	waitFor := func(desc string, check func(cm *v1.ConfigMap) bool) {
+		t.Helper()
+		select {
+		case cm := <-changes:
+			if !check(cm) {
+				t.Errorf("Unexpected ConfigMap on %s: %+v", desc, cm)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", desc)
+		}
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: DefaultConfigNamespace},
+		Data:       map[string]string{"key": "v1"},
+	}
+	if _, err := client.CoreV1().ConfigMaps(DefaultConfigNamespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	waitFor("create", func(cm *v1.ConfigMap) bool { return cm != nil && cm.Data["key"] == "v1" })
+
+	cm.Data["key"] = "v2"
+	if _, err := client.CoreV1().ConfigMaps(DefaultConfigNamespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	waitFor("update", func(cm *v1.ConfigMap) bool { return cm != nil && cm.Data["key"] == "v2" })
+
+	if err := client.CoreV1().ConfigMaps(DefaultConfigNamespace).Delete(ctx, "policy", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	waitFor("delete", func(cm *v1.ConfigMap) bool { return cm == nil })
+}
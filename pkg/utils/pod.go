@@ -38,6 +38,10 @@ const (
 	LegacyPodGroupNameLabel         = "pod-group.scheduling.sigs.k8s.io/name"
 	LegacyPodGroupMinAvailableLabel = "pod-group.scheduling.sigs.k8s.io/min-available"
 
+	// AnnotationPodGroup is the pod annotation naming a pod's gang, as read by
+	// NetworkFabricScore, VRAMScheduler and the snapshot index
+	AnnotationPodGroup = "pod-group.scheduling.sigs.k8s.io/name"
+
 	// Native K8s 1.35+ Workload labels
 	NativeWorkloadNameLabel = "scheduling.k8s.io/workload-name"
 	NativePodGroupLabel     = "scheduling.k8s.io/pod-group"
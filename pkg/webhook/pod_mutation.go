@@ -22,12 +22,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -51,13 +50,14 @@ const (
 
 // PodMutator handles pod mutation for deterministic autoscaling
 type PodMutator struct {
-	clientset kubernetes.Interface
+	namespaceLister corelisters.NamespaceLister
 }
 
-// NewPodMutator creates a new PodMutator
-func NewPodMutator(clientset kubernetes.Interface) *PodMutator {
+// NewPodMutator creates a new PodMutator. Namespace tiers are read from the
+// informer-backed lister, so admission never blocks on an API round trip.
+func NewPodMutator(namespaceLister corelisters.NamespaceLister) *PodMutator {
 	return &PodMutator{
-		clientset: clientset,
+		namespaceLister: namespaceLister,
 	}
 }
 
@@ -155,7 +155,7 @@ func (pm *PodMutator) mutate(ctx context.Context, req *admissionv1.AdmissionRequ
 	}
 
 	// Get namespace tier
-	tier, err := pm.getNamespaceTier(ctx, req.Namespace)
+	tier, err := pm.getNamespaceTier(req.Namespace)
 	if err != nil {
 		klog.V(4).InfoS("Failed to get namespace tier, allowing without mutation",
 			"namespace", req.Namespace,
@@ -211,10 +211,8 @@ func (pm *PodMutator) mutate(ctx context.Context, req *admissionv1.AdmissionRequ
 }
 
 // getNamespaceTier retrieves the tenant tier from namespace labels
-func (pm *PodMutator) getNamespaceTier(ctx context.Context, namespace string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-	ns, err := pm.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+func (pm *PodMutator) getNamespaceTier(namespace string) (string, error) {
+	ns, err := pm.namespaceLister.Get(namespace)
 	if err != nil {
 		return "", fmt.Errorf("failed to get namespace: %w", err)
 	}
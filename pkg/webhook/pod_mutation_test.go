@@ -21,6 +21,9 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestGetTierGPUClass(t *testing.T) {
@@ -169,3 +172,38 @@ func TestEscapeJSONPointer(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNamespaceTier(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "premium", Labels: map[string]string{TenantTierLabel: "Gold"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "untiered"}},
+	} {
+		if err := indexer.Add(ns); err != nil {
+			t.Fatalf("Failed to add namespace: %v", err)
+		}
+	}
+	pm := NewPodMutator(corelisters.NewNamespaceLister(indexer))
+
+	tests := []struct {
+		namespace string
+		expected  string
+		wantErr   bool
+	}{
+		{namespace: "premium", expected: TierGold},
+		{namespace: "untiered", wantErr: true},
+		{namespace: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			tier, err := pm.getNamespaceTier(tt.namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getNamespaceTier(%q) error = %v, wantErr %v", tt.namespace, err, tt.wantErr)
+			}
+			if tier != tt.expected {
+				t.Errorf("getNamespaceTier(%q) = %q, want %q", tt.namespace, tier, tt.expected)
+			}
+		})
+	}
+}